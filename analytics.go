@@ -0,0 +1,53 @@
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+// The maximum number of dimensions allowed on an event.
+const maxEventDimensions = 8
+
+var errEmptyEventName = errors.New("parse: cannot use empty event name")
+
+type event struct {
+	Dimensions map[string]string `json:"dimensions,omitempty"`
+	At         *Date             `json:"at,omitempty"`
+}
+
+// AnalyticsClient sends analytics events.
+type AnalyticsClient struct {
+	Client *Client
+}
+
+func (c *AnalyticsClient) send(name string, dimensions map[string]string, at time.Time) error {
+	e := event{Dimensions: dimensions}
+	if !at.IsZero() {
+		d := Date(at)
+		e.At = &d
+	}
+	_, err := c.Client.Post(&url.URL{Path: "events/" + name}, &e, nil)
+	return err
+}
+
+// AppOpened tracks an application being opened at the given time. A zero time
+// means now.
+func (c *AnalyticsClient) AppOpened(at time.Time) error {
+	return c.send("AppOpened", nil, at)
+}
+
+// Track tracks a custom event with the given dimensions at the given time. A
+// zero time means now.
+func (c *AnalyticsClient) Track(name string, dimensions map[string]string, at time.Time) error {
+	if name == "" {
+		return errEmptyEventName
+	}
+	if len(dimensions) > maxEventDimensions {
+		return fmt.Errorf(
+			"parse: event has %d dimensions which exceeds the limit of %d",
+			len(dimensions), maxEventDimensions)
+	}
+	return c.send(name, dimensions, at)
+}
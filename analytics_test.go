@@ -0,0 +1,62 @@
+package parse_test
+
+import (
+	"net/http"
+	"regexp"
+	"strconv"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestAnalyticsAppOpened(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Method, "POST")
+			ensure.DeepEqual(t, r.URL.Path, "/1/events/AppOpened")
+			var m map[string]interface{}
+			decodeBody(t, r, &m)
+			ensure.DeepEqual(t, m, map[string]interface{}{})
+			return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+		}),
+	}
+	ac := parse.AnalyticsClient{Client: c}
+	ensure.Nil(t, ac.AppOpened(time.Time{}))
+}
+
+func TestAnalyticsTrack(t *testing.T) {
+	t.Parallel()
+	at := time.Date(2015, 6, 16, 1, 2, 3, 0, time.UTC)
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.URL.Path, "/1/events/Yak")
+			var m map[string]interface{}
+			decodeBody(t, r, &m)
+			ensure.DeepEqual(t, m, map[string]interface{}{
+				"dimensions": map[string]interface{}{"herd": "north"},
+				"at": map[string]interface{}{
+					"__type": "Date",
+					"iso":    "2015-06-16T01:02:03.000Z",
+				},
+			})
+			return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+		}),
+	}
+	ac := parse.AnalyticsClient{Client: c}
+	ensure.Nil(t, ac.Track("Yak", map[string]string{"herd": "north"}, at))
+}
+
+func TestAnalyticsTrackInvalid(t *testing.T) {
+	t.Parallel()
+	ac := parse.AnalyticsClient{Client: &parse.Client{}}
+	ensure.Err(t, ac.Track("", nil, time.Time{}), regexp.MustCompile("empty event name"))
+	dimensions := make(map[string]string)
+	for i := 0; i < 9; i++ {
+		dimensions[strconv.Itoa(i)] = "a"
+	}
+	ensure.Err(t, ac.Track("Yak", dimensions, time.Time{}),
+		regexp.MustCompile("9 dimensions which exceeds the limit of 8"))
+}
@@ -0,0 +1,52 @@
+package parse
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+)
+
+// Doer performs Parse API calls. It is implemented by Client, and lets
+// application code accept a fake in tests.
+type Doer interface {
+	Do(req *http.Request, body, result interface{}) (*http.Response, error)
+	DoCtx(ctx context.Context, req *http.Request, body, result interface{}) (*http.Response, error)
+}
+
+// API is the method set of Client for making calls. It is implemented by
+// Client, and by parsetest.Mock for tests.
+type API interface {
+	Doer
+	Get(u *url.URL, result interface{}) (*http.Response, error)
+	Post(u *url.URL, body, result interface{}) (*http.Response, error)
+	Put(u *url.URL, body, result interface{}) (*http.Response, error)
+	Delete(u *url.URL, result interface{}) (*http.Response, error)
+	GetCtx(ctx context.Context, u *url.URL, result interface{}) (*http.Response, error)
+	PostCtx(ctx context.Context, u *url.URL, body, result interface{}) (*http.Response, error)
+	PutCtx(ctx context.Context, u *url.URL, body, result interface{}) (*http.Response, error)
+	DeleteCtx(ctx context.Context, u *url.URL, result interface{}) (*http.Response, error)
+}
+
+// Objects has the single object and query methods of ObjectClient. It is
+// implemented by ObjectClient, and by parsetest.MockObjects for tests.
+type Objects interface {
+	Get(id string, result interface{}) (*http.Response, error)
+	GetCtx(ctx context.Context, id string, result interface{}) (*http.Response, error)
+	GetWithOptions(id string, opt *GetOptions, result interface{}) (*http.Response, error)
+	GetWithOptionsCtx(ctx context.Context, id string, opt *GetOptions, result interface{}) (*http.Response, error)
+	Post(v interface{}) (*CreateResponse, error)
+	PostCtx(ctx context.Context, v interface{}) (*CreateResponse, error)
+	Put(id string, v interface{}) (*UpdateResponse, error)
+	PutCtx(ctx context.Context, id string, v interface{}) (*UpdateResponse, error)
+	Delete(id string) (*http.Response, error)
+	DeleteCtx(ctx context.Context, id string) (*http.Response, error)
+	Count(where interface{}) (int, error)
+	CountCtx(ctx context.Context, where interface{}) (int, error)
+	Query(p *Params, result interface{}) (*http.Response, error)
+	QueryCtx(ctx context.Context, p *Params, result interface{}) (*http.Response, error)
+}
+
+var (
+	_ API     = (*Client)(nil)
+	_ Objects = (*ObjectClient)(nil)
+)
@@ -0,0 +1,112 @@
+package parse
+
+import (
+	"encoding/json"
+	"net/url"
+	"time"
+)
+
+const audiencesPath = "push_audiences/"
+
+// Audience is a saved set of installations to target pushes at.
+type Audience struct {
+	ID        string
+	Name      string
+	Query     Where
+	CreatedAt *time.Time
+	LastUsed  *time.Time
+	TimesUsed int
+}
+
+// The API stores the query as a JSON encoded string.
+type audienceJSON struct {
+	ID        string     `json:"objectId,omitempty"`
+	Name      string     `json:"name,omitempty"`
+	Query     string     `json:"query,omitempty"`
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+	LastUsed  *time.Time `json:"lastUsed,omitempty"`
+	TimesUsed int        `json:"timesUsed,omitempty"`
+}
+
+// MarshalJSON encodes the Audience.
+func (a Audience) MarshalJSON() ([]byte, error) {
+	j := audienceJSON{
+		ID:        a.ID,
+		Name:      a.Name,
+		CreatedAt: a.CreatedAt,
+		LastUsed:  a.LastUsed,
+		TimesUsed: a.TimesUsed,
+	}
+	if a.Query != nil {
+		q, err := json.Marshal(a.Query)
+		if err != nil {
+			return nil, err
+		}
+		j.Query = string(q)
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON decodes the Audience.
+func (a *Audience) UnmarshalJSON(b []byte) error {
+	var j audienceJSON
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	a.ID = j.ID
+	a.Name = j.Name
+	a.CreatedAt = j.CreatedAt
+	a.LastUsed = j.LastUsed
+	a.TimesUsed = j.TimesUsed
+	a.Query = nil
+	if j.Query != "" {
+		return json.Unmarshal([]byte(j.Query), &a.Query)
+	}
+	return nil
+}
+
+// AudiencesClient manages saved push audiences. It requires the Master Key.
+type AudiencesClient struct {
+	Client *Client
+}
+
+func (c *AudiencesClient) objects() *ObjectClient {
+	return &ObjectClient{Client: c.Client, Path: audiencesPath}
+}
+
+// Create saves a new audience. Only the Name and Query are sent.
+func (c *AudiencesClient) Create(a *Audience) (*CreateResponse, error) {
+	return c.objects().Post(Audience{Name: a.Name, Query: a.Query})
+}
+
+// Get fetches the audience with the given ID.
+func (c *AudiencesClient) Get(id string) (*Audience, error) {
+	var a Audience
+	if _, err := c.objects().Get(id, &a); err != nil {
+		return nil, err
+	}
+	return &a, nil
+}
+
+// List fetches all saved audiences.
+func (c *AudiencesClient) List() ([]*Audience, error) {
+	var res struct {
+		Results []*Audience `json:"results"`
+	}
+	if _, err := c.Client.Get(&url.URL{Path: audiencesPath}, &res); err != nil {
+		return nil, err
+	}
+	return res.Results, nil
+}
+
+// Update changes the Name and Query of the audience with the given ID. Empty
+// values are left unchanged.
+func (c *AudiencesClient) Update(id string, a *Audience) (*UpdateResponse, error) {
+	return c.objects().Put(id, Audience{Name: a.Name, Query: a.Query})
+}
+
+// Delete removes the audience with the given ID.
+func (c *AudiencesClient) Delete(id string) error {
+	_, err := c.objects().Delete(id)
+	return err
+}
@@ -0,0 +1,102 @@
+package parse_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestAudiencesCreate(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Method, "POST")
+			ensure.DeepEqual(t, r.URL.Path, "/1/push_audiences/")
+			var m map[string]interface{}
+			decodeBody(t, r, &m)
+			ensure.DeepEqual(t, m, map[string]interface{}{
+				"name":  "iOS",
+				"query": `{"deviceType":"ios"}`,
+			})
+			return jsonResponse(t, http.StatusCreated, map[string]string{
+				"objectId": "xyz",
+			}), nil
+		}),
+	}
+	ac := parse.AudiencesClient{Client: c}
+	res, err := ac.Create(&parse.Audience{
+		ID:        "ignored",
+		Name:      "iOS",
+		Query:     parse.Where{}.EqualTo("deviceType", "ios"),
+		TimesUsed: 3,
+	})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res.ID, "xyz")
+}
+
+func TestAudiencesList(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Method, "GET")
+			ensure.DeepEqual(t, r.URL.Path, "/1/push_audiences/")
+			return jsonResponse(t, http.StatusOK, map[string]interface{}{
+				"results": []interface{}{
+					map[string]interface{}{
+						"objectId":  "xyz",
+						"name":      "iOS",
+						"query":     `{"deviceType":"ios"}`,
+						"timesUsed": 2,
+					},
+				},
+			}), nil
+		}),
+	}
+	ac := parse.AudiencesClient{Client: c}
+	audiences, err := ac.List()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, audiences, []*parse.Audience{
+		{
+			ID:        "xyz",
+			Name:      "iOS",
+			Query:     parse.Where{"deviceType": "ios"},
+			TimesUsed: 2,
+		},
+	})
+}
+
+func TestAudiencesGetUpdateDelete(t *testing.T) {
+	t.Parallel()
+	var methods []string
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			methods = append(methods, r.Method)
+			ensure.DeepEqual(t, r.URL.Path, "/1/push_audiences/xyz")
+			return jsonResponse(t, http.StatusOK, map[string]string{
+				"objectId": "xyz",
+				"name":     "iOS",
+			}), nil
+		}),
+	}
+	ac := parse.AudiencesClient{Client: c}
+	a, err := ac.Get("xyz")
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, a, &parse.Audience{ID: "xyz", Name: "iOS"})
+	_, err = ac.Update("xyz", &parse.Audience{Name: "Apple"})
+	ensure.Nil(t, err)
+	ensure.Nil(t, ac.Delete("xyz"))
+	ensure.DeepEqual(t, methods, []string{"GET", "PUT", "DELETE"})
+}
+
+func TestPushBuilderAudience(t *testing.T) {
+	t.Parallel()
+	p, err := new(parse.PushBuilder).
+		Audience(&parse.Audience{ID: "xyz", Query: parse.Where{"deviceType": "ios"}}).
+		Alert("hello").
+		Build()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, p.AudienceID, "xyz")
+	ensure.DeepEqual(t, p.Where, parse.Where{"deviceType": "ios"})
+}
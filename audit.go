@@ -0,0 +1,192 @@
+package parse
+
+import (
+	"bytes"
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sort"
+	"strings"
+	"time"
+)
+
+// The classes of the endpoints other than classes/.
+var endpointClasses = map[string]string{
+	"users":         "_User",
+	"roles":         "_Role",
+	"installations": "_Installation",
+	"sessions":      "_Session",
+}
+
+type auditActorKey struct{}
+
+// WithAuditActor returns a context that records actor, such as the name of the
+// user on whose behalf a service acts, as the Actor of the audit records of
+// the requests made with it.
+func WithAuditActor(ctx context.Context, actor string) context.Context {
+	return context.WithValue(ctx, auditActorKey{}, actor)
+}
+
+// AuditRecord describes a POST, PUT or DELETE request. Every operation of a
+// batch request gets its own record.
+type AuditRecord struct {
+	Time   time.Time
+	Method string
+
+	// Endpoint is the API without object IDs, such as "classes/Yak" or
+	// "functions/hello".
+	Endpoint string
+
+	// ClassName of the changed object, if any.
+	ClassName string
+
+	// ObjectID of the changed object, or the object created by a POST.
+	ObjectID string
+
+	// Actor is given to WithAuditActor.
+	Actor string
+
+	// Credentials is the kind of credentials used, one of "master key",
+	// "session token", "rest api key" and "javascript key", or empty.
+	Credentials string
+
+	// Session identifies the session token used without revealing it. It is
+	// the first 16 hexadecimal digits of the SHA-256 of the token.
+	Session string
+
+	// Fields are the sorted names of the fields in the request body.
+	Fields []string
+
+	// StatusCode of the response, which for an operation of a batch is the
+	// status of the batch request.
+	StatusCode int
+
+	// Err is the error of the request, or of the operation of a batch.
+	Err error
+}
+
+// AuditSink receives an AuditRecord for every POST, PUT and DELETE request of
+// a Client once it completed, successfully or not. It is called concurrently
+// when the Client is.
+type AuditSink interface {
+	Audit(AuditRecord)
+}
+
+// AuditSinkFunc is an AuditSink implemented by a function.
+type AuditSinkFunc func(AuditRecord)
+
+// Audit calls f(r).
+func (f AuditSinkFunc) Audit(r AuditRecord) {
+	f(r)
+}
+
+func (c *Client) auditRequest(cl *call, req *http.Request) error {
+	switch cl.info.Method {
+	case "POST", "PUT", "DELETE":
+	default:
+		return nil
+	}
+	var body []byte
+	if req.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+	}
+
+	r := AuditRecord{
+		Time:   cl.start,
+		Method: cl.info.Method,
+	}
+	if a, ok := cl.info.Context.Value(auditActorKey{}).(string); ok {
+		r.Actor = a
+	}
+	switch {
+	case req.Header.Get(masterKeyHeader) != "":
+		r.Credentials = "master key"
+	case req.Header.Get(sessionTokenHeader) != "":
+		r.Credentials = "session token"
+	case req.Header.Get(restAPIKeyHeader) != "":
+		r.Credentials = "rest api key"
+	case req.Header.Get(javaScriptKeyHeader) != "":
+		r.Credentials = "javascript key"
+	}
+	if t := req.Header.Get(sessionTokenHeader); t != "" {
+		sum := sha256.Sum256([]byte(t))
+		r.Session = hex.EncodeToString(sum[:8])
+	}
+
+	if cl.info.Endpoint == "batch" && cl.info.Method == "POST" {
+		var batch struct {
+			Requests []struct {
+				Method string          `json:"method"`
+				Path   string          `json:"path"`
+				Body   json.RawMessage `json:"body"`
+			} `json:"requests"`
+		}
+		if json.Unmarshal(body, &batch) == nil {
+			for _, op := range batch.Requests {
+				opr := r
+				opr.Method = strings.ToUpper(op.Method)
+				c.auditTarget(&opr, &url.URL{Path: op.Path}, op.Body)
+				cl.writes = append(cl.writes, opr)
+			}
+			return nil
+		}
+	}
+	c.auditTarget(&r, req.URL, body)
+	cl.writes = append(cl.writes, r)
+	return nil
+}
+
+// auditTarget sets the endpoint, object and fields of a record.
+func (c *Client) auditTarget(r *AuditRecord, u *url.URL, body []byte) {
+	r.Endpoint, r.ObjectID = c.splitPath(u)
+	if strings.HasPrefix(r.Endpoint, "classes/") {
+		r.ClassName = r.Endpoint[len("classes/"):]
+	} else {
+		r.ClassName = endpointClasses[r.Endpoint]
+	}
+	var o map[string]json.RawMessage
+	if len(body) != 0 && json.Unmarshal(body, &o) == nil {
+		for k := range o {
+			r.Fields = append(r.Fields, k)
+		}
+		sort.Strings(r.Fields)
+	}
+}
+
+func (c *Client) auditResponse(cl *call, info ResponseInfo, body []byte) {
+	var ops []BatchResponse
+	if len(cl.writes) > 1 || cl.info.Endpoint == "batch" {
+		if info.Err != nil || json.Unmarshal(body, &ops) != nil || len(ops) != len(cl.writes) {
+			ops = nil
+		}
+	}
+	for n, r := range cl.writes {
+		r.StatusCode = info.StatusCode
+		r.Err = info.Err
+		switch {
+		case ops != nil:
+			if ops[n].Error != nil {
+				r.Err = ops[n].Error
+			}
+			var o struct {
+				ID string `json:"objectId"`
+			}
+			if json.Unmarshal(ops[n].Success, &o) == nil && o.ID != "" {
+				r.ObjectID = o.ID
+			}
+		case cl.info.Endpoint != "batch":
+			r.ObjectID = info.ObjectID
+		}
+		c.Audit.Audit(r)
+	}
+}
@@ -0,0 +1,197 @@
+// Package audit scans a Parse application for security problems: objects
+// with public write ACLs, classes with open class level permissions and
+// fields that look like they contain credentials.
+package audit
+
+import (
+	"net/url"
+	"regexp"
+	"sort"
+	"strconv"
+
+	"github.com/facebookgo/parse"
+)
+
+const defaultSampleSize = 100
+
+// Kinds of findings reported by the Scanner.
+const (
+	PublicWriteACL      = "public-write-acl"
+	OpenCLP             = "open-clp"
+	SuspectedCredential = "suspected-credential"
+)
+
+var (
+	// Field names that suggest a credential is being stored.
+	credentialName = regexp.MustCompile(
+		`(?i)(passw(or)?d|secret|token|api_?key|private_?key|credential)`)
+
+	// Values that look like well known credential formats.
+	credentialValue = regexp.MustCompile(
+		`AKIA[0-9A-Z]{16}|-----BEGIN [A-Z ]*PRIVATE KEY-----|sk_live_[0-9a-zA-Z]{16,}`)
+
+	// Class level permissions that allow modifying data or the schema.
+	writeOperations = []string{"create", "update", "delete", "addField"}
+
+	// Built in fields that are expected to hold credentials.
+	systemCredentialFields = map[string]map[string]bool{
+		"_User":         {"password": true, "sessionToken": true},
+		"_Session":      {"sessionToken": true},
+		"_Installation": {"deviceToken": true},
+	}
+)
+
+// A Finding is a single problem found by the Scanner.
+type Finding struct {
+	Kind      string `json:"kind"`
+	Class     string `json:"class"`
+	ObjectID  string `json:"objectId,omitempty"`
+	Field     string `json:"field,omitempty"`
+	Operation string `json:"operation,omitempty"`
+	Detail    string `json:"detail"`
+}
+
+// A Report is the machine readable result of a scan. It is intended to be
+// serialized as JSON.
+type Report struct {
+	Classes  []string  `json:"classes"`
+	Findings []Finding `json:"findings"`
+}
+
+// Scanner walks all classes in an application. The Client must be configured
+// with the Master Key as the schema API and ACLs are only available to it.
+type Scanner struct {
+	Client *parse.Client
+
+	// The number of objects sampled from each class. When zero
+	// defaultSampleSize will be used.
+	SampleSize int
+}
+
+func (s *Scanner) sampleSize() int {
+	if s.SampleSize == 0 {
+		return defaultSampleSize
+	}
+	return s.SampleSize
+}
+
+// Scan walks all classes and reports the problems found. Findings are sorted
+// by class.
+func (s *Scanner) Scan() (*Report, error) {
+	sc := parse.SchemaClient{Client: s.Client}
+	schemas, err := sc.List()
+	if err != nil {
+		return nil, err
+	}
+	sort.Sort(byClassName(schemas))
+
+	report := &Report{Classes: []string{}, Findings: []Finding{}}
+	for _, sc := range schemas {
+		report.Classes = append(report.Classes, sc.ClassName)
+		report.Findings = append(report.Findings, scanSchema(sc)...)
+		findings, err := s.scanObjects(sc)
+		if err != nil {
+			return nil, err
+		}
+		report.Findings = append(report.Findings, findings...)
+	}
+	return report, nil
+}
+
+func scanSchema(sc *parse.Schema) []Finding {
+	var findings []Finding
+	for _, op := range writeOperations {
+		if p := sc.ClassLevelPermissions.Operation(op); p != nil && p.Public {
+			findings = append(findings, Finding{
+				Kind:      OpenCLP,
+				Class:     sc.ClassName,
+				Operation: op,
+				Detail:    "class level permission allows public " + op,
+			})
+		}
+	}
+
+	names := make([]string, 0, len(sc.Fields))
+	for name := range sc.Fields {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		if systemCredentialFields[sc.ClassName][name] {
+			continue
+		}
+		if credentialName.MatchString(name) {
+			findings = append(findings, Finding{
+				Kind:   SuspectedCredential,
+				Class:  sc.ClassName,
+				Field:  name,
+				Detail: "field name suggests it contains a credential",
+			})
+		}
+	}
+	return findings
+}
+
+func (s *Scanner) scanObjects(sc *parse.Schema) ([]Finding, error) {
+	u := url.URL{
+		Path:     "classes/" + sc.ClassName,
+		RawQuery: url.Values{"limit": {strconv.Itoa(s.sampleSize())}}.Encode(),
+	}
+	var objects struct {
+		Results []map[string]interface{} `json:"results"`
+	}
+	if _, err := s.Client.Get(&u, &objects); err != nil {
+		return nil, err
+	}
+
+	var findings []Finding
+	for _, o := range objects.Results {
+		id, _ := o["objectId"].(string)
+		if publicWrite(o["ACL"]) {
+			findings = append(findings, Finding{
+				Kind:     PublicWriteACL,
+				Class:    sc.ClassName,
+				ObjectID: id,
+				Detail:   "object can be modified by anyone",
+			})
+		}
+
+		keys := make([]string, 0, len(o))
+		for k := range o {
+			keys = append(keys, k)
+		}
+		sort.Strings(keys)
+		for _, k := range keys {
+			if systemCredentialFields[sc.ClassName][k] {
+				continue
+			}
+			if v, ok := o[k].(string); ok && credentialValue.MatchString(v) {
+				findings = append(findings, Finding{
+					Kind:     SuspectedCredential,
+					Class:    sc.ClassName,
+					ObjectID: id,
+					Field:    k,
+					Detail:   "value looks like a credential",
+				})
+			}
+		}
+	}
+	return findings, nil
+}
+
+// publicWrite reports if the raw ACL allows public writes. A missing ACL
+// allows anyone to read and write the object.
+func publicWrite(v interface{}) bool {
+	m, ok := v.(map[string]interface{})
+	if !ok {
+		return true
+	}
+	public, ok := m["*"].(map[string]interface{})
+	return ok && public["write"] == true
+}
+
+type byClassName []*parse.Schema
+
+func (s byClassName) Len() int           { return len(s) }
+func (s byClassName) Less(i, j int) bool { return s[i].ClassName < s[j].ClassName }
+func (s byClassName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
@@ -0,0 +1,103 @@
+package parse_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestAuditSink(t *testing.T) {
+	t.Parallel()
+	var records []parse.AuditRecord
+	c := &parse.Client{
+		Credentials: parse.SessionToken{ApplicationID: "app", RestAPIKey: "key", SessionToken: "r:token"},
+		Audit:       parse.AuditSinkFunc(func(r parse.AuditRecord) { records = append(records, r) }),
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			if r.Method != "GET" && r.Method != "DELETE" {
+				decodeBody(t, r, &body)
+			}
+			switch {
+			case r.Method == "POST":
+				ensure.DeepEqual(t, body["name"], "Al")
+				return jsonResponse(t, http.StatusCreated, map[string]string{"objectId": "new1"}), nil
+			case r.Method == "PUT":
+				return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+			case r.Method == "DELETE":
+				return jsonResponse(t, http.StatusNotFound, map[string]interface{}{"code": 101, "error": "object not found"}), nil
+			}
+			return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+		}),
+	}
+	ctx := parse.WithAuditActor(context.Background(), "al@example.com")
+	_, err := c.PostCtx(ctx, &url.URL{Path: "classes/Yak"}, map[string]string{"name": "Al", "color": "brown"}, nil)
+	ensure.Nil(t, err)
+	_, err = c.Put(&url.URL{Path: "users/u1"}, map[string]string{"email": "al@example.com"}, nil)
+	ensure.Nil(t, err)
+	_, err = c.Delete(&url.URL{Path: "classes/Yak/y1"}, nil)
+	ensure.NotNil(t, err)
+	_, err = c.Get(&url.URL{Path: "classes/Yak/y1"}, nil)
+	ensure.Nil(t, err)
+
+	ensure.DeepEqual(t, len(records), 3)
+	for _, r := range records {
+		ensure.False(t, r.Time.IsZero())
+		ensure.True(t, time.Since(r.Time) < time.Minute)
+		ensure.DeepEqual(t, r.Credentials, "session token")
+		ensure.DeepEqual(t, len(r.Session), 16)
+		ensure.DeepEqual(t, r.Session, records[0].Session)
+	}
+	ensure.DeepEqual(t, records[0].Actor, "al@example.com")
+	ensure.DeepEqual(t, records[0].Method, "POST")
+	ensure.DeepEqual(t, records[0].ClassName, "Yak")
+	ensure.DeepEqual(t, records[0].ObjectID, "new1")
+	ensure.DeepEqual(t, records[0].Fields, []string{"color", "name"})
+	ensure.DeepEqual(t, records[0].StatusCode, http.StatusCreated)
+	ensure.DeepEqual(t, records[1].Actor, "")
+	ensure.DeepEqual(t, records[1].ClassName, "_User")
+	ensure.DeepEqual(t, records[1].ObjectID, "u1")
+	ensure.DeepEqual(t, records[1].Fields, []string{"email"})
+	ensure.DeepEqual(t, records[2].Method, "DELETE")
+	ensure.DeepEqual(t, records[2].ObjectID, "y1")
+	ensure.DeepEqual(t, records[2].Err, &parse.Error{
+		Code:       101,
+		Message:    "object not found",
+		StatusCode: http.StatusNotFound,
+		URL:        "https://api.parse.com/1/classes/Yak/y1",
+	})
+}
+
+func TestAuditSinkBatch(t *testing.T) {
+	t.Parallel()
+	var records []parse.AuditRecord
+	c := &parse.Client{
+		Credentials: parse.MasterKey{ApplicationID: "app", MasterKey: "master"},
+		Audit:       parse.AuditSinkFunc(func(r parse.AuditRecord) { records = append(records, r) }),
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			return jsonResponse(t, http.StatusOK, []interface{}{
+				map[string]interface{}{"success": map[string]string{"objectId": "new1"}},
+				map[string]interface{}{"error": map[string]interface{}{"code": 101, "error": "object not found"}},
+			}), nil
+		}),
+	}
+	_, err := c.Batch([]parse.BatchRequest{
+		{Method: "POST", Path: "classes/Yak", Body: map[string]string{"name": "Al"}},
+		{Method: "DELETE", Path: "classes/Yak/y1"},
+	})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(records), 2)
+	ensure.DeepEqual(t, records[0].Credentials, "master key")
+	ensure.DeepEqual(t, records[0].Method, "POST")
+	ensure.DeepEqual(t, records[0].ClassName, "Yak")
+	ensure.DeepEqual(t, records[0].ObjectID, "new1")
+	ensure.DeepEqual(t, records[0].Fields, []string{"name"})
+	ensure.Nil(t, records[0].Err)
+	ensure.DeepEqual(t, records[1].Method, "DELETE")
+	ensure.DeepEqual(t, records[1].ObjectID, "y1")
+	ensure.DeepEqual(t, records[1].Err, &parse.Error{Code: 101, Message: "object not found"})
+}
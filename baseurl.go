@@ -0,0 +1,45 @@
+package parse
+
+import (
+	"fmt"
+	"net/url"
+	"strings"
+)
+
+// ParseBaseURL parses the URL of a Parse Server to be used as the Client
+// BaseURL, such as https://example.com:1337/parse. It must be an absolute http
+// or https URL without a query. A trailing slash is added to the mount path,
+// since without one relative URLs resolve outside of it.
+func ParseBaseURL(raw string) (*url.URL, error) {
+	u, err := url.Parse(raw)
+	if err != nil {
+		return nil, err
+	}
+	if (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+		return nil, fmt.Errorf("parse: base URL %q must be an absolute http or https URL", raw)
+	}
+	if u.RawQuery != "" || u.Fragment != "" {
+		return nil, fmt.Errorf("parse: base URL %q cannot have a query or fragment", raw)
+	}
+	return withTrailingSlash(u), nil
+}
+
+func withTrailingSlash(u *url.URL) *url.URL {
+	if strings.HasSuffix(u.Path, "/") {
+		return u
+	}
+	u2 := *u
+	u2.Path += "/"
+	if u2.RawPath != "" {
+		u2.RawPath += "/"
+	}
+	return &u2
+}
+
+// baseURL returns the BaseURL, or the default one, ending with a slash.
+func (c *Client) baseURL() *url.URL {
+	if c.BaseURL == nil {
+		return &defaultBaseURL
+	}
+	return withTrailingSlash(c.BaseURL)
+}
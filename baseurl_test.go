@@ -0,0 +1,66 @@
+package parse_test
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestParseBaseURL(t *testing.T) {
+	t.Parallel()
+	cases := map[string]string{
+		"https://example.com":             "https://example.com/",
+		"https://example.com:1337/parse":  "https://example.com:1337/parse/",
+		"http://localhost:1337/parse/":    "http://localhost:1337/parse/",
+		"https://example.com/apps/yak/v1": "https://example.com/apps/yak/v1/",
+	}
+	for raw, expected := range cases {
+		u, err := parse.ParseBaseURL(raw)
+		ensure.Nil(t, err)
+		ensure.DeepEqual(t, u.String(), expected)
+	}
+}
+
+func TestParseBaseURLInvalid(t *testing.T) {
+	t.Parallel()
+	cases := map[string]string{
+		"example.com/parse":             "absolute http or https URL",
+		"/parse/":                       "absolute http or https URL",
+		"ftp://example.com/parse":       "absolute http or https URL",
+		"https://example.com/parse?a=b": "cannot have a query",
+		"https://example.com/parse#a":   "cannot have a query",
+		"https://exa mple.com":          "invalid",
+	}
+	for raw, expected := range cases {
+		_, err := parse.ParseBaseURL(raw)
+		ensure.Err(t, err, regexp.MustCompile(expected))
+	}
+}
+
+func TestBaseURLWithoutTrailingSlash(t *testing.T) {
+	t.Parallel()
+	var urls []string
+	c := &parse.Client{
+		BaseURL: &url.URL{Scheme: "http", Host: "localhost:1337", Path: "/parse"},
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			urls = append(urls, r.URL.String())
+			return jsonResponse(t, http.StatusOK, []parse.BatchResponse{{}}), nil
+		}),
+	}
+	_, err := c.Get(&url.URL{Path: "classes/Yak/abc"}, nil)
+	ensure.Nil(t, err)
+	_, err = c.Get(nil, nil)
+	ensure.Nil(t, err)
+	_, err = c.Batch([]parse.BatchRequest{{Method: "DELETE", Path: "classes/Yak/abc"}})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, urls, []string{
+		"http://localhost:1337/parse/classes/Yak/abc",
+		"http://localhost:1337/parse/",
+		"http://localhost:1337/parse/batch",
+	})
+	ensure.DeepEqual(t, c.BaseURL.Path, "/parse")
+}
@@ -0,0 +1,86 @@
+package parse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/url"
+)
+
+// MaxBatchSize is the maximum number of operations allowed in a single batch.
+const MaxBatchSize = 50
+
+var errBatchTooLarge = fmt.Errorf("parse: batch cannot contain more than %d operations", MaxBatchSize)
+
+var errBatchResponseMismatch = errors.New("parse: batch response does not match requests")
+
+// BatchRequest is a single operation in a batch.
+type BatchRequest struct {
+	Method string `json:"method"`
+
+	// Path of the operation relative to the Client BaseURL, such as
+	// "classes/GameScore/Ed1nuqPvcm".
+	Path string `json:"path"`
+
+	Body interface{} `json:"body,omitempty"`
+}
+
+// BatchResponse is the outcome of a single operation in a batch. Exactly one
+// of Success and Error will be set.
+type BatchResponse struct {
+	Success json.RawMessage `json:"success,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Batch performs up to MaxBatchSize operations in a single request. The
+// returned responses correspond to the requests by index. Each operation
+// succeeds or fails on its own, so the returned error only reports failure of
+// the batch request as a whole.
+func (c *Client) Batch(reqs []BatchRequest) ([]BatchResponse, error) {
+	return c.batch(reqs, false)
+}
+
+// transactionKey marks the context of a transactional batch, so that the
+// server version can be checked.
+type transactionKey struct{}
+
+// BatchTransaction performs the operations like Batch, in a transaction, so
+// that either all of them succeed or the batch request fails with the error
+// of the operation that failed. It needs Parse Server 3.9.0 or later, with a
+// MongoDB replica set or PostgreSQL.
+func (c *Client) BatchTransaction(reqs []BatchRequest) ([]BatchResponse, error) {
+	ctx := c.ctx
+	if ctx == nil {
+		ctx = context.Background()
+	}
+	return c.WithContext(context.WithValue(ctx, transactionKey{}, true)).batch(reqs, true)
+}
+
+func (c *Client) batch(reqs []BatchRequest, transaction bool) ([]BatchResponse, error) {
+	if len(reqs) == 0 {
+		return nil, nil
+	}
+	if len(reqs) > MaxBatchSize {
+		return nil, errBatchTooLarge
+	}
+
+	base := c.baseURL()
+	body := struct {
+		Requests    []BatchRequest `json:"requests"`
+		Transaction bool           `json:"transaction,omitempty"`
+	}{Requests: make([]BatchRequest, len(reqs)), Transaction: transaction}
+	for i, r := range reqs {
+		r.Path = base.ResolveReference(&url.URL{Path: r.Path}).Path
+		body.Requests[i] = r
+	}
+
+	var res []BatchResponse
+	if _, err := c.Post(&url.URL{Path: "batch"}, body, &res); err != nil {
+		return nil, err
+	}
+	if len(res) != len(reqs) {
+		return nil, errBatchResponseMismatch
+	}
+	return res, nil
+}
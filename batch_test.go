@@ -0,0 +1,95 @@
+package parse_test
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestBatch(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		BaseURL: &url.URL{Scheme: "https", Host: "yaks.example.com", Path: "/parse/"},
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Method, "POST")
+			ensure.DeepEqual(t, r.URL.String(), "https://yaks.example.com/parse/batch")
+			var body map[string]interface{}
+			decodeBody(t, r, &body)
+			ensure.DeepEqual(t, body, map[string]interface{}{
+				"requests": []interface{}{
+					map[string]interface{}{
+						"method": "POST",
+						"path":   "/parse/classes/Yak",
+						"body":   map[string]interface{}{"name": "alpha"},
+					},
+					map[string]interface{}{
+						"method": "DELETE",
+						"path":   "/parse/classes/Yak/b",
+					},
+				},
+			})
+			return jsonResponse(t, http.StatusOK, []interface{}{
+				map[string]interface{}{"success": map[string]string{"objectId": "a"}},
+				map[string]interface{}{"error": map[string]interface{}{"code": 101, "error": "object not found"}},
+			}), nil
+		}),
+	}
+	res, err := c.Batch([]parse.BatchRequest{
+		{Method: "POST", Path: "classes/Yak", Body: map[string]string{"name": "alpha"}},
+		{Method: "DELETE", Path: "classes/Yak/b"},
+	})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(res), 2)
+	ensure.DeepEqual(t, string(res[0].Success), `{"objectId":"a"}`)
+	ensure.True(t, res[0].Error == nil)
+	ensure.DeepEqual(t, res[1].Error, &parse.Error{Code: 101, Message: "object not found"})
+}
+
+func TestBatchTransaction(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			var body map[string]interface{}
+			decodeBody(t, r, &body)
+			ensure.DeepEqual(t, body["transaction"], true)
+			return jsonResponse(t, http.StatusOK, []interface{}{
+				map[string]interface{}{"success": map[string]string{"objectId": "a"}},
+			}), nil
+		}),
+	}
+	res, err := c.BatchTransaction([]parse.BatchRequest{
+		{Method: "POST", Path: "classes/Yak", Body: map[string]string{"name": "alpha"}},
+	})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, string(res[0].Success), `{"objectId":"a"}`)
+}
+
+func TestBatchMismatch(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			return jsonResponse(t, http.StatusOK, []interface{}{}), nil
+		}),
+	}
+	_, err := c.Batch([]parse.BatchRequest{{Method: "DELETE", Path: "classes/Yak/a"}})
+	ensure.Err(t, err, regexp.MustCompile("does not match requests"))
+}
+
+func TestBatchSize(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			panic("unexpected request")
+		}),
+	}
+	res, err := c.Batch(nil)
+	ensure.Nil(t, err)
+	ensure.True(t, res == nil)
+
+	_, err = c.Batch(make([]parse.BatchRequest, parse.MaxBatchSize+1))
+	ensure.Err(t, err, regexp.MustCompile("more than 50 operations"))
+}
@@ -0,0 +1,146 @@
+package parse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+)
+
+const defaultCacheTTL = time.Minute
+
+// Cache keeps responses for ObjectClient. Keys start with the path of the
+// objects, such as "classes/GameScore/", so that Invalidate can drop all
+// cached objects and queries of a class.
+type Cache interface {
+	// Get returns the value of the key, if it is cached and not expired.
+	Get(key string) ([]byte, bool)
+
+	// Set caches the value for the ttl.
+	Set(key string, value []byte, ttl time.Duration)
+
+	// Invalidate removes the keys starting with the prefix.
+	Invalidate(prefix string)
+}
+
+// MemoryCache is an in-memory Cache. The zero value is ready to use and safe
+// for concurrent use.
+type MemoryCache struct {
+	// Clock optionally replaces the real clock for expiry, in tests.
+	Clock Clock
+
+	mu      sync.Mutex
+	entries map[string]memoryCacheEntry
+}
+
+type memoryCacheEntry struct {
+	value   []byte
+	expires time.Time
+}
+
+// Get returns the value of the key.
+func (m *MemoryCache) Get(key string) ([]byte, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	e, ok := m.entries[key]
+	if !ok {
+		return nil, false
+	}
+	if clockOrReal(m.Clock).Now().After(e.expires) {
+		delete(m.entries, key)
+		return nil, false
+	}
+	return e.value, true
+}
+
+// Set caches the value for the ttl.
+func (m *MemoryCache) Set(key string, value []byte, ttl time.Duration) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	if m.entries == nil {
+		m.entries = make(map[string]memoryCacheEntry)
+	}
+	now := clockOrReal(m.Clock).Now()
+	for k, e := range m.entries {
+		if now.After(e.expires) {
+			delete(m.entries, k)
+		}
+	}
+	m.entries[key] = memoryCacheEntry{value: value, expires: now.Add(ttl)}
+}
+
+// Invalidate removes the keys starting with the prefix.
+func (m *MemoryCache) Invalidate(prefix string) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for k := range m.entries {
+		if strings.HasPrefix(k, prefix) {
+			delete(m.entries, k)
+		}
+	}
+}
+
+func (o *ObjectClient) cacheTTL() time.Duration {
+	if o.CacheTTL > 0 {
+		return o.CacheTTL
+	}
+	return defaultCacheTTL
+}
+
+// cacheKey is the URL of the request followed by the credentials it is made
+// with, since ACLs make responses differ between users, and the Partition of
+// the Client.
+func (o *ObjectClient) cacheKey(u *url.URL) (string, bool) {
+	r := &http.Request{Header: make(http.Header)}
+	for k, v := range o.Client.Header {
+		r.Header[k] = v
+	}
+	if o.Client.Credentials != nil {
+		if err := o.Client.Credentials.Modify(r); err != nil {
+			return "", false
+		}
+	}
+	key := u.String() + "#" + credentialsHash(r.Header)
+	if o.Client.Partition != "" {
+		key += "#" + o.Client.Partition
+	}
+	return key, true
+}
+
+// get fetches the URL, using the Cache if there is one. The response is nil
+// when it comes from the Cache.
+func (o *ObjectClient) get(ctx context.Context, u *url.URL, result interface{}) (*http.Response, error) {
+	if o.Cache == nil {
+		return o.Client.GetCtx(ctx, u, result)
+	}
+	key, ok := o.cacheKey(u)
+	if !ok {
+		return o.Client.GetCtx(ctx, u, result)
+	}
+	if b, ok := o.Cache.Get(key); ok {
+		if result == nil {
+			return nil, nil
+		}
+		return nil, o.Client.decode(b, result)
+	}
+	var raw json.RawMessage
+	res, err := o.Client.GetCtx(ctx, u, &raw)
+	if err != nil {
+		return res, err
+	}
+	o.Cache.Set(key, raw, o.cacheTTL())
+	if result == nil {
+		return res, nil
+	}
+	return res, o.Client.decode(raw, result)
+}
+
+// invalidate drops the cached objects and queries after a write.
+func (o *ObjectClient) invalidate() {
+	if o.Cache != nil {
+		o.Cache.Invalidate(o.objectURL("").String())
+	}
+}
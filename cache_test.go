@@ -0,0 +1,125 @@
+package parse_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+// countingObjects returns an ObjectClient with a MemoryCache, that counts the
+// requests per method.
+func countingObjects(t *testing.T, requests map[string]int) *parse.ObjectClient {
+	return &parse.ObjectClient{
+		Path:  "classes/Yak",
+		Cache: &parse.MemoryCache{},
+		Client: &parse.Client{
+			Credentials: defaultRestAPIKey,
+			Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+				requests[r.Method]++
+				return jsonResponse(t, http.StatusOK, map[string]interface{}{
+					"objectId": "abc",
+					"name":     "yak",
+					"results":  []map[string]string{{"objectId": "abc"}},
+					"count":    1,
+				}), nil
+			}),
+		},
+	}
+}
+
+func TestObjectCache(t *testing.T) {
+	t.Parallel()
+	requests := make(map[string]int)
+	o := countingObjects(t, requests)
+	var yak struct{ Name string }
+	res, err := o.Get("abc", &yak)
+	ensure.Nil(t, err)
+	ensure.NotNil(t, res)
+	ensure.DeepEqual(t, yak.Name, "yak")
+
+	yak.Name = ""
+	res, err = o.Get("abc", &yak)
+	ensure.Nil(t, err)
+	ensure.True(t, res == nil)
+	ensure.DeepEqual(t, yak.Name, "yak")
+
+	n, err := o.Count(nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, n, 1)
+	_, err = o.Count(nil)
+	ensure.Nil(t, err)
+	_, err = o.Query(&parse.Params{Limit: 1}, nil)
+	ensure.Nil(t, err)
+	_, err = o.Query(&parse.Params{Limit: 1}, nil)
+	ensure.Nil(t, err)
+	_, err = o.Query(&parse.Params{Limit: 2}, nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, requests["GET"], 4)
+
+	_, err = o.Put("abc", map[string]string{"name": "yak"})
+	ensure.Nil(t, err)
+	_, err = o.Get("abc", nil)
+	ensure.Nil(t, err)
+	_, err = o.Count(nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, requests["GET"], 6)
+
+	_, err = o.Post(map[string]string{"name": "yak"})
+	ensure.Nil(t, err)
+	_, err = o.Count(nil)
+	ensure.Nil(t, err)
+	_, err = o.Delete("abc")
+	ensure.Nil(t, err)
+	_, err = o.Get("abc", nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, requests["GET"], 8)
+}
+
+func TestObjectCachePerCredentials(t *testing.T) {
+	t.Parallel()
+	requests := make(map[string]int)
+	o := countingObjects(t, requests)
+	other := *o
+	other.Client = o.Client.WithCredentials(parse.MasterKey{ApplicationID: defaultApplicationID, MasterKey: "m"})
+	_, err := o.Get("abc", nil)
+	ensure.Nil(t, err)
+	_, err = other.Get("abc", nil)
+	ensure.Nil(t, err)
+	_, err = o.Get("abc", nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, requests["GET"], 2)
+}
+
+func TestObjectCacheTTL(t *testing.T) {
+	t.Parallel()
+	requests := make(map[string]int)
+	o := countingObjects(t, requests)
+	o.CacheTTL = time.Millisecond
+	_, err := o.Get("abc", nil)
+	ensure.Nil(t, err)
+	time.Sleep(2 * time.Millisecond)
+	_, err = o.Get("abc", nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, requests["GET"], 2)
+}
+
+func TestMemoryCache(t *testing.T) {
+	t.Parallel()
+	var m parse.MemoryCache
+	_, ok := m.Get("a")
+	ensure.False(t, ok)
+	m.Set("classes/Yak/a", []byte("1"), time.Minute)
+	m.Set("classes/Yak/b", []byte("2"), time.Minute)
+	m.Set("classes/Yaks/c", []byte("3"), time.Minute)
+	v, ok := m.Get("classes/Yak/a")
+	ensure.True(t, ok)
+	ensure.DeepEqual(t, string(v), "1")
+	m.Invalidate("classes/Yak/")
+	_, ok = m.Get("classes/Yak/b")
+	ensure.False(t, ok)
+	_, ok = m.Get("classes/Yaks/c")
+	ensure.True(t, ok)
+}
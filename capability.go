@@ -0,0 +1,222 @@
+package parse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+)
+
+// ErrUnsupportedByServer is wrapped by the UnsupportedError returned when the
+// server is too old for a feature.
+var ErrUnsupportedByServer = errors.New("parse: feature unsupported by server")
+
+// Feature is an API feature that only newer servers support.
+type Feature string
+
+// The features checked against the server version.
+const (
+	FeatureAggregate    Feature = "aggregate"
+	FeatureTransactions Feature = "transactions"
+	FeatureExcludeKeys  Feature = "excludeKeys"
+	FeatureGraphQL      Feature = "graphQL"
+)
+
+// The first Parse Server version with each feature.
+var featureVersions = map[Feature]string{
+	FeatureAggregate:    "2.7.0",
+	FeatureGraphQL:      "3.5.0",
+	FeatureTransactions: "3.9.0",
+	FeatureExcludeKeys:  "4.5.0",
+}
+
+// UnsupportedError is returned for a feature the server doesn't support.
+type UnsupportedError struct {
+	Feature Feature
+
+	// Version of the server.
+	Version string
+}
+
+func (e *UnsupportedError) Error() string {
+	return fmt.Sprintf("parse: server version %s does not support %s", e.Version, e.Feature)
+}
+
+// Unwrap returns ErrUnsupportedByServer.
+func (e *UnsupportedError) Unwrap() error {
+	return ErrUnsupportedByServer
+}
+
+// capabilitiesFailureTTL is how long a failure to fetch the ServerInfo is
+// kept, so that it isn't fetched again for every request.
+const capabilitiesFailureTTL = time.Minute
+
+// Capabilities caches the ServerInfo of a server, so that its features are
+// only fetched once. The zero value is ready to use and can be shared by the
+// clients of a server.
+//
+// Fetching the ServerInfo requires the Master Key. When it fails, for example
+// for a Client with the REST API Key only, the failure is kept for a minute
+// and requests are sent without being checked.
+type Capabilities struct {
+	mu       sync.Mutex
+	info     *ServerInfo
+	err      error
+	failedAt time.Time
+
+	// fetching is closed when the ServerInfo being fetched is kept.
+	fetching chan struct{}
+}
+
+// Reset forgets the cached ServerInfo, for example after a server upgrade.
+func (cp *Capabilities) Reset() {
+	cp.mu.Lock()
+	cp.info = nil
+	cp.err = nil
+	cp.mu.Unlock()
+}
+
+// serverInfo returns the kept ServerInfo, or fetches it. Concurrent callers
+// wait for a single fetch, without holding the lock during the request.
+func (cp *Capabilities) serverInfo(ctx context.Context, c *Client) (*ServerInfo, error) {
+	for {
+		cp.mu.Lock()
+		if cp.info != nil {
+			cp.mu.Unlock()
+			return cp.info, nil
+		}
+		if cp.err != nil && c.clock().Now().Sub(cp.failedAt) < capabilitiesFailureTTL {
+			err := cp.err
+			cp.mu.Unlock()
+			return nil, err
+		}
+		if cp.fetching == nil {
+			break
+		}
+		fetching := cp.fetching
+		cp.mu.Unlock()
+		select {
+		case <-fetching:
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	fetching := make(chan struct{})
+	cp.fetching = fetching
+	cp.mu.Unlock()
+
+	info, err := c.ServerInfoCtx(ctx)
+	cp.mu.Lock()
+	defer cp.mu.Unlock()
+	cp.fetching = nil
+	close(fetching)
+	if err != nil {
+		// A canceled fetch says nothing about the server.
+		if ctx.Err() == nil {
+			cp.err, cp.failedAt = err, c.clock().Now()
+		}
+		return nil, err
+	}
+	cp.info, cp.err = info, nil
+	return info, nil
+}
+
+// Supports reports if the server supports the feature. The ServerInfo is
+// cached in the Capabilities of the Client, or fetched every time without
+// them.
+func (c *Client) Supports(ctx context.Context, f Feature) (bool, error) {
+	var info *ServerInfo
+	var err error
+	if c.Capabilities != nil {
+		info, err = c.Capabilities.serverInfo(ctx, c)
+	} else {
+		info, err = c.ServerInfoCtx(ctx)
+	}
+	if err != nil {
+		return false, err
+	}
+	min, ok := featureVersions[f]
+	if !ok {
+		return false, fmt.Errorf("parse: unknown feature %s", f)
+	}
+	return compareVersions(info.Version, min) >= 0, nil
+}
+
+// require returns an UnsupportedError if the Client has Capabilities and the
+// server doesn't support the feature. Without Capabilities, or when the
+// ServerInfo cannot be fetched, nothing is checked.
+func (c *Client) require(ctx context.Context, f Feature) error {
+	if c.Capabilities == nil {
+		return nil
+	}
+	info, err := c.Capabilities.serverInfo(ctx, c)
+	if err != nil {
+		return nil
+	}
+	if compareVersions(info.Version, featureVersions[f]) < 0 {
+		return &UnsupportedError{Feature: f, Version: info.Version}
+	}
+	return nil
+}
+
+// requireFor checks the features used by an API request.
+func (c *Client) requireFor(req *http.Request) error {
+	if c.Capabilities == nil {
+		return nil
+	}
+	endpoint, _ := c.splitPath(req.URL)
+	if strings.HasPrefix(endpoint, "aggregate/") {
+		if err := c.require(req.Context(), FeatureAggregate); err != nil {
+			return err
+		}
+	}
+	if endpoint == "batch" && req.Context().Value(transactionKey{}) != nil {
+		if err := c.require(req.Context(), FeatureTransactions); err != nil {
+			return err
+		}
+	}
+	if req.URL.Query().Get("excludeKeys") != "" {
+		if err := c.require(req.Context(), FeatureExcludeKeys); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// compareVersions compares the numeric parts of two versions like 4.2.0,
+// ignoring suffixes like -alpha.1.
+func compareVersions(a, b string) int {
+	pa, pb := versionParts(a), versionParts(b)
+	for i := 0; i < len(pa) || i < len(pb); i++ {
+		var x, y int
+		if i < len(pa) {
+			x = pa[i]
+		}
+		if i < len(pb) {
+			y = pb[i]
+		}
+		if x != y {
+			if x < y {
+				return -1
+			}
+			return 1
+		}
+	}
+	return 0
+}
+
+func versionParts(v string) []int {
+	if i := strings.IndexAny(v, "-+"); i != -1 {
+		v = v[:i]
+	}
+	var parts []int
+	for _, s := range strings.Split(v, ".") {
+		n, _ := strconv.Atoi(s)
+		parts = append(parts, n)
+	}
+	return parts
+}
@@ -0,0 +1,153 @@
+package parse_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+	"github.com/facebookgo/parse/parsetest"
+)
+
+// serverVersion returns a Transport for a server with the given version, which
+// counts the serverInfo requests and returns an empty object otherwise.
+func serverVersion(t *testing.T, version string, infos *int) http.RoundTripper {
+	return transportFunc(func(r *http.Request) (*http.Response, error) {
+		if r.URL.Path == "/1/serverInfo" {
+			*infos++
+			return jsonResponse(t, http.StatusOK, map[string]string{"parseServerVersion": version}), nil
+		}
+		return jsonResponse(t, http.StatusOK, map[string]interface{}{}), nil
+	})
+}
+
+func TestSupports(t *testing.T) {
+	t.Parallel()
+	var infos int
+	c := &parse.Client{
+		Capabilities: &parse.Capabilities{},
+		Transport:    serverVersion(t, "3.9.0-alpha.1", &infos),
+	}
+	ctx := context.Background()
+	for f, expected := range map[parse.Feature]bool{
+		parse.FeatureAggregate:    true,
+		parse.FeatureGraphQL:      true,
+		parse.FeatureTransactions: true,
+		parse.FeatureExcludeKeys:  false,
+	} {
+		ok, err := c.Supports(ctx, f)
+		ensure.Nil(t, err)
+		ensure.DeepEqual(t, ok, expected)
+	}
+	ensure.DeepEqual(t, infos, 1)
+
+	_, err := c.Supports(ctx, parse.Feature("teleport"))
+	ensure.NotNil(t, err)
+}
+
+func TestCapabilitiesGate(t *testing.T) {
+	t.Parallel()
+	var infos int
+	c := &parse.Client{
+		Capabilities: &parse.Capabilities{},
+		Transport:    serverVersion(t, "2.6.5", &infos),
+	}
+	_, err := c.Get(&url.URL{Path: "aggregate/Yak"}, nil)
+	ensure.NotNil(t, err)
+	ensure.True(t, errors.Is(err, parse.ErrUnsupportedByServer))
+	ensure.DeepEqual(t, err.Error(), "parse: server version 2.6.5 does not support aggregate")
+
+	_, err = c.Get(&url.URL{Path: "classes/Yak", RawQuery: "excludeKeys=secret"}, nil)
+	ensure.DeepEqual(t, err, &parse.UnsupportedError{Feature: parse.FeatureExcludeKeys, Version: "2.6.5"})
+
+	_, err = c.BatchTransaction([]parse.BatchRequest{{Method: "DELETE", Path: "classes/Yak/a"}})
+	ensure.DeepEqual(t, err, &parse.UnsupportedError{Feature: parse.FeatureTransactions, Version: "2.6.5"})
+
+	_, err = c.Get(&url.URL{Path: "classes/Yak"}, nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, infos, 1)
+
+	c.Capabilities.Reset()
+	_, err = c.Get(&url.URL{Path: "aggregate/Yak"}, nil)
+	ensure.NotNil(t, err)
+	ensure.DeepEqual(t, infos, 2)
+}
+
+func TestCapabilitiesNotSet(t *testing.T) {
+	t.Parallel()
+	var infos int
+	c := &parse.Client{Transport: serverVersion(t, "2.6.5", &infos)}
+	_, err := c.Get(&url.URL{Path: "aggregate/Yak"}, nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, infos, 0)
+}
+
+func TestCapabilitiesServerInfoFails(t *testing.T) {
+	t.Parallel()
+	var infos, sent int
+	clock := parsetest.NewClock(time.Date(2015, 6, 16, 1, 2, 3, 0, time.UTC))
+	c := &parse.Client{
+		Clock:        clock,
+		Capabilities: &parse.Capabilities{},
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			if r.URL.Path == "/1/serverInfo" {
+				infos++
+				return jsonResponse(t, http.StatusForbidden, map[string]string{"error": "unauthorized"}), nil
+			}
+			sent++
+			return jsonResponse(t, http.StatusOK, map[string]interface{}{}), nil
+		}),
+	}
+	for i := 0; i < 3; i++ {
+		_, err := c.Get(&url.URL{Path: "classes/Yak", RawQuery: "excludeKeys=secret"}, nil)
+		ensure.Nil(t, err)
+	}
+	ensure.DeepEqual(t, sent, 3)
+	ensure.DeepEqual(t, infos, 1)
+
+	_, err := c.Supports(context.Background(), parse.FeatureExcludeKeys)
+	ensure.Err(t, err, regexp.MustCompile("unauthorized"))
+	ensure.DeepEqual(t, infos, 1)
+
+	clock.Advance(2 * time.Minute)
+	_, err = c.Get(&url.URL{Path: "classes/Yak", RawQuery: "excludeKeys=secret"}, nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, infos, 2)
+}
+
+func TestCapabilitiesConcurrentFetch(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	infos := 0
+	release := make(chan struct{})
+	c := &parse.Client{
+		Capabilities: &parse.Capabilities{},
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			mu.Lock()
+			infos++
+			mu.Unlock()
+			<-release
+			return jsonResponse(t, http.StatusOK, map[string]string{"parseServerVersion": "4.5.0"}), nil
+		}),
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			ok, err := c.Supports(context.Background(), parse.FeatureExcludeKeys)
+			ensure.Nil(t, err)
+			ensure.True(t, ok)
+		}()
+	}
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	wg.Wait()
+	ensure.DeepEqual(t, infos, 1)
+}
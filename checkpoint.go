@@ -0,0 +1,73 @@
+package parse
+
+import (
+	"io/ioutil"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Checkpoint persists the progress of an Exporter, so that an interrupted
+// export can be resumed by a later process.
+type Checkpoint interface {
+	// Load returns the saved objectId, or an empty string if there is none.
+	Load() (string, error)
+
+	// Save stores the objectId of the last exported object. An empty
+	// string clears the checkpoint.
+	Save(lastID string) error
+}
+
+// FileCheckpoint is a Checkpoint stored in a file.
+type FileCheckpoint struct {
+	Path string
+}
+
+// Load reads the checkpoint file. A missing file is an empty checkpoint.
+func (f FileCheckpoint) Load() (string, error) {
+	b, err := ioutil.ReadFile(f.Path)
+	if os.IsNotExist(err) {
+		return "", nil
+	}
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(string(b)), nil
+}
+
+// Save writes the checkpoint file. The file is replaced atomically so that a
+// crash while saving leaves the previous checkpoint intact. Clearing the
+// checkpoint removes the file.
+func (f FileCheckpoint) Save(lastID string) error {
+	if lastID == "" {
+		err := os.Remove(f.Path)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	return writeFileAtomic(f.Path, []byte(lastID+"\n"))
+}
+
+// writeFileAtomic replaces the file with the data, so that a crash while
+// writing leaves the previous file intact.
+func writeFileAtomic(name string, data []byte) error {
+	tmp, err := ioutil.TempFile(filepath.Dir(name), filepath.Base(name)+".")
+	if err != nil {
+		return err
+	}
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := tmp.Close(); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), name); err != nil {
+		os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
@@ -0,0 +1,124 @@
+package parse_test
+
+import (
+	"bytes"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+type memCheckpoint struct {
+	id    string
+	saves []string
+}
+
+func (m *memCheckpoint) Load() (string, error) { return m.id, nil }
+
+func (m *memCheckpoint) Save(id string) error {
+	m.id = id
+	m.saves = append(m.saves, id)
+	return nil
+}
+
+func TestFileCheckpoint(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "parse-checkpoint-")
+	ensure.Nil(t, err)
+	defer os.RemoveAll(dir)
+
+	c := parse.FileCheckpoint{Path: filepath.Join(dir, "yaks.checkpoint")}
+	id, err := c.Load()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, id, "")
+
+	ensure.Nil(t, c.Save("a"))
+	ensure.Nil(t, c.Save("b"))
+	id, err = c.Load()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, id, "b")
+
+	ensure.Nil(t, c.Save(""))
+	_, err = os.Stat(c.Path)
+	ensure.True(t, os.IsNotExist(err))
+	ensure.Nil(t, c.Save(""))
+
+	files, err := ioutil.ReadDir(dir)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(files), 0)
+}
+
+func TestExportCheckpoint(t *testing.T) {
+	t.Parallel()
+	calls := 0
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			switch calls {
+			case 1:
+				ensure.DeepEqual(t, r.URL.Query().Get("where"), `{"objectId":{"$gt":"m"}}`)
+				return jsonResponse(t, http.StatusOK, map[string]interface{}{
+					"results": []map[string]string{{"objectId": "n"}},
+				}), nil
+			case 2:
+				return nil, errors.New("connection reset")
+			case 3:
+				ensure.DeepEqual(t, r.URL.Query().Get("where"), `{"objectId":{"$gt":"n"}}`)
+				return jsonResponse(t, http.StatusOK, map[string]interface{}{
+					"results": []map[string]string{},
+				}), nil
+			}
+			panic("unexpected request")
+		}),
+	}
+	cp := &memCheckpoint{id: "m"}
+	e := parse.Exporter{
+		Client:     c,
+		ClassName:  "Yak",
+		BatchSize:  1,
+		After:      "a",
+		Checkpoint: cp,
+	}
+	var buf bytes.Buffer
+	res, err := e.Export(&buf)
+	ensure.Err(t, err, regexp.MustCompile("connection reset"))
+	ensure.DeepEqual(t, res, &parse.ExportResult{Count: 1, LastID: "n"})
+	ensure.DeepEqual(t, cp.saves, []string{"n"})
+
+	res, err = e.Export(&buf)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res, &parse.ExportResult{LastID: "n"})
+	ensure.DeepEqual(t, cp.saves, []string{"n", ""})
+	ensure.DeepEqual(t, buf.String(), `{"objectId":"n"}`+"\n")
+}
+
+func TestExportCSVCheckpointSkipsHeader(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			if r.URL.Path == "/1/schemas/Yak" {
+				return jsonResponse(t, http.StatusOK, map[string]interface{}{
+					"className": "Yak",
+					"fields":    map[string]interface{}{"name": map[string]string{"type": "String"}},
+				}), nil
+			}
+			if r.URL.Query().Get("where") == `{"objectId":{"$gt":"n"}}` {
+				return jsonResponse(t, http.StatusOK, map[string]interface{}{"results": []interface{}{}}), nil
+			}
+			return jsonResponse(t, http.StatusOK, map[string]interface{}{
+				"results": []map[string]string{{"objectId": "n", "name": "Nu"}},
+			}), nil
+		}),
+	}
+	e := parse.Exporter{Client: c, ClassName: "Yak", Checkpoint: &memCheckpoint{id: "m"}}
+	var buf bytes.Buffer
+	_, err := e.ExportCSV(&buf)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, buf.String(), "n,Nu\n")
+}
@@ -0,0 +1,156 @@
+package parse
+
+import (
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+)
+
+const (
+	defaultCircuitWindow      = 10 * time.Second
+	defaultCircuitMinRequests = 20
+	defaultCircuitErrorRate   = 0.5
+	defaultCircuitCooldown    = 30 * time.Second
+)
+
+// CircuitOpenError is returned without performing the request while a
+// CircuitBreaker is open.
+type CircuitOpenError struct {
+	// Until is when the breaker will let a request through again.
+	Until time.Time
+}
+
+func (e *CircuitOpenError) Error() string {
+	return fmt.Sprintf("parse: circuit breaker open until %s", e.Until.Format(time.RFC3339))
+}
+
+// CircuitBreaker is an http.RoundTripper that stops sending requests when too
+// many of them fail, so that an outage of the API fails calls immediately
+// instead of tying them up until they time out. It is used as the Client
+// Transport:
+//
+//	c := &parse.Client{Transport: &parse.CircuitBreaker{}}
+//
+// The breaker opens when at least MinRequests were made within the Window and
+// the fraction that failed reaches ErrorRate. While open, requests return a
+// *CircuitOpenError. After the Cooldown a single request is let through, and
+// its success closes the breaker again. The zero value is ready to use. A
+// CircuitBreaker must not be copied after first use.
+type CircuitBreaker struct {
+	// Transport performs the requests. When nil http.DefaultTransport will be
+	// used.
+	Transport http.RoundTripper
+
+	// Window is the period over which failures are counted. Defaults to 10s.
+	Window time.Duration
+
+	// MinRequests is the number of requests within the Window needed before
+	// the breaker can open. Defaults to 20.
+	MinRequests int
+
+	// ErrorRate is the fraction of failed requests, between 0 and 1, which
+	// opens the breaker. Defaults to 0.5.
+	ErrorRate float64
+
+	// Cooldown is how long the breaker stays open. Defaults to 30s.
+	Cooldown time.Duration
+
+	// Failure optionally replaces the default decision of whether a request
+	// failed. By default errors and responses with a 5xx status are failures.
+	Failure func(res *http.Response, err error) bool
+
+	mu          sync.Mutex
+	windowStart time.Time
+	requests    int
+	failures    int
+	openUntil   time.Time
+	probing     bool
+}
+
+// RoundTrip performs the request unless the breaker is open.
+func (b *CircuitBreaker) RoundTrip(req *http.Request) (*http.Response, error) {
+	probe, err := b.allow(time.Now())
+	if err != nil {
+		return nil, err
+	}
+	t := b.Transport
+	if t == nil {
+		t = http.DefaultTransport
+	}
+	res, err := t.RoundTrip(req)
+	b.record(time.Now(), probe, b.failure(res, err))
+	return res, err
+}
+
+func (b *CircuitBreaker) failure(res *http.Response, err error) bool {
+	if b.Failure != nil {
+		return b.Failure(res, err)
+	}
+	return err != nil || res.StatusCode >= 500
+}
+
+// allow checks if a request may be made, and whether it is the probe of a
+// breaker that is done cooling down.
+func (b *CircuitBreaker) allow(now time.Time) (bool, error) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if b.openUntil.IsZero() {
+		return false, nil
+	}
+	if b.probing || now.Before(b.openUntil) {
+		return false, &CircuitOpenError{Until: b.openUntil}
+	}
+	b.probing = true
+	return true, nil
+}
+
+func (b *CircuitBreaker) record(now time.Time, probe, failed bool) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if probe {
+		b.probing = false
+		if failed {
+			b.openUntil = now.Add(b.cooldown())
+		} else {
+			b.openUntil = time.Time{}
+			b.windowStart = now
+			b.requests, b.failures = 0, 0
+		}
+		return
+	}
+	if !b.openUntil.IsZero() {
+		return
+	}
+
+	window := b.Window
+	if window <= 0 {
+		window = defaultCircuitWindow
+	}
+	if now.Sub(b.windowStart) > window {
+		b.windowStart = now
+		b.requests, b.failures = 0, 0
+	}
+	b.requests++
+	if failed {
+		b.failures++
+	}
+
+	min, rate := b.MinRequests, b.ErrorRate
+	if min <= 0 {
+		min = defaultCircuitMinRequests
+	}
+	if rate <= 0 {
+		rate = defaultCircuitErrorRate
+	}
+	if b.requests >= min && float64(b.failures) >= rate*float64(b.requests) {
+		b.openUntil = now.Add(b.cooldown())
+	}
+}
+
+func (b *CircuitBreaker) cooldown() time.Duration {
+	if b.Cooldown <= 0 {
+		return defaultCircuitCooldown
+	}
+	return b.Cooldown
+}
@@ -0,0 +1,104 @@
+package parse_test
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestCircuitBreaker(t *testing.T) {
+	t.Parallel()
+	var calls int
+	fail := true
+	b := &parse.CircuitBreaker{
+		MinRequests: 4,
+		ErrorRate:   0.5,
+		Cooldown:    20 * time.Millisecond,
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			if fail && calls%2 == 0 {
+				return jsonResponse(t, http.StatusServiceUnavailable, map[string]string{}), nil
+			}
+			return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+		}),
+	}
+	c := &parse.Client{Transport: b}
+	get := func() error {
+		_, err := c.Get(&url.URL{Path: "a"}, nil)
+		return err
+	}
+
+	// Two out of four requests fail, which opens the breaker.
+	for i := 0; i < 4; i++ {
+		get()
+	}
+	ensure.DeepEqual(t, calls, 4)
+	err := get()
+	ensure.Err(t, err, regexp.MustCompile("circuit breaker open until"))
+	_, ok := err.(*parse.CircuitOpenError)
+	ensure.True(t, ok)
+	ensure.DeepEqual(t, calls, 4)
+
+	// After the cooldown a failing probe opens it again.
+	time.Sleep(25 * time.Millisecond)
+	calls = 1
+	ensure.NotNil(t, get())
+	ensure.DeepEqual(t, calls, 2)
+	_, ok = get().(*parse.CircuitOpenError)
+	ensure.True(t, ok)
+
+	// A successful probe closes it.
+	time.Sleep(25 * time.Millisecond)
+	fail = false
+	ensure.Nil(t, get())
+	ensure.Nil(t, get())
+	ensure.DeepEqual(t, calls, 4)
+}
+
+func TestCircuitBreakerBelowRate(t *testing.T) {
+	t.Parallel()
+	var calls int
+	b := &parse.CircuitBreaker{
+		MinRequests: 2,
+		ErrorRate:   0.5,
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			if calls%3 == 0 {
+				return nil, errors.New("connection reset")
+			}
+			return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+		}),
+	}
+	c := &parse.Client{Transport: b}
+	for i := 0; i < 9; i++ {
+		c.Get(&url.URL{Path: "a"}, nil)
+	}
+	ensure.DeepEqual(t, calls, 9)
+}
+
+func TestCircuitBreakerNotRetried(t *testing.T) {
+	t.Parallel()
+	var calls int
+	b := &parse.CircuitBreaker{
+		MinRequests: 1,
+		Cooldown:    time.Hour,
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			return nil, errors.New("connection refused")
+		}),
+	}
+	c := &parse.Client{
+		Transport: b,
+		Retry:     &parse.RetryPolicy{MaxAttempts: 5, BaseDelay: time.Millisecond},
+	}
+	_, err := c.Get(&url.URL{Path: "a"}, nil)
+	_, ok := err.(*parse.CircuitOpenError)
+	ensure.True(t, ok)
+	ensure.DeepEqual(t, calls, 1)
+}
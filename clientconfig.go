@@ -0,0 +1,125 @@
+package parse
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"time"
+)
+
+// ClientConfig describes a Client in a JSON file, so that tools can share one
+// file per environment:
+//
+//	{
+//		"applicationId": "...",
+//		"masterKey": "...",
+//		"serverURL": "https://example.com/parse",
+//		"timeout": "30s",
+//		"retry": {"maxAttempts": 3, "baseDelay": "200ms"}
+//	}
+//
+// Durations use the format of time.ParseDuration.
+type ClientConfig struct {
+	ApplicationID string       `json:"applicationId"`
+	MasterKey     string       `json:"masterKey,omitempty"`
+	RestAPIKey    string       `json:"restApiKey,omitempty"`
+	JavaScriptKey string       `json:"javascriptKey,omitempty"`
+	SessionToken  string       `json:"sessionToken,omitempty"`
+	ServerURL     string       `json:"serverURL,omitempty"`
+	Timeout       string       `json:"timeout,omitempty"`
+	Retry         *RetryConfig `json:"retry,omitempty"`
+}
+
+// RetryConfig describes a RetryPolicy in a ClientConfig.
+type RetryConfig struct {
+	MaxAttempts    int     `json:"maxAttempts"`
+	BaseDelay      string  `json:"baseDelay,omitempty"`
+	MaxDelay       string  `json:"maxDelay,omitempty"`
+	Multiplier     float64 `json:"multiplier,omitempty"`
+	Jitter         float64 `json:"jitter,omitempty"`
+	StatusCodes    []int   `json:"statusCodes,omitempty"`
+	RetryPOST      bool    `json:"retryPOST,omitempty"`
+	IdempotentPOST bool    `json:"idempotentPOST,omitempty"`
+	RateLimited    bool    `json:"rateLimited,omitempty"`
+}
+
+// LoadClientConfig reads the ClientConfig in the named JSON file and returns
+// its Client. Unknown keys are an error, to catch misspelled settings.
+func LoadClientConfig(name string) (*Client, error) {
+	b, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	var cc ClientConfig
+	d := json.NewDecoder(bytes.NewReader(b))
+	d.DisallowUnknownFields()
+	if err := d.Decode(&cc); err != nil {
+		return nil, fmt.Errorf("parse: invalid config %s: %s", name, err)
+	}
+	return cc.Client()
+}
+
+// Client returns the Client described by the config. The credentials are
+// chosen like CredentialsFlag does.
+func (cc *ClientConfig) Client() (*Client, error) {
+	cf := CredentialsFlag{
+		ApplicationID: cc.ApplicationID,
+		MasterKey:     cc.MasterKey,
+		RestAPIKey:    cc.RestAPIKey,
+		JavaScriptKey: cc.JavaScriptKey,
+		SessionToken:  cc.SessionToken,
+	}
+	cr, err := cf.Credentials()
+	if err != nil {
+		return nil, err
+	}
+	var opts []Option
+	if cc.ServerURL != "" {
+		opts = append(opts, WithBaseURL(cc.ServerURL))
+	}
+	c, err := NewClient(cr, opts...)
+	if err != nil {
+		return nil, err
+	}
+	if c.Timeout, err = parseConfigDuration("timeout", cc.Timeout); err != nil {
+		return nil, err
+	}
+	if cc.Retry != nil {
+		if c.Retry, err = cc.Retry.policy(); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+func (rc *RetryConfig) policy() (*RetryPolicy, error) {
+	p := &RetryPolicy{
+		MaxAttempts:    rc.MaxAttempts,
+		Multiplier:     rc.Multiplier,
+		Jitter:         rc.Jitter,
+		StatusCodes:    rc.StatusCodes,
+		RetryPOST:      rc.RetryPOST,
+		IdempotentPOST: rc.IdempotentPOST,
+		RateLimited:    rc.RateLimited,
+	}
+	var err error
+	if p.BaseDelay, err = parseConfigDuration("retry baseDelay", rc.BaseDelay); err != nil {
+		return nil, err
+	}
+	if p.MaxDelay, err = parseConfigDuration("retry maxDelay", rc.MaxDelay); err != nil {
+		return nil, err
+	}
+	return p, nil
+}
+
+func parseConfigDuration(name, s string) (time.Duration, error) {
+	if s == "" {
+		return 0, nil
+	}
+	d, err := time.ParseDuration(s)
+	if err != nil {
+		return 0, fmt.Errorf("parse: invalid %s %q in config", name, s)
+	}
+	return d, nil
+}
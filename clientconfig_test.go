@@ -0,0 +1,73 @@
+package parse_test
+
+import (
+	"io/ioutil"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func writeConfig(t *testing.T, s string) string {
+	f, err := ioutil.TempFile("", "parse-config")
+	ensure.Nil(t, err)
+	_, err = f.WriteString(s)
+	ensure.Nil(t, err)
+	ensure.Nil(t, f.Close())
+	return f.Name()
+}
+
+func TestLoadClientConfig(t *testing.T) {
+	t.Parallel()
+	name := writeConfig(t, `{
+		"applicationId": "a",
+		"masterKey": "m",
+		"serverURL": "https://example.com/parse",
+		"timeout": "30s",
+		"retry": {"maxAttempts": 3, "baseDelay": "200ms", "statusCodes": [503], "rateLimited": true}
+	}`)
+	defer os.Remove(name)
+	c, err := parse.LoadClientConfig(name)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, c.Credentials, parse.MasterKey{ApplicationID: "a", MasterKey: "m"})
+	ensure.DeepEqual(t, c.BaseURL.String(), "https://example.com/parse/")
+	ensure.DeepEqual(t, c.Timeout, 30*time.Second)
+	ensure.DeepEqual(t, c.Retry, &parse.RetryPolicy{
+		MaxAttempts: 3,
+		BaseDelay:   200 * time.Millisecond,
+		StatusCodes: []int{503},
+		RateLimited: true,
+	})
+}
+
+func TestLoadClientConfigMinimal(t *testing.T) {
+	t.Parallel()
+	name := writeConfig(t, `{"applicationId": "a", "restApiKey": "r"}`)
+	defer os.Remove(name)
+	c, err := parse.LoadClientConfig(name)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, c, &parse.Client{Credentials: parse.RestAPIKey{ApplicationID: "a", RestAPIKey: "r"}})
+}
+
+func TestLoadClientConfigErrors(t *testing.T) {
+	t.Parallel()
+	cases := map[string]string{
+		`{"applicationId": "a", "restApiKey": "r", "timout": "1s"}`:                   `unknown field "timout"`,
+		`{"applicationId": "a", "restApiKey": "r", "timeout": "soon"}`:                `invalid timeout "soon"`,
+		`{"applicationId": "a", "restApiKey": "r", "retry": {"maxDelay": "x"}}`:       `invalid retry maxDelay "x"`,
+		`{"applicationId": "a", "restApiKey": "r", "serverURL": "example.com/parse"}`: "absolute http or https URL",
+		`{"applicationId": "a"}`: "key or session token is required",
+		`[`:                      "invalid config",
+	}
+	for s, expected := range cases {
+		name := writeConfig(t, s)
+		_, err := parse.LoadClientConfig(name)
+		os.Remove(name)
+		ensure.Err(t, err, regexp.MustCompile(regexp.QuoteMeta(expected)))
+	}
+	_, err := parse.LoadClientConfig("/nonexistent/parse.json")
+	ensure.True(t, os.IsNotExist(err))
+}
@@ -0,0 +1,33 @@
+package parse
+
+import "time"
+
+// Clock tells the time and waits. It is used for retry delays, Retry-After
+// times, cache expiry and scheduled pushes, so tests of time based behavior
+// can use a fake clock such as parsetest.Clock.
+type Clock interface {
+	Now() time.Time
+
+	// After sends the time on the returned channel once d has elapsed.
+	After(d time.Duration) <-chan time.Time
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+// clockOrReal returns the clock, or the real clock if it is nil.
+func clockOrReal(c Clock) Clock {
+	if c == nil {
+		return realClock{}
+	}
+	return c
+}
+
+func (c *Client) clock() Clock {
+	if c == nil {
+		return realClock{}
+	}
+	return clockOrReal(c.Clock)
+}
@@ -0,0 +1,95 @@
+package parse_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+	"github.com/facebookgo/parse/parsetest"
+)
+
+func TestClockRetryDelay(t *testing.T) {
+	t.Parallel()
+	clock := parsetest.NewClock(time.Date(2015, 6, 16, 1, 2, 3, 0, time.UTC))
+	calls := 0
+	c := &parse.Client{
+		Clock: clock,
+		Retry: &parse.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Hour, MaxDelay: time.Hour},
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			if calls == 1 {
+				return jsonResponse(t, http.StatusServiceUnavailable, map[string]string{}), nil
+			}
+			return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+		}),
+	}
+	done := make(chan error)
+	go func() {
+		_, err := c.Get(&url.URL{Path: "classes/Yak/a"}, nil)
+		done <- err
+	}()
+	for clock.Waiters() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	clock.Advance(time.Hour)
+	ensure.Nil(t, <-done)
+	ensure.DeepEqual(t, calls, 2)
+}
+
+func TestClockRetryAfter(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2015, 6, 16, 1, 2, 3, 0, time.UTC)
+	c := &parse.Client{
+		Clock: parsetest.NewClock(now),
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			res := jsonResponse(t, http.StatusTooManyRequests, map[string]string{})
+			res.Header = http.Header{"Retry-After": {now.Add(time.Minute).Format(http.TimeFormat)}}
+			return res, nil
+		}),
+	}
+	_, err := c.Get(&url.URL{Path: "classes/Yak/a"}, nil)
+	rerr, ok := err.(*parse.RateLimitError)
+	ensure.True(t, ok, err)
+	ensure.DeepEqual(t, rerr.RetryAfter, time.Minute)
+}
+
+func TestClockMemoryCache(t *testing.T) {
+	t.Parallel()
+	clock := parsetest.NewClock(time.Date(2015, 6, 16, 1, 2, 3, 0, time.UTC))
+	m := parse.MemoryCache{Clock: clock}
+	m.Set("classes/Yak/a", []byte("{}"), time.Minute)
+	clock.Advance(time.Minute)
+	_, ok := m.Get("classes/Yak/a")
+	ensure.True(t, ok)
+	clock.Advance(time.Second)
+	_, ok = m.Get("classes/Yak/a")
+	ensure.False(t, ok)
+}
+
+func TestClockPushIn(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2015, 6, 16, 1, 2, 3, 0, time.UTC)
+	p, err := (&parse.PushBuilder{Clock: parsetest.NewClock(now)}).
+		Channels("yaks").
+		Alert("Hello").
+		In(time.Hour).
+		Build()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, *p.PushTime, now.Add(time.Hour))
+}
+
+func TestClockDryRun(t *testing.T) {
+	t.Parallel()
+	now := time.Date(2015, 6, 16, 1, 2, 3, 0, time.UTC)
+	c := &parse.Client{
+		Clock:  parsetest.NewClock(now),
+		DryRun: true,
+		Logger: parse.LoggerFunc(func(parse.RequestLog) {}),
+	}
+	res, err := (&parse.ObjectClient{Client: c, Path: "classes/Yak"}).Post(map[string]string{})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res.CreatedAt, now)
+}
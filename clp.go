@@ -0,0 +1,181 @@
+package parse
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+)
+
+const rolePrefix = "role:"
+
+// Permission lists who is allowed to perform an operation on a class.
+type Permission struct {
+	Public                 bool
+	RequiresAuthentication bool
+
+	// Users by their object ID.
+	Users []string
+
+	// Roles by their name.
+	Roles []string
+
+	// PointerFields allow the users referenced by the given Pointer fields
+	// of an object.
+	PointerFields []string
+}
+
+// AllowPublic allows anyone.
+func (p *Permission) AllowPublic() *Permission {
+	p.Public = true
+	return p
+}
+
+// RequireAuthentication allows any authenticated user.
+func (p *Permission) RequireAuthentication() *Permission {
+	p.RequiresAuthentication = true
+	return p
+}
+
+// AllowUser allows the user with the given object ID.
+func (p *Permission) AllowUser(id string) *Permission {
+	p.Users = append(p.Users, id)
+	return p
+}
+
+// AllowRole allows the users in the role with the given name.
+func (p *Permission) AllowRole(name string) *Permission {
+	p.Roles = append(p.Roles, name)
+	return p
+}
+
+// AllowPointer allows the users referenced by the given Pointer field.
+func (p *Permission) AllowPointer(field string) *Permission {
+	p.PointerFields = append(p.PointerFields, field)
+	return p
+}
+
+// MarshalJSON encodes the Permission.
+func (p Permission) MarshalJSON() ([]byte, error) {
+	m := make(map[string]interface{})
+	if p.Public {
+		m["*"] = true
+	}
+	if p.RequiresAuthentication {
+		m["requiresAuthentication"] = true
+	}
+	for _, u := range p.Users {
+		m[u] = true
+	}
+	for _, r := range p.Roles {
+		m[rolePrefix+r] = true
+	}
+	if len(p.PointerFields) != 0 {
+		m["pointerFields"] = p.PointerFields
+	}
+	return json.Marshal(m)
+}
+
+// UnmarshalJSON decodes the Permission.
+func (p *Permission) UnmarshalJSON(b []byte) error {
+	var m map[string]json.RawMessage
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+	*p = Permission{}
+	for k, v := range m {
+		if k == "pointerFields" {
+			if err := json.Unmarshal(v, &p.PointerFields); err != nil {
+				return err
+			}
+			continue
+		}
+		var allowed bool
+		if err := json.Unmarshal(v, &allowed); err != nil {
+			return err
+		}
+		if !allowed {
+			continue
+		}
+		switch {
+		case k == "*":
+			p.Public = true
+		case k == "requiresAuthentication":
+			p.RequiresAuthentication = true
+		case strings.HasPrefix(k, rolePrefix):
+			p.Roles = append(p.Roles, strings.TrimPrefix(k, rolePrefix))
+		default:
+			p.Users = append(p.Users, k)
+		}
+	}
+	sort.Strings(p.Roles)
+	sort.Strings(p.Users)
+	return nil
+}
+
+// CLP are the class level permissions of a class. Operations with a nil
+// Permission are left unchanged on update.
+type CLP struct {
+	Find     *Permission `json:"find,omitempty"`
+	Get      *Permission `json:"get,omitempty"`
+	Count    *Permission `json:"count,omitempty"`
+	Create   *Permission `json:"create,omitempty"`
+	Update   *Permission `json:"update,omitempty"`
+	Delete   *Permission `json:"delete,omitempty"`
+	AddField *Permission `json:"addField,omitempty"`
+
+	// ReadUserFields and WriteUserFields are the legacy form of pointer
+	// permissions.
+	ReadUserFields  []string `json:"readUserFields,omitempty"`
+	WriteUserFields []string `json:"writeUserFields,omitempty"`
+
+	// ProtectedFields hides fields from the given users or roles, keyed as
+	// "*", a user ID or "role:name".
+	ProtectedFields map[string][]string `json:"protectedFields,omitempty"`
+}
+
+// Read sets the Permission for the find, get and count operations.
+func (c *CLP) Read(p *Permission) *CLP {
+	c.Find = p
+	c.Get = p
+	c.Count = p
+	return c
+}
+
+// Write sets the Permission for the create, update and delete operations.
+func (c *CLP) Write(p *Permission) *CLP {
+	c.Create = p
+	c.Update = p
+	c.Delete = p
+	return c
+}
+
+// Operation returns the Permission for the operation with the given name
+// such as "find" or "addField", or nil if it is not set.
+func (c *CLP) Operation(name string) *Permission {
+	if c == nil {
+		return nil
+	}
+	switch name {
+	case "find":
+		return c.Find
+	case "get":
+		return c.Get
+	case "count":
+		return c.Count
+	case "create":
+		return c.Create
+	case "update":
+		return c.Update
+	case "delete":
+		return c.Delete
+	case "addField":
+		return c.AddField
+	}
+	return nil
+}
+
+// UpdateCLP sets the class level permissions of the given class, and returns
+// the resulting schema.
+func (c *SchemaClient) UpdateCLP(className string, clp *CLP) (*Schema, error) {
+	return c.Update(&Schema{ClassName: className, ClassLevelPermissions: clp})
+}
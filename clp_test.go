@@ -0,0 +1,90 @@
+package parse_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestPermissionJSON(t *testing.T) {
+	t.Parallel()
+	p := new(parse.Permission).
+		AllowPublic().
+		RequireAuthentication().
+		AllowUser("xyz").
+		AllowRole("Admin").
+		AllowPointer("owner")
+	b := jsonB(t, p)
+	ensure.DeepEqual(t, string(b), `{`+
+		`"*":true,`+
+		`"pointerFields":["owner"],`+
+		`"requiresAuthentication":true,`+
+		`"role:Admin":true,`+
+		`"xyz":true`+
+		`}`)
+	var p2 parse.Permission
+	ensure.Nil(t, json.Unmarshal(b, &p2))
+	ensure.DeepEqual(t, &p2, p)
+}
+
+func TestPermissionUnmarshalIgnoresDenied(t *testing.T) {
+	t.Parallel()
+	var p parse.Permission
+	ensure.Nil(t, json.Unmarshal([]byte(`{"*":false,"role:Admin":true}`), &p))
+	ensure.DeepEqual(t, p, parse.Permission{Roles: []string{"Admin"}})
+}
+
+func TestCLPBuilder(t *testing.T) {
+	t.Parallel()
+	read := new(parse.Permission).AllowPublic()
+	write := new(parse.Permission).AllowRole("Admin")
+	clp := new(parse.CLP).Read(read).Write(write)
+	clp.ProtectedFields = map[string][]string{"*": {"email"}}
+	ensure.DeepEqual(t, string(jsonB(t, clp)), `{`+
+		`"find":{"*":true},`+
+		`"get":{"*":true},`+
+		`"count":{"*":true},`+
+		`"create":{"role:Admin":true},`+
+		`"update":{"role:Admin":true},`+
+		`"delete":{"role:Admin":true},`+
+		`"protectedFields":{"*":["email"]}`+
+		`}`)
+	ensure.True(t, clp.Operation("find") == read)
+	ensure.True(t, clp.Operation("delete") == write)
+	ensure.True(t, clp.Operation("addField") == nil)
+	ensure.True(t, clp.Operation("unknown") == nil)
+	ensure.True(t, (*parse.CLP)(nil).Operation("find") == nil)
+}
+
+func TestSchemaUpdateCLP(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Method, "PUT")
+			ensure.DeepEqual(t, r.URL.Path, "/1/schemas/Yak")
+			var m map[string]interface{}
+			decodeBody(t, r, &m)
+			ensure.DeepEqual(t, m, map[string]interface{}{
+				"className": "Yak",
+				"classLevelPermissions": map[string]interface{}{
+					"addField":       map[string]interface{}{},
+					"readUserFields": []interface{}{"owner"},
+				},
+			})
+			return jsonResponse(t, http.StatusOK, m), nil
+		}),
+	}
+	sc := parse.SchemaClient{Client: c}
+	s, err := sc.UpdateCLP("Yak", &parse.CLP{
+		AddField:       &parse.Permission{},
+		ReadUserFields: []string{"owner"},
+	})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, s.ClassLevelPermissions, &parse.CLP{
+		AddField:       &parse.Permission{},
+		ReadUserFields: []string{"owner"},
+	})
+}
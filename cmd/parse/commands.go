@@ -0,0 +1,396 @@
+package main
+
+import (
+	"encoding/json"
+	"errors"
+	"flag"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"log"
+	"os"
+	"strings"
+
+	"github.com/facebookgo/parse"
+	"github.com/facebookgo/parse/audit"
+)
+
+var errInvalidFormat = errors.New("parse: format must be json or csv")
+
+func (c *cli) flags(name string) *flag.FlagSet {
+	fs := flag.NewFlagSet(name, flag.ContinueOnError)
+	fs.SetOutput(c.Stderr)
+	fs.Usage = func() {
+		fmt.Fprintf(c.Stderr, "usage: parse %s %s\n", name, usages[name])
+		fs.PrintDefaults()
+	}
+	return fs
+}
+
+func (c *cli) parse(fs *flag.FlagSet, args []string, min, max int) error {
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() < min || fs.NArg() > max {
+		fs.Usage()
+		return flag.ErrHelp
+	}
+	return nil
+}
+
+// readJSON returns the JSON given as an argument, or read from stdin when the
+// argument is "-".
+func (c *cli) readJSON(arg string) (json.RawMessage, error) {
+	b := []byte(arg)
+	if arg == "-" {
+		var err error
+		if b, err = ioutil.ReadAll(c.Stdin); err != nil {
+			return nil, err
+		}
+	}
+	var v interface{}
+	if err := json.Unmarshal(b, &v); err != nil {
+		return nil, fmt.Errorf("parse: invalid json: %s", err)
+	}
+	return json.RawMessage(b), nil
+}
+
+func (c *cli) print(v interface{}) error {
+	b, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(c.Stdout, "%s\n", b)
+	return err
+}
+
+func (c *cli) objects(className string) *parse.ObjectClient {
+	return c.client.Class(className)
+}
+
+func (c *cli) get(args []string) error {
+	fs := c.flags("get")
+	if err := c.parse(fs, args, 2, 2); err != nil {
+		return err
+	}
+	var v json.RawMessage
+	if _, err := c.objects(fs.Arg(0)).Get(fs.Arg(1), &v); err != nil {
+		return err
+	}
+	return c.print(v)
+}
+
+func (c *cli) create(args []string) error {
+	fs := c.flags("create")
+	if err := c.parse(fs, args, 2, 2); err != nil {
+		return err
+	}
+	body, err := c.readJSON(fs.Arg(1))
+	if err != nil {
+		return err
+	}
+	res, err := c.objects(fs.Arg(0)).Post(body)
+	if err != nil {
+		return err
+	}
+	return c.print(res)
+}
+
+func (c *cli) update(args []string) error {
+	fs := c.flags("update")
+	if err := c.parse(fs, args, 3, 3); err != nil {
+		return err
+	}
+	body, err := c.readJSON(fs.Arg(2))
+	if err != nil {
+		return err
+	}
+	res, err := c.objects(fs.Arg(0)).Put(fs.Arg(1), body)
+	if err != nil {
+		return err
+	}
+	return c.print(res)
+}
+
+func (c *cli) delete(args []string) error {
+	fs := c.flags("delete")
+	if err := c.parse(fs, args, 2, 2); err != nil {
+		return err
+	}
+	_, err := c.objects(fs.Arg(0)).Delete(fs.Arg(1))
+	return err
+}
+
+func (c *cli) query(args []string) error {
+	fs := c.flags("query")
+	where := fs.String("where", "", "where clause as json")
+	order := fs.String("order", "", "comma separated keys to order by, prefixed with - for descending order")
+	limit := fs.Int("limit", 0, "maximum number of results")
+	skip := fs.Int("skip", 0, "number of results to skip")
+	keys := fs.String("keys", "", "comma separated keys to return")
+	count := fs.Bool("count", false, "include the number of matching objects")
+	if err := c.parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+
+	p := parse.Params{Limit: *limit, Skip: *skip, Count: *count}
+	if *where != "" {
+		w, err := c.readJSON(*where)
+		if err != nil {
+			return err
+		}
+		p.Where = w
+	}
+	if *order != "" {
+		p.Order = strings.Split(*order, ",")
+	}
+	if *keys != "" {
+		p.Keys = strings.Split(*keys, ",")
+	}
+	u, err := p.URL("classes/" + fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	var res json.RawMessage
+	if _, err := c.client.Get(u, &res); err != nil {
+		return err
+	}
+	return c.print(res)
+}
+
+func (c *cli) push(args []string) error {
+	fs := c.flags("push")
+	channels := fs.String("channels", "", "comma separated channels to push to")
+	where := fs.String("where", "", "installation query as json")
+	alert := fs.String("alert", "", "alert text")
+	data := fs.String("data", "", "push data as a json object")
+	dryRun := fs.Bool("dry-run", false, "count the targeted installations instead of sending")
+	if err := c.parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+
+	var b parse.PushBuilder
+	if *channels != "" {
+		b.Channels(strings.Split(*channels, ",")...)
+	}
+	if *where != "" {
+		w, err := c.readJSON(*where)
+		if err != nil {
+			return err
+		}
+		b.Where(w)
+	}
+	if *data != "" {
+		raw, err := c.readJSON(*data)
+		if err != nil {
+			return err
+		}
+		var d map[string]interface{}
+		if err := json.Unmarshal(raw, &d); err != nil {
+			return fmt.Errorf("parse: push data must be a json object: %s", err)
+		}
+		for k, v := range d {
+			b.Data(k, v)
+		}
+	}
+	if *alert != "" {
+		b.Alert(*alert)
+	}
+	p, err := b.Build()
+	if err != nil {
+		return err
+	}
+
+	pc := parse.PushClient{
+		Client: c.client,
+		DryRun: *dryRun,
+		Logger: log.New(c.Stderr, "", 0),
+	}
+	res, err := pc.Send(p)
+	if err != nil {
+		return err
+	}
+	if *dryRun {
+		return c.print(map[string]int{"installations": res.Installations})
+	}
+	return c.print(map[string]interface{}{"result": res.Result, "statusId": res.StatusID})
+}
+
+func (c *cli) schema(args []string) error {
+	fs := c.flags("schema")
+	if err := c.parse(fs, args, 0, 1); err != nil {
+		return err
+	}
+	sc := parse.SchemaClient{Client: c.client}
+	if fs.NArg() == 0 {
+		s, err := sc.List()
+		if err != nil {
+			return err
+		}
+		return c.print(s)
+	}
+	s, err := sc.Get(fs.Arg(0))
+	if err != nil {
+		return err
+	}
+	return c.print(s)
+}
+
+func (c *cli) config(args []string) error {
+	fs := c.flags("config")
+	if err := c.parse(fs, args, 0, 2); err != nil {
+		return err
+	}
+	if fs.NArg() == 2 {
+		v, err := c.readJSON(fs.Arg(1))
+		if err != nil {
+			return err
+		}
+		var config parse.Config
+		if err := config.Set(fs.Arg(0), v); err != nil {
+			return err
+		}
+		return c.client.UpdateConfig(&config)
+	}
+
+	config, err := c.client.GetConfig()
+	if err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		return c.print(config.Params)
+	}
+	v, ok := config.Params[fs.Arg(0)]
+	if !ok {
+		return fmt.Errorf("parse: config has no parameter %s", fs.Arg(0))
+	}
+	return c.print(v)
+}
+
+func (c *cli) export(args []string) error {
+	fs := c.flags("export")
+	format := fs.String("format", "json", "output format, json for JSON Lines or csv")
+	keys := fs.String("keys", "", "comma separated keys to export")
+	where := fs.String("where", "", "where clause as json")
+	checkpoint := fs.String("checkpoint", "", "file to save progress to, and resume from")
+	out := fs.String("o", "", "output file, defaults to stdout")
+	if err := c.parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+	if *format != "json" && *format != "csv" {
+		return errInvalidFormat
+	}
+
+	e := parse.Exporter{Client: c.client, ClassName: fs.Arg(0)}
+	if *keys != "" {
+		e.Keys = strings.Split(*keys, ",")
+	}
+	if *where != "" {
+		w, err := c.readJSON(*where)
+		if err != nil {
+			return err
+		}
+		if err := json.Unmarshal(w, &e.Where); err != nil {
+			return err
+		}
+	}
+	resume := false
+	if *checkpoint != "" {
+		cp := parse.FileCheckpoint{Path: *checkpoint}
+		id, err := cp.Load()
+		if err != nil {
+			return err
+		}
+		resume = id != ""
+		e.Checkpoint = cp
+	}
+
+	w := c.Stdout
+	if *out != "" {
+		// A resumed export continues the earlier output.
+		flags := os.O_WRONLY | os.O_CREATE | os.O_TRUNC
+		if resume {
+			flags = os.O_WRONLY | os.O_CREATE | os.O_APPEND
+		}
+		f, err := os.OpenFile(*out, flags, 0666)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		w = f
+	}
+
+	var (
+		res *parse.ExportResult
+		err error
+	)
+	if *format == "csv" {
+		res, err = e.ExportCSV(w)
+	} else {
+		res, err = e.Export(w)
+	}
+	if res != nil {
+		fmt.Fprintf(c.Stderr, "exported %d objects\n", res.Count)
+	}
+	return err
+}
+
+func (c *cli) importClass(args []string) error {
+	fs := c.flags("import")
+	format := fs.String("format", "json", "input format, json for JSON Lines or csv")
+	concurrency := fs.Int("concurrency", 1, "number of batch requests in flight")
+	in := fs.String("i", "", "input file, defaults to stdin")
+	if err := c.parse(fs, args, 1, 1); err != nil {
+		return err
+	}
+	if *format != "json" && *format != "csv" {
+		return errInvalidFormat
+	}
+
+	var r io.Reader = c.Stdin
+	if *in != "" {
+		f, err := os.Open(*in)
+		if err != nil {
+			return err
+		}
+		defer f.Close()
+		r = f
+	}
+
+	i := parse.Importer{Client: c.client, ClassName: fs.Arg(0), Concurrency: *concurrency}
+	var (
+		res *parse.ImportResult
+		err error
+	)
+	if *format == "csv" {
+		res, err = i.ImportCSV(r)
+	} else {
+		res, err = i.Import(r)
+	}
+	if err != nil {
+		return err
+	}
+	for _, ierr := range res.Errors {
+		fmt.Fprintln(c.Stderr, ierr)
+	}
+	fmt.Fprintf(c.Stderr, "created %d, updated %d, failed %d objects\n",
+		res.Created, res.Updated, len(res.Errors))
+	if len(res.Errors) != 0 {
+		return fmt.Errorf("parse: import of %d objects failed", len(res.Errors))
+	}
+	return nil
+}
+
+func (c *cli) audit(args []string) error {
+	fs := c.flags("audit")
+	sample := fs.Int("sample", 0, "number of objects to sample per class, defaults to 100")
+	if err := c.parse(fs, args, 0, 0); err != nil {
+		return err
+	}
+	s := audit.Scanner{Client: c.client, SampleSize: *sample}
+	report, err := s.Scan()
+	if err != nil {
+		return err
+	}
+	return c.print(report)
+}
@@ -0,0 +1,199 @@
+package main
+
+import (
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"path/filepath"
+	"regexp"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+func decodeBody(t testing.TB, r *http.Request) map[string]interface{} {
+	var v map[string]interface{}
+	ensure.Nil(t, json.NewDecoder(r.Body).Decode(&v))
+	return v
+}
+
+func TestGet(t *testing.T) {
+	t.Parallel()
+	c, stdout, _ := newTestCLI("", func(r *http.Request) (*http.Response, error) {
+		ensure.DeepEqual(t, r.Method, "GET")
+		ensure.DeepEqual(t, r.URL.Path, "/1/classes/Yak/a")
+		ensure.DeepEqual(t, r.Header.Get("X-Parse-Master-Key"), "master")
+		return jsonResponse(t, map[string]string{"objectId": "a", "name": "Al"}), nil
+	})
+	ensure.Nil(t, c.Run([]string{"get", "Yak", "a"}))
+	ensure.DeepEqual(t, stdout.String(), "{\n  \"name\": \"Al\",\n  \"objectId\": \"a\"\n}\n")
+}
+
+func TestGetArguments(t *testing.T) {
+	t.Parallel()
+	c, _, stderr := newTestCLI("", nil)
+	ensure.DeepEqual(t, c.Run([]string{"get", "Yak"}), flag.ErrHelp)
+	ensure.StringContains(t, stderr.String(), "usage: parse get <class> <objectId>")
+}
+
+func TestCreateFromStdin(t *testing.T) {
+	t.Parallel()
+	c, stdout, _ := newTestCLI(`{"name":"Al"}`, func(r *http.Request) (*http.Response, error) {
+		ensure.DeepEqual(t, r.Method, "POST")
+		ensure.DeepEqual(t, r.URL.Path, "/1/classes/Yak/")
+		ensure.DeepEqual(t, decodeBody(t, r), map[string]interface{}{"name": "Al"})
+		return jsonResponse(t, map[string]string{
+			"objectId":  "a",
+			"createdAt": "2015-03-07T21:52:12Z",
+		}), nil
+	})
+	ensure.Nil(t, c.Run([]string{"create", "Yak", "-"}))
+	ensure.DeepEqual(t, stdout.String(),
+		"{\n  \"objectId\": \"a\",\n  \"createdAt\": \"2015-03-07T21:52:12Z\"\n}\n")
+}
+
+func TestUpdateInvalidJSON(t *testing.T) {
+	t.Parallel()
+	c, _, _ := newTestCLI("", nil)
+	ensure.Err(t, c.Run([]string{"update", "Yak", "a", "{name"}), regexp.MustCompile("invalid json"))
+}
+
+func TestDelete(t *testing.T) {
+	t.Parallel()
+	c, stdout, _ := newTestCLI("", func(r *http.Request) (*http.Response, error) {
+		ensure.DeepEqual(t, r.Method, "DELETE")
+		ensure.DeepEqual(t, r.URL.Path, "/1/classes/Yak/a")
+		return jsonResponse(t, map[string]string{}), nil
+	})
+	ensure.Nil(t, c.Run([]string{"delete", "Yak", "a"}))
+	ensure.DeepEqual(t, stdout.String(), "")
+}
+
+func TestQuery(t *testing.T) {
+	t.Parallel()
+	c, stdout, _ := newTestCLI("", func(r *http.Request) (*http.Response, error) {
+		ensure.DeepEqual(t, r.URL.Path, "/1/classes/Yak")
+		ensure.DeepEqual(t, r.URL.Query(), url.Values{
+			"where": {`{"hairy":true}`},
+			"order": {"-createdAt"},
+			"limit": {"5"},
+			"count": {"1"},
+		})
+		return jsonResponse(t, map[string]interface{}{"results": []interface{}{}, "count": 0}), nil
+	})
+	ensure.Nil(t, c.Run([]string{"query", "-where", `{"hairy":true}`, "-order=-createdAt", "-limit=5", "-count", "Yak"}))
+	ensure.DeepEqual(t, stdout.String(), "{\n  \"count\": 0,\n  \"results\": []\n}\n")
+}
+
+func TestPush(t *testing.T) {
+	t.Parallel()
+	c, stdout, _ := newTestCLI("", func(r *http.Request) (*http.Response, error) {
+		ensure.DeepEqual(t, r.URL.Path, "/1/push")
+		ensure.DeepEqual(t, decodeBody(t, r), map[string]interface{}{
+			"channels": []interface{}{"yaks", "herds"},
+			"data":     map[string]interface{}{"alert": "Hello", "sound": "moo.caf"},
+		})
+		res := jsonResponse(t, map[string]bool{"result": true})
+		res.Header.Set("X-Parse-Push-Status-Id", "s")
+		return res, nil
+	})
+	ensure.Nil(t, c.Run([]string{"push", "-channels=yaks,herds", "-alert=Hello", `-data={"sound":"moo.caf"}`}))
+	ensure.DeepEqual(t, stdout.String(), "{\n  \"result\": true,\n  \"statusId\": \"s\"\n}\n")
+}
+
+func TestSchema(t *testing.T) {
+	t.Parallel()
+	c, stdout, _ := newTestCLI("", func(r *http.Request) (*http.Response, error) {
+		ensure.DeepEqual(t, r.URL.Path, "/1/schemas/Yak")
+		return jsonResponse(t, map[string]interface{}{
+			"className": "Yak",
+			"fields":    map[string]interface{}{"name": map[string]string{"type": "String"}},
+		}), nil
+	})
+	ensure.Nil(t, c.Run([]string{"schema", "Yak"}))
+	ensure.StringContains(t, stdout.String(), `"className": "Yak"`)
+}
+
+func TestConfig(t *testing.T) {
+	t.Parallel()
+	c, stdout, _ := newTestCLI("", func(r *http.Request) (*http.Response, error) {
+		ensure.DeepEqual(t, r.URL.Path, "/1/config")
+		return jsonResponse(t, map[string]interface{}{
+			"params": map[string]interface{}{"welcome": "Hi"},
+		}), nil
+	})
+	ensure.Nil(t, c.Run([]string{"config", "welcome"}))
+	ensure.DeepEqual(t, stdout.String(), "\"Hi\"\n")
+	ensure.Err(t, c.Run([]string{"config", "goodbye"}), regexp.MustCompile("no parameter goodbye"))
+
+	c, _, _ = newTestCLI("", func(r *http.Request) (*http.Response, error) {
+		ensure.DeepEqual(t, r.Method, "PUT")
+		ensure.DeepEqual(t, decodeBody(t, r), map[string]interface{}{
+			"params": map[string]interface{}{"limit": 3.0},
+		})
+		return jsonResponse(t, map[string]bool{"result": true}), nil
+	})
+	ensure.Nil(t, c.Run([]string{"config", "limit", "3"}))
+}
+
+func TestExportResume(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "parse-cli-")
+	ensure.Nil(t, err)
+	defer os.RemoveAll(dir)
+	out := filepath.Join(dir, "yaks.jsonl")
+	checkpoint := filepath.Join(dir, "yaks.checkpoint")
+	ensure.Nil(t, ioutil.WriteFile(out, []byte(`{"objectId":"a"}`+"\n"), 0666))
+	ensure.Nil(t, ioutil.WriteFile(checkpoint, []byte("a\n"), 0666))
+
+	c, _, stderr := newTestCLI("", func(r *http.Request) (*http.Response, error) {
+		if r.URL.Query().Get("where") == `{"objectId":{"$gt":"b"}}` {
+			return jsonResponse(t, map[string]interface{}{"results": []interface{}{}}), nil
+		}
+		ensure.DeepEqual(t, r.URL.Query().Get("where"), `{"objectId":{"$gt":"a"}}`)
+		return jsonResponse(t, map[string]interface{}{
+			"results": []map[string]string{{"objectId": "b"}},
+		}), nil
+	})
+	ensure.Nil(t, c.Run([]string{"export", "-o", out, "-checkpoint", checkpoint, "Yak"}))
+	ensure.DeepEqual(t, stderr.String(), "exported 1 objects\n")
+	b, err := ioutil.ReadFile(out)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, string(b), `{"objectId":"a"}`+"\n"+`{"objectId":"b"}`+"\n")
+	_, err = os.Stat(checkpoint)
+	ensure.True(t, os.IsNotExist(err))
+}
+
+func TestExportFormat(t *testing.T) {
+	t.Parallel()
+	c, _, _ := newTestCLI("", nil)
+	ensure.Err(t, c.Run([]string{"export", "-format=xml", "Yak"}), regexp.MustCompile("json or csv"))
+}
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+	in := "name,age:Number\nAl,7\nBo,old\n"
+	c, _, stderr := newTestCLI(in, func(r *http.Request) (*http.Response, error) {
+		ensure.DeepEqual(t, r.URL.Path, "/1/batch")
+		return jsonResponse(t, []interface{}{
+			map[string]interface{}{"success": map[string]string{"objectId": "a"}},
+		}), nil
+	})
+	err := c.Run([]string{"import", "-format=csv", "Yak"})
+	ensure.Err(t, err, regexp.MustCompile("import of 1 objects failed"))
+	ensure.StringContains(t, stderr.String(), `line 3 failed: parse: invalid Number "old"`)
+	ensure.StringContains(t, stderr.String(), "created 1, updated 0, failed 1 objects\n")
+}
+
+func TestAudit(t *testing.T) {
+	t.Parallel()
+	c, stdout, _ := newTestCLI("", func(r *http.Request) (*http.Response, error) {
+		ensure.DeepEqual(t, r.URL.Path, "/1/schemas/")
+		return jsonResponse(t, map[string]interface{}{"results": []interface{}{}}), nil
+	})
+	ensure.Nil(t, c.Run([]string{"audit"}))
+	ensure.StringContains(t, stdout.String(), `"findings"`)
+}
@@ -0,0 +1,135 @@
+// Command parse provides command line access to the Parse API:
+//
+//	parse [flags] <command> [arguments]
+//
+// The credentials are taken from the -application-id, -master-key,
+// -rest-api-key, -javascript-key and -session-token flags, which default to the
+// PARSE_APPLICATION_ID, PARSE_MASTER_KEY, PARSE_REST_API_KEY,
+// PARSE_JAVASCRIPT_KEY and PARSE_SESSION_TOKEN environment variables. The
+// -server flag, or the PARSE_SERVER_URL environment variable, selects a server
+// other than api.parse.com.
+//
+// Objects and other values are read and written as JSON. Run parse without
+// arguments for the list of commands.
+package main
+
+import (
+	"flag"
+	"fmt"
+	"io"
+	"net/http"
+	"os"
+	"sort"
+
+	"github.com/facebookgo/parse"
+)
+
+// usages has the arguments of every command.
+var usages = map[string]string{
+	"get":    "<class> <objectId>",
+	"create": "<class> <json>",
+	"update": "<class> <objectId> <json>",
+	"delete": "<class> <objectId>",
+	"query":  "[-where json] [-order keys] [-limit n] [-skip n] [-keys keys] [-count] <class>",
+	"push":   "[-channels list] [-where json] [-alert text] [-data json] [-dry-run]",
+	"schema": "[class]",
+	"config": "[<key> [json]]",
+	"export": "[-format json|csv] [-keys keys] [-where json] [-checkpoint file] [-o file] <class>",
+	"import": "[-format json|csv] [-concurrency n] [-i file] <class>",
+	"audit":  "[-sample n]",
+}
+
+var commands = map[string]func(c *cli, args []string) error{
+	"get":    (*cli).get,
+	"create": (*cli).create,
+	"update": (*cli).update,
+	"delete": (*cli).delete,
+	"query":  (*cli).query,
+	"push":   (*cli).push,
+	"schema": (*cli).schema,
+	"config": (*cli).config,
+	"export": (*cli).export,
+	"import": (*cli).importClass,
+	"audit":  (*cli).audit,
+}
+
+// cli runs commands with the given environment, so it can be tested.
+type cli struct {
+	Transport http.RoundTripper
+	Getenv    func(string) string
+	Stdin     io.Reader
+	Stdout    io.Writer
+	Stderr    io.Writer
+
+	client *parse.Client
+}
+
+func (c *cli) usage(fs *flag.FlagSet) {
+	fmt.Fprintf(c.Stderr, "usage: parse [flags] <command> [arguments]\n\nflags:\n")
+	fs.PrintDefaults()
+	fmt.Fprintf(c.Stderr, "\ncommands:\n")
+	var names []string
+	for name := range usages {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	for _, name := range names {
+		fmt.Fprintf(c.Stderr, "  %s %s\n", name, usages[name])
+	}
+}
+
+// Run parses the global flags and runs the command.
+func (c *cli) Run(args []string) error {
+	cf := parse.CredentialsFlag{
+		ApplicationID: c.Getenv("PARSE_APPLICATION_ID"),
+		MasterKey:     c.Getenv("PARSE_MASTER_KEY"),
+		RestAPIKey:    c.Getenv("PARSE_REST_API_KEY"),
+		JavaScriptKey: c.Getenv("PARSE_JAVASCRIPT_KEY"),
+		SessionToken:  c.Getenv("PARSE_SESSION_TOKEN"),
+	}
+	fs := flag.NewFlagSet("parse", flag.ContinueOnError)
+	fs.SetOutput(c.Stderr)
+	cf.Register(fs)
+	server := fs.String("server", c.Getenv("PARSE_SERVER_URL"), "server URL, such as https://example.com/parse/")
+	fs.Usage = func() { c.usage(fs) }
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() == 0 {
+		c.usage(fs)
+		return flag.ErrHelp
+	}
+	cmd, ok := commands[fs.Arg(0)]
+	if !ok {
+		return fmt.Errorf("parse: unknown command %q", fs.Arg(0))
+	}
+
+	cr, err := cf.Credentials()
+	if err != nil {
+		return err
+	}
+	c.client = &parse.Client{Transport: c.Transport, Credentials: cr}
+	if *server != "" {
+		u, err := parse.ParseBaseURL(*server)
+		if err != nil {
+			return err
+		}
+		c.client.BaseURL = u
+	}
+	return cmd(c, fs.Args()[1:])
+}
+
+func main() {
+	c := cli{
+		Getenv: os.Getenv,
+		Stdin:  os.Stdin,
+		Stdout: os.Stdout,
+		Stderr: os.Stderr,
+	}
+	if err := c.Run(os.Args[1:]); err != nil {
+		if err != flag.ErrHelp {
+			fmt.Fprintln(os.Stderr, err)
+		}
+		os.Exit(2)
+	}
+}
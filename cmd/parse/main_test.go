@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"flag"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+)
+
+type transportFunc func(*http.Request) (*http.Response, error)
+
+func (t transportFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return t(r)
+}
+
+func jsonResponse(t testing.TB, v interface{}) *http.Response {
+	b, err := json.Marshal(v)
+	ensure.Nil(t, err)
+	return &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     make(http.Header),
+		Body:       ioutil.NopCloser(bytes.NewReader(b)),
+	}
+}
+
+var testEnv = map[string]string{
+	"PARSE_APPLICATION_ID": "app",
+	"PARSE_MASTER_KEY":     "master",
+}
+
+// newTestCLI returns a cli using the environment in testEnv, and the given
+// transport.
+func newTestCLI(stdin string, t transportFunc) (*cli, *bytes.Buffer, *bytes.Buffer) {
+	var stdout, stderr bytes.Buffer
+	c := &cli{
+		Transport: t,
+		Getenv:    func(k string) string { return testEnv[k] },
+		Stdin:     strings.NewReader(stdin),
+		Stdout:    &stdout,
+		Stderr:    &stderr,
+	}
+	return c, &stdout, &stderr
+}
+
+func TestRunUsage(t *testing.T) {
+	t.Parallel()
+	c, _, stderr := newTestCLI("", nil)
+	ensure.DeepEqual(t, c.Run(nil), flag.ErrHelp)
+	ensure.StringContains(t, stderr.String(), "usage: parse [flags] <command> [arguments]")
+	ensure.StringContains(t, stderr.String(), "  export [-format json|csv]")
+	ensure.StringContains(t, stderr.String(), "-master-key")
+}
+
+func TestRunUnknownCommand(t *testing.T) {
+	t.Parallel()
+	c, _, _ := newTestCLI("", nil)
+	ensure.Err(t, c.Run([]string{"frobnicate"}), regexp.MustCompile(`unknown command "frobnicate"`))
+}
+
+func TestRunCredentials(t *testing.T) {
+	t.Parallel()
+	c, _, _ := newTestCLI("", func(r *http.Request) (*http.Response, error) {
+		ensure.DeepEqual(t, r.URL.String(), "https://yaks.example.com/parse/classes/Yak/a")
+		ensure.DeepEqual(t, r.Header.Get("X-Parse-Application-ID"), "app2")
+		ensure.DeepEqual(t, r.Header.Get("X-Parse-REST-API-Key"), "rest")
+		ensure.DeepEqual(t, r.Header.Get("X-Parse-Master-Key"), "")
+		return jsonResponse(t, map[string]string{}), nil
+	})
+	c.Getenv = func(k string) string {
+		return map[string]string{
+			"PARSE_APPLICATION_ID": "app",
+			"PARSE_REST_API_KEY":   "rest",
+			"PARSE_SERVER_URL":     "https://yaks.example.com/parse/",
+		}[k]
+	}
+	ensure.Nil(t, c.Run([]string{"-application-id=app2", "get", "Yak", "a"}))
+
+	c, _, _ = newTestCLI("", nil)
+	c.Getenv = func(string) string { return "" }
+	ensure.Err(t, c.Run([]string{"get", "Yak", "a"}), regexp.MustCompile("empty ApplicationID"))
+}
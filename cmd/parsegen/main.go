@@ -0,0 +1,313 @@
+// Command parsegen generates Go types and typed clients for the classes of a
+// Parse application, using the live schemas. It is intended to be used with
+// go generate:
+//
+//	//go:generate parsegen -package models -o models_gen.go -classes Yak,Herd
+//
+// The Application ID and Master Key are read from the -application-id and
+// -master-key flags. With -nullable the generated fields can be cleared, see
+// parse.NullString. Classes and fields whose Go names are already used are
+// numbered, such as User2 for _User next to User, with a warning.
+package main
+
+import (
+	"bytes"
+	"flag"
+	"fmt"
+	"go/format"
+	"io"
+	"io/ioutil"
+	"os"
+	"sort"
+	"strings"
+	"unicode"
+
+	"github.com/facebookgo/parse"
+)
+
+// Fields that are handled explicitly on every generated type.
+var defaultFields = map[string]bool{
+	"objectId":  true,
+	"createdAt": true,
+	"updatedAt": true,
+	"ACL":       true,
+}
+
+// Common initialisms, from golint.
+var initialisms = map[string]bool{
+	"ACL": true, "API": true, "HTML": true, "HTTP": true, "HTTPS": true,
+	"ID": true, "IP": true, "JSON": true, "SQL": true, "URI": true,
+	"URL": true, "UUID": true,
+}
+
+// goName converts a class or field name into an exported Go identifier. For
+// example "_User" becomes "User" and "owner_id" becomes "OwnerID".
+func goName(name string) string {
+	var words []string
+	var word []rune
+	flush := func() {
+		if len(word) != 0 {
+			words = append(words, string(word))
+			word = nil
+		}
+	}
+	for _, r := range name {
+		switch {
+		case r == '_' || r == '-' || r == ' ':
+			flush()
+		case unicode.IsUpper(r) && len(word) != 0 && !unicode.IsUpper(word[len(word)-1]):
+			flush()
+			word = append(word, r)
+		default:
+			word = append(word, r)
+		}
+	}
+	flush()
+
+	var buf bytes.Buffer
+	for _, w := range words {
+		if u := strings.ToUpper(w); initialisms[u] {
+			buf.WriteString(u)
+			continue
+		}
+		r := []rune(w)
+		r[0] = unicode.ToUpper(r[0])
+		buf.WriteString(string(r))
+	}
+	if buf.Len() == 0 || !unicode.IsLetter([]rune(buf.String())[0]) {
+		return "X" + buf.String()
+	}
+	return buf.String()
+}
+
+// goType returns the Go type for a field. With nullable the String, Number
+// and Boolean fields use the parse Null types, so they can be set to null or
+// to their zero value.
+func goType(f parse.Field, nullable bool) string {
+	switch f.Type {
+	case parse.FieldString:
+		if nullable {
+			return "*parse.NullString"
+		}
+		return "string"
+	case parse.FieldNumber:
+		if nullable {
+			return "*parse.NullFloat"
+		}
+		return "float64"
+	case parse.FieldBoolean:
+		if nullable {
+			return "*parse.NullBool"
+		}
+		return "bool"
+	case parse.FieldDate:
+		return "*parse.Date"
+	case parse.FieldObject:
+		return "map[string]interface{}"
+	case parse.FieldArray:
+		return "[]interface{}"
+	case parse.FieldGeoPoint:
+		return "*parse.GeoPoint"
+	case parse.FieldFile:
+		return "*parse.File"
+	case parse.FieldPointer:
+		return "*parse.Pointer"
+	case parse.FieldRelation:
+		return "*parse.Relation"
+	}
+	return "interface{}"
+}
+
+// Go names of the fields that are generated on every type.
+var defaultGoNames = []string{"ID", "CreatedAt", "UpdatedAt", "ACL"}
+
+// unique returns name, or name with the first free number appended when
+// another class or field already uses it, and marks the result as used.
+// Classes also reserve the name of their client type.
+func unique(used map[string]bool, name, suffix string) string {
+	n := name
+	for i := 2; used[n] || used[n+suffix]; i++ {
+		n = fmt.Sprintf("%s%d", name, i)
+	}
+	used[n] = true
+	used[n+suffix] = true
+	return n
+}
+
+// generate writes the types and clients for the schemas as a Go source file
+// in package pkg. Classes and fields whose Go names collide are renamed, and
+// a warning is written to warn.
+func generate(pkg string, schemas []*parse.Schema, nullable bool, warn io.Writer) ([]byte, error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "// Code generated by parsegen. DO NOT EDIT.\n\n")
+	fmt.Fprintf(&buf, "package %s\n", pkg)
+	if len(schemas) != 0 {
+		fmt.Fprintf(&buf, "\nimport (\n\t\"time\"\n\n\t\"github.com/facebookgo/parse\"\n)\n")
+	}
+
+	sort.Sort(byClassName(schemas))
+	types := make(map[string]bool)
+	for _, s := range schemas {
+		name := unique(types, goName(s.ClassName), "Client")
+		if name != goName(s.ClassName) {
+			fmt.Fprintf(warn, "parsegen: class %s renamed to %s, %s is already used\n",
+				s.ClassName, name, goName(s.ClassName))
+		}
+		fmt.Fprintf(&buf, "\n// %s is an object of the %s class.\n", name, s.ClassName)
+		fmt.Fprintf(&buf, "type %s struct {\n", name)
+		fmt.Fprintf(&buf, "\tID string `json:\"objectId,omitempty\"`\n")
+		fmt.Fprintf(&buf, "\tCreatedAt *time.Time `json:\"createdAt,omitempty\"`\n")
+		fmt.Fprintf(&buf, "\tUpdatedAt *time.Time `json:\"updatedAt,omitempty\"`\n")
+		fmt.Fprintf(&buf, "\tACL map[string]interface{} `json:\"ACL,omitempty\"`\n")
+
+		fields := make([]string, 0, len(s.Fields))
+		for f := range s.Fields {
+			if !defaultFields[f] {
+				fields = append(fields, f)
+			}
+		}
+		sort.Strings(fields)
+		used := make(map[string]bool)
+		for _, n := range defaultGoNames {
+			used[n] = true
+		}
+		for _, f := range fields {
+			field := s.Fields[f]
+			fname := unique(used, goName(f), "")
+			if fname != goName(f) {
+				fmt.Fprintf(warn, "parsegen: field %s.%s renamed to %s, %s is already used\n",
+					s.ClassName, f, fname, goName(f))
+			}
+			if field.TargetClass != "" {
+				fmt.Fprintf(&buf, "\n\t// %s refers to %s.\n", fname, field.TargetClass)
+			}
+			fmt.Fprintf(&buf, "\t%s %s `json:\"%s,omitempty\"`\n", fname, goType(field, nullable), f)
+		}
+		fmt.Fprintf(&buf, "}\n")
+
+		path := "classes/" + s.ClassName + "/"
+		switch s.ClassName {
+		case "_User":
+			path = "users/"
+		case "_Role":
+			path = "roles/"
+		case "_Installation":
+			path = "installations/"
+		case "_Session":
+			path = "sessions/"
+		}
+		fmt.Fprintf(&buf, clientTemplate, name, s.ClassName, path)
+	}
+	return format.Source(buf.Bytes())
+}
+
+const clientTemplate = `
+// %[1]sClient provides access to the %[2]s class.
+type %[1]sClient struct {
+	Client *parse.Client
+}
+
+func (c *%[1]sClient) objects() *parse.ObjectClient {
+	return &parse.ObjectClient{Client: c.Client, Path: %[3]q}
+}
+
+// Get fetches the object with the given ID.
+func (c *%[1]sClient) Get(id string) (*%[1]s, error) {
+	var v %[1]s
+	if _, err := c.objects().Get(id, &v); err != nil {
+		return nil, err
+	}
+	return &v, nil
+}
+
+// Create creates a new object.
+func (c *%[1]sClient) Create(v *%[1]s) (*parse.CreateResponse, error) {
+	v2 := *v
+	v2.ID = ""
+	v2.CreatedAt = nil
+	v2.UpdatedAt = nil
+	return c.objects().Post(&v2)
+}
+
+// Update modifies the object with the given ID using the fields in v.
+func (c *%[1]sClient) Update(id string, v interface{}) (*parse.UpdateResponse, error) {
+	return c.objects().Put(id, v)
+}
+
+// Delete deletes the object with the given ID.
+func (c *%[1]sClient) Delete(id string) error {
+	_, err := c.objects().Delete(id)
+	return err
+}
+`
+
+type byClassName []*parse.Schema
+
+func (s byClassName) Len() int           { return len(s) }
+func (s byClassName) Less(i, j int) bool { return s[i].ClassName < s[j].ClassName }
+func (s byClassName) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+func main() {
+	applicationID := flag.String("application-id", "", "application ID")
+	masterKey := flag.String("master-key", "", "master key")
+	server := flag.String("server", "", "server URL, such as https://example.com/parse/")
+	pkg := flag.String("package", "models", "package name of the generated file")
+	out := flag.String("o", "", "output file, defaults to stdout")
+	classes := flag.String("classes", "", "comma separated classes to generate, defaults to all")
+	nullable := flag.Bool("nullable", false, "use parse.NullString, NullFloat and NullBool for String, Number and Boolean fields")
+	flag.Parse()
+
+	if err := run(*applicationID, *masterKey, *server, *pkg, *out, *classes, *nullable); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(applicationID, masterKey, server, pkg, out, classes string, nullable bool) error {
+	c := &parse.Client{
+		Credentials: parse.MasterKey{
+			ApplicationID: applicationID,
+			MasterKey:     masterKey,
+		},
+	}
+	if server != "" {
+		u, err := parse.ParseBaseURL(server)
+		if err != nil {
+			return err
+		}
+		c.BaseURL = u
+	}
+
+	sc := parse.SchemaClient{Client: c}
+	schemas, err := sc.List()
+	if err != nil {
+		return err
+	}
+	if classes != "" {
+		want := make(map[string]bool)
+		for _, c := range strings.Split(classes, ",") {
+			want[c] = true
+		}
+		var filtered []*parse.Schema
+		for _, s := range schemas {
+			if want[s.ClassName] {
+				filtered = append(filtered, s)
+				delete(want, s.ClassName)
+			}
+		}
+		for c := range want {
+			return fmt.Errorf("parsegen: class %s not found", c)
+		}
+		schemas = filtered
+	}
+
+	src, err := generate(pkg, schemas, nullable, os.Stderr)
+	if err != nil {
+		return err
+	}
+	if out == "" {
+		_, err = os.Stdout.Write(src)
+		return err
+	}
+	return ioutil.WriteFile(out, src, 0644)
+}
@@ -0,0 +1,149 @@
+package main
+
+import (
+	"bytes"
+	"go/parser"
+	"go/token"
+	"io/ioutil"
+	"strings"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestGoName(t *testing.T) {
+	t.Parallel()
+	cases := map[string]string{
+		"_User":       "User",
+		"GameScore":   "GameScore",
+		"owner_id":    "OwnerID",
+		"deviceToken": "DeviceToken",
+		"GCMSenderId": "GCMSenderID",
+		"imageUrl":    "ImageURL",
+		"2fa":         "X2fa",
+	}
+	for in, out := range cases {
+		ensure.DeepEqual(t, goName(in), out, in)
+	}
+}
+
+func TestGenerate(t *testing.T) {
+	t.Parallel()
+	src, err := generate("models", []*parse.Schema{
+		{
+			ClassName: "Yak",
+			Fields: map[string]parse.Field{
+				"objectId": {Type: parse.FieldString},
+				"name":     {Type: parse.FieldString},
+				"age":      {Type: parse.FieldNumber},
+				"born":     {Type: parse.FieldDate},
+				"location": {Type: parse.FieldGeoPoint},
+				"photo":    {Type: parse.FieldFile},
+				"owner":    {Type: parse.FieldPointer, TargetClass: "_User"},
+				"likes":    {Type: parse.FieldRelation, TargetClass: "_User"},
+				"tags":     {Type: parse.FieldArray},
+			},
+		},
+		{ClassName: "_User"},
+	}, false, ioutil.Discard)
+	ensure.Nil(t, err)
+	_, err = parser.ParseFile(token.NewFileSet(), "models_gen.go", src, 0)
+	ensure.Nil(t, err)
+
+	// Compare with alignment collapsed so the assertions don't depend on
+	// gofmt's column layout.
+	s := strings.Join(strings.Fields(string(src)), " ")
+	for _, expected := range []string{
+		"// Code generated by parsegen. DO NOT EDIT. package models",
+		"type Yak struct { ID string `json:\"objectId,omitempty\"`",
+		"Age float64 `json:\"age,omitempty\"`",
+		"Born *parse.Date `json:\"born,omitempty\"`",
+		"// Likes refers to _User. Likes *parse.Relation `json:\"likes,omitempty\"`",
+		"Location *parse.GeoPoint `json:\"location,omitempty\"`",
+		"// Owner refers to _User. Owner *parse.Pointer `json:\"owner,omitempty\"`",
+		"Photo *parse.File `json:\"photo,omitempty\"`",
+		"Tags []interface{} `json:\"tags,omitempty\"`",
+		"type YakClient struct {",
+		`return &parse.ObjectClient{Client: c.Client, Path: "classes/Yak/"}`,
+		"func (c *YakClient) Get(id string) (*Yak, error) {",
+		"type User struct {",
+		`return &parse.ObjectClient{Client: c.Client, Path: "users/"}`,
+	} {
+		ensure.True(t, strings.Contains(s, expected), expected, s)
+	}
+	ensure.False(t, strings.Contains(s, "ObjectID"))
+}
+
+func TestGenerateNullable(t *testing.T) {
+	t.Parallel()
+	src, err := generate("models", []*parse.Schema{
+		{
+			ClassName: "Yak",
+			Fields: map[string]parse.Field{
+				"name": {Type: parse.FieldString},
+				"age":  {Type: parse.FieldNumber},
+				"tame": {Type: parse.FieldBoolean},
+				"born": {Type: parse.FieldDate},
+			},
+		},
+	}, true, ioutil.Discard)
+	ensure.Nil(t, err)
+	s := strings.Join(strings.Fields(string(src)), " ")
+	for _, expected := range []string{
+		"Age *parse.NullFloat `json:\"age,omitempty\"`",
+		"Born *parse.Date `json:\"born,omitempty\"`",
+		"Name *parse.NullString `json:\"name,omitempty\"`",
+		"Tame *parse.NullBool `json:\"tame,omitempty\"`",
+	} {
+		ensure.True(t, strings.Contains(s, expected), expected, s)
+	}
+}
+
+func TestGenerateEmpty(t *testing.T) {
+	t.Parallel()
+	src, err := generate("models", nil, false, ioutil.Discard)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, string(src), "// Code generated by parsegen. DO NOT EDIT.\n\npackage models\n")
+}
+
+func TestGenerateCollisions(t *testing.T) {
+	t.Parallel()
+	var warn bytes.Buffer
+	src, err := generate("models", []*parse.Schema{
+		{
+			ClassName: "Yak",
+			Fields: map[string]parse.Field{
+				"id":         {Type: parse.FieldString},
+				"created_at": {Type: parse.FieldDate},
+				"updated_at": {Type: parse.FieldDate},
+				"ownerId":    {Type: parse.FieldString},
+				"owner_id":   {Type: parse.FieldString},
+			},
+		},
+		{ClassName: "User"},
+		{ClassName: "_User"},
+		{ClassName: "YakClient"},
+	}, false, &warn)
+	ensure.Nil(t, err)
+
+	s := strings.Join(strings.Fields(string(src)), " ")
+	for _, expected := range []string{
+		"CreatedAt2 *parse.Date `json:\"created_at,omitempty\"`",
+		"ID2 string `json:\"id,omitempty\"`",
+		"OwnerID string `json:\"ownerId,omitempty\"`",
+		"OwnerID2 string `json:\"owner_id,omitempty\"`",
+		"UpdatedAt2 *parse.Date `json:\"updated_at,omitempty\"`",
+		"// User is an object of the User class. type User struct {",
+		"// User2 is an object of the _User class. type User2 struct {",
+		"type YakClient2 struct {",
+		"type YakClient2Client struct {",
+		"type YakClient struct { Client *parse.Client }",
+	} {
+		ensure.True(t, strings.Contains(s, expected), expected, s)
+	}
+	ensure.StringContains(t, warn.String(), "parsegen: field Yak.id renamed to ID2, ID is already used\n")
+	ensure.StringContains(t, warn.String(), "parsegen: field Yak.owner_id renamed to OwnerID2, OwnerID is already used\n")
+	ensure.StringContains(t, warn.String(), "parsegen: class _User renamed to User2, User is already used\n")
+	ensure.StringContains(t, warn.String(), "parsegen: class YakClient renamed to YakClient2, YakClient is already used\n")
+}
@@ -0,0 +1,46 @@
+package parse
+
+import (
+	"encoding/json"
+	"io"
+)
+
+// Codec encodes request bodies and decodes results. It allows replacing
+// encoding/json with a faster compatible package, such as jsoniter.
+type Codec interface {
+	Marshal(v interface{}) ([]byte, error)
+	Unmarshal(data []byte, v interface{}) error
+	NewDecoder(r io.Reader) Decoder
+}
+
+// Decoder decodes a JSON value, like a json.Decoder.
+type Decoder interface {
+	Decode(v interface{}) error
+	UseNumber()
+	DisallowUnknownFields()
+}
+
+// JSONCodec is the Codec using encoding/json.
+type JSONCodec struct{}
+
+// Marshal calls json.Marshal.
+func (JSONCodec) Marshal(v interface{}) ([]byte, error) {
+	return json.Marshal(v)
+}
+
+// Unmarshal calls json.Unmarshal.
+func (JSONCodec) Unmarshal(data []byte, v interface{}) error {
+	return json.Unmarshal(data, v)
+}
+
+// NewDecoder returns a json.Decoder.
+func (JSONCodec) NewDecoder(r io.Reader) Decoder {
+	return json.NewDecoder(r)
+}
+
+func (c *Client) codec() Codec {
+	if c.Codec == nil {
+		return JSONCodec{}
+	}
+	return c.Codec
+}
@@ -0,0 +1,49 @@
+package parse_test
+
+import (
+	"encoding/json"
+	"io"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+type countingCodec struct {
+	parse.JSONCodec
+	marshals, decoders int
+}
+
+func (c *countingCodec) Marshal(v interface{}) ([]byte, error) {
+	c.marshals++
+	return json.Marshal(v)
+}
+
+func (c *countingCodec) NewDecoder(r io.Reader) parse.Decoder {
+	c.decoders++
+	return json.NewDecoder(r)
+}
+
+func TestCodec(t *testing.T) {
+	t.Parallel()
+	codec := &countingCodec{}
+	c := &parse.Client{
+		Codec: codec,
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			var body map[string]string
+			decodeBody(t, r, &body)
+			ensure.DeepEqual(t, body, map[string]string{"name": "Al"})
+			return jsonResponse(t, http.StatusOK, map[string]interface{}{"result": 42}), nil
+		}),
+	}
+	var res map[string]int
+	_, err := c.Post(&url.URL{Path: "classes/Yak"}, map[string]string{"name": "Al"}, &res)
+	ensure.Nil(t, err)
+	var answer int
+	ensure.Nil(t, c.CallFunction("answer", map[string]string{"name": "Al"}, &answer))
+	ensure.DeepEqual(t, answer, 42)
+	ensure.DeepEqual(t, codec.marshals, 2)
+	ensure.DeepEqual(t, codec.decoders, 3)
+}
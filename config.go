@@ -0,0 +1,83 @@
+package parse
+
+import (
+	"encoding/json"
+	"net/url"
+	"time"
+)
+
+var configURL = &url.URL{Path: "config"}
+
+// Config holds the application config parameters. The parameters are kept in
+// their encoded form, so parameters of any type are preserved on update.
+type Config struct {
+	Params map[string]json.RawMessage `json:"params"`
+}
+
+func (c *Config) get(key string, v interface{}) bool {
+	raw, ok := c.Params[key]
+	if !ok {
+		return false
+	}
+	return json.Unmarshal(raw, v) == nil
+}
+
+// GetString returns the string parameter with the given key. It returns false
+// if the parameter is missing or not a string.
+func (c *Config) GetString(key string) (string, bool) {
+	var s string
+	ok := c.get(key, &s)
+	return s, ok
+}
+
+// GetBool returns the boolean parameter with the given key. It returns false
+// if the parameter is missing or not a boolean.
+func (c *Config) GetBool(key string) (bool, bool) {
+	var b bool
+	ok := c.get(key, &b)
+	return b, ok
+}
+
+// GetFloat returns the number parameter with the given key. It returns false
+// if the parameter is missing or not a number.
+func (c *Config) GetFloat(key string) (float64, bool) {
+	var f float64
+	ok := c.get(key, &f)
+	return f, ok
+}
+
+// GetTime returns the date parameter with the given key. It returns false if
+// the parameter is missing or not a date.
+func (c *Config) GetTime(key string) (time.Time, bool) {
+	var d Date
+	ok := c.get(key, &d)
+	return time.Time(d), ok
+}
+
+// Set sets the parameter with the given key. Use Date for date parameters.
+func (c *Config) Set(key string, value interface{}) error {
+	raw, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	if c.Params == nil {
+		c.Params = make(map[string]json.RawMessage)
+	}
+	c.Params[key] = raw
+	return nil
+}
+
+// GetConfig fetches the application config.
+func (c *Client) GetConfig() (*Config, error) {
+	var config Config
+	if _, err := c.Get(configURL, &config); err != nil {
+		return nil, err
+	}
+	return &config, nil
+}
+
+// UpdateConfig updates the application config. It requires the Master Key.
+func (c *Client) UpdateConfig(config *Config) error {
+	_, err := c.Put(configURL, config, nil)
+	return err
+}
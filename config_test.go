@@ -0,0 +1,92 @@
+package parse_test
+
+import (
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestGetConfig(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Method, "GET")
+			ensure.DeepEqual(t, r.URL.Path, "/1/config")
+			return jsonResponse(t, http.StatusOK, map[string]interface{}{
+				"params": map[string]interface{}{
+					"welcome": "hello",
+					"enabled": true,
+					"limit":   42,
+					"launch": map[string]string{
+						"__type": "Date",
+						"iso":    "2015-06-16T01:02:03.000Z",
+					},
+				},
+			}), nil
+		}),
+	}
+	config, err := c.GetConfig()
+	ensure.Nil(t, err)
+
+	s, ok := config.GetString("welcome")
+	ensure.True(t, ok)
+	ensure.DeepEqual(t, s, "hello")
+
+	b, ok := config.GetBool("enabled")
+	ensure.True(t, ok)
+	ensure.True(t, b)
+
+	f, ok := config.GetFloat("limit")
+	ensure.True(t, ok)
+	ensure.DeepEqual(t, f, float64(42))
+
+	tm, ok := config.GetTime("launch")
+	ensure.True(t, ok)
+	ensure.True(t, tm.Equal(time.Date(2015, 6, 16, 1, 2, 3, 0, time.UTC)))
+
+	_, ok = config.GetBool("welcome")
+	ensure.False(t, ok)
+	_, ok = config.GetString("missing")
+	ensure.False(t, ok)
+}
+
+func TestUpdateConfigPreservesParams(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			if r.Method == "GET" {
+				return jsonResponse(t, http.StatusOK, map[string]interface{}{
+					"params": map[string]interface{}{
+						"unknown": map[string]interface{}{"__type": "File", "name": "a.png"},
+					},
+				}), nil
+			}
+			ensure.DeepEqual(t, r.Method, "PUT")
+			var m map[string]interface{}
+			decodeBody(t, r, &m)
+			ensure.DeepEqual(t, m, map[string]interface{}{
+				"params": map[string]interface{}{
+					"unknown": map[string]interface{}{"__type": "File", "name": "a.png"},
+					"welcome": "hi",
+				},
+			})
+			return jsonResponse(t, http.StatusOK, map[string]bool{"result": true}), nil
+		}),
+	}
+	config, err := c.GetConfig()
+	ensure.Nil(t, err)
+	ensure.Nil(t, config.Set("welcome", "hi"))
+	ensure.Nil(t, c.UpdateConfig(config))
+}
+
+func TestConfigSetEmpty(t *testing.T) {
+	t.Parallel()
+	var config parse.Config
+	ensure.Nil(t, config.Set("enabled", false))
+	b, ok := config.GetBool("enabled")
+	ensure.True(t, ok)
+	ensure.False(t, b)
+}
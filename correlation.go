@@ -0,0 +1,33 @@
+package parse
+
+import (
+	"context"
+	"net/http"
+)
+
+const defaultCorrelationIDHeader = "X-Request-Id"
+
+type correlationIDKey struct{}
+
+// WithCorrelationID returns a context that sends id in the correlation header
+// of the requests made with it, so the logs of Parse Server can be joined
+// with those of the calling service. Errors returned for these requests
+// include the id.
+func WithCorrelationID(ctx context.Context, id string) context.Context {
+	return context.WithValue(ctx, correlationIDKey{}, id)
+}
+
+// setCorrelationID adds the correlation header from the request context, and
+// returns the id.
+func (c *Client) setCorrelationID(req *http.Request) string {
+	id, _ := req.Context().Value(correlationIDKey{}).(string)
+	if id == "" {
+		return ""
+	}
+	h := c.CorrelationIDHeader
+	if h == "" {
+		h = defaultCorrelationIDHeader
+	}
+	req.Header.Set(h, id)
+	return id
+}
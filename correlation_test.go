@@ -0,0 +1,54 @@
+package parse_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestCorrelationID(t *testing.T) {
+	t.Parallel()
+	var headers []string
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			headers = append(headers, r.Header.Get("X-Request-Id"), r.Header.Get("X-Trace"))
+			return jsonResponse(t, http.StatusNotFound, map[string]interface{}{
+				"code":  101,
+				"error": "object not found",
+			}), nil
+		}),
+	}
+	ctx := parse.WithCorrelationID(context.Background(), "req-1")
+	_, err := c.GetCtx(ctx, &url.URL{Path: "classes/Yak/a"}, nil)
+	ensure.DeepEqual(t, err, &parse.Error{
+		Code:       101,
+		Message:    "object not found",
+		StatusCode: http.StatusNotFound,
+		URL:        "https://api.parse.com/1/classes/Yak/a",
+		RequestID:  "req-1",
+	})
+	ensure.DeepEqual(t, err.Error(), `parse: api error with code=101 and message="object not found" for request req-1`)
+
+	c.CorrelationIDHeader = "X-Trace"
+	_, err = c.GetCtx(ctx, &url.URL{Path: "classes/Yak/a"}, nil)
+	ensure.NotNil(t, err)
+	_, err = c.Get(&url.URL{Path: "classes/Yak/a"}, nil)
+	ensure.DeepEqual(t, err.(*parse.Error).RequestID, "")
+	ensure.DeepEqual(t, headers, []string{"req-1", "", "", "req-1", "", ""})
+}
+
+func TestCorrelationIDRawError(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			return jsonResponse(t, http.StatusBadGateway, "bad gateway"), nil
+		}),
+	}
+	ctx := parse.WithCorrelationID(context.Background(), "req-2")
+	_, err := c.GetCtx(ctx, &url.URL{Path: "classes/Yak/a"}, nil)
+	ensure.DeepEqual(t, err.Error(), `parse: error with status=502 and body="\"bad gateway\"" for request req-2`)
+}
@@ -0,0 +1,291 @@
+package parse
+
+import (
+	"bytes"
+	"encoding/csv"
+	"encoding/json"
+	"fmt"
+	"io"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var errCSVRelation = fmt.Errorf("parse: %s fields cannot be used in CSV", FieldRelation)
+
+type csvColumn struct {
+	name  string
+	field Field
+}
+
+func (c csvColumn) header() string {
+	if c.field.Type == FieldString {
+		return c.name
+	}
+	return c.name + ":" + c.field.String()
+}
+
+func parseCSVHeader(h string) (csvColumn, error) {
+	i := strings.Index(h, ":")
+	if i == -1 {
+		return csvColumn{name: h, field: Field{Type: FieldString}}, nil
+	}
+	col := csvColumn{name: h[:i], field: Field{Type: h[i+1:]}}
+	if j := strings.Index(col.field.Type, "<"); j != -1 && strings.HasSuffix(col.field.Type, ">") {
+		col.field.TargetClass = col.field.Type[j+1 : len(col.field.Type)-1]
+		col.field.Type = col.field.Type[:j]
+	}
+	if col.name == "" || col.field.Type == "" {
+		return col, fmt.Errorf("parse: invalid CSV column %q", h)
+	}
+	if col.field.Type == FieldRelation {
+		return col, errCSVRelation
+	}
+	return col, nil
+}
+
+func (c csvColumn) encode(raw json.RawMessage) (string, error) {
+	if len(raw) == 0 || string(raw) == "null" {
+		return "", nil
+	}
+	switch c.field.Type {
+	case FieldString:
+		var s string
+		err := json.Unmarshal(raw, &s)
+		return s, err
+	case FieldNumber, FieldBoolean:
+		return string(bytes.TrimSpace(raw)), nil
+	case FieldDate:
+		// createdAt and updatedAt are plain strings rather than Dates.
+		var s string
+		if json.Unmarshal(raw, &s) == nil {
+			return s, nil
+		}
+		var d Date
+		if err := json.Unmarshal(raw, &d); err != nil {
+			return "", err
+		}
+		return time.Time(d).UTC().Format(dateLayout), nil
+	case FieldGeoPoint:
+		var g GeoPoint
+		if err := json.Unmarshal(raw, &g); err != nil {
+			return "", err
+		}
+		return strconv.FormatFloat(g.Latitude, 'g', -1, 64) + "," +
+			strconv.FormatFloat(g.Longitude, 'g', -1, 64), nil
+	case FieldPointer:
+		var p Pointer
+		err := json.Unmarshal(raw, &p)
+		return p.ID, err
+	case FieldFile:
+		var f File
+		err := json.Unmarshal(raw, &f)
+		return f.Name, err
+	}
+	var buf bytes.Buffer
+	err := json.Compact(&buf, raw)
+	return buf.String(), err
+}
+
+func (c csvColumn) decode(s string) (json.RawMessage, error) {
+	var v interface{}
+	switch c.field.Type {
+	case FieldString:
+		v = s
+	case FieldNumber:
+		f, err := strconv.ParseFloat(s, 64)
+		if err != nil || math.IsInf(f, 0) || math.IsNaN(f) {
+			return nil, fmt.Errorf("parse: invalid Number %q", s)
+		}
+		v = f
+	case FieldBoolean:
+		b, err := strconv.ParseBool(s)
+		if err != nil {
+			return nil, fmt.Errorf("parse: invalid Boolean %q", s)
+		}
+		v = b
+	case FieldDate:
+		t, err := time.Parse(time.RFC3339Nano, s)
+		if err != nil {
+			return nil, fmt.Errorf("parse: invalid Date %q", s)
+		}
+		v = Date(t)
+	case FieldGeoPoint:
+		var g GeoPoint
+		parts := strings.Split(s, ",")
+		var err1, err2 error
+		if len(parts) == 2 {
+			g.Latitude, err1 = strconv.ParseFloat(strings.TrimSpace(parts[0]), 64)
+			g.Longitude, err2 = strconv.ParseFloat(strings.TrimSpace(parts[1]), 64)
+		}
+		if len(parts) != 2 || err1 != nil || err2 != nil {
+			return nil, fmt.Errorf("parse: invalid GeoPoint %q", s)
+		}
+		v = g
+	case FieldPointer:
+		v = Pointer{ClassName: c.field.TargetClass, ID: s}
+	case FieldFile:
+		v = File{Name: s}
+	default:
+		if err := json.Unmarshal([]byte(s), &v); err != nil {
+			return nil, fmt.Errorf("parse: invalid %s %q", c.field.Type, s)
+		}
+	}
+	return json.Marshal(v)
+}
+
+// ExportCSV writes all matching objects to w as CSV, with one row per object.
+// The columns are objectId followed by Keys, or by all fields in the class
+// schema if Keys is empty.
+//
+// The header row names the columns as "field:Type", where Type is the schema
+// type of the field as rendered by Field.String, such as "Number" or
+// "Pointer<_User>". The types are taken from the class schema. Without a type
+// the column is a String. An empty cell means the field isn't set. Cells are
+// encoded depending on the type:
+//
+//	String    the string itself
+//	Number    the number, like 42 or 1.5
+//	Boolean   true or false
+//	Date      the ISO 8601 UTC time, like 2015-03-07T21:52:12.000Z
+//	GeoPoint  latitude and longitude separated by a comma, like 37.48,-122.15
+//	Pointer   the objectId of the target, the class is part of the header
+//	File      the file name
+//	others    the JSON encoded value, for Array, Object, ACL and so on
+//
+// Relation fields have no value of their own. They are left out, and are an
+// error when listed in Keys. The header row is not written when resuming an
+// export, since it is expected to append to the earlier output.
+func (e *Exporter) ExportCSV(w io.Writer) (*ExportResult, error) {
+	after, err := e.start()
+	if err != nil {
+		return nil, err
+	}
+	s, err := (&SchemaClient{Client: e.Client}).Get(e.ClassName)
+	if err != nil {
+		return nil, err
+	}
+	cols, err := e.csvColumns(s)
+	if err != nil {
+		return nil, err
+	}
+
+	cw := csv.NewWriter(w)
+	row := make([]string, len(cols))
+	if after == "" {
+		for i, c := range cols {
+			row[i] = c.header()
+		}
+		if err := cw.Write(row); err != nil {
+			return nil, err
+		}
+	}
+	flush := func() error {
+		cw.Flush()
+		return cw.Error()
+	}
+	res, err := e.each(after, flush, func(raw json.RawMessage) error {
+		var o map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &o); err != nil {
+			return err
+		}
+		for i, c := range cols {
+			cell, err := c.encode(o[c.name])
+			if err != nil {
+				return fmt.Errorf("parse: cannot encode %s as CSV: %s", c.name, err)
+			}
+			row[i] = cell
+		}
+		return cw.Write(row)
+	})
+	cw.Flush()
+	if err == nil {
+		err = cw.Error()
+	}
+	return res, err
+}
+
+func (e *Exporter) csvColumns(s *Schema) ([]csvColumn, error) {
+	names := e.Keys
+	if len(names) == 0 {
+		for name, f := range s.Fields {
+			if f.Type != FieldRelation && name != "objectId" {
+				names = append(names, name)
+			}
+		}
+		sort.Strings(names)
+	}
+	cols := []csvColumn{{name: "objectId", field: Field{Type: FieldString}}}
+	for _, name := range names {
+		f, ok := s.Fields[name]
+		if !ok {
+			return nil, fmt.Errorf("parse: class %s has no field %s", e.ClassName, name)
+		}
+		if f.Type == FieldRelation {
+			return nil, errCSVRelation
+		}
+		if name != "objectId" {
+			cols = append(cols, csvColumn{name: name, field: f})
+		}
+	}
+	return cols, nil
+}
+
+// ImportCSV reads all records from r as CSV, using the columns written by
+// Exporter.ExportCSV. It behaves like Import, and the line of an ImportError is
+// the number of the CSV record, counting the header as line 1.
+func (i *Importer) ImportCSV(r io.Reader) (*ImportResult, error) {
+	cr := csv.NewReader(r)
+	h, err := cr.Read()
+	if err == io.EOF {
+		return &ImportResult{}, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	cols := make([]csvColumn, len(h))
+	for n, cell := range h {
+		if cols[n], err = parseCSVHeader(cell); err != nil {
+			return nil, err
+		}
+	}
+	return i.run(&csvImportSource{r: cr, cols: cols, line: 1})
+}
+
+type csvImportSource struct {
+	r    *csv.Reader
+	cols []csvColumn
+	line int
+}
+
+func (s *csvImportSource) next() (importRecord, error) {
+	row, err := s.r.Read()
+	if err != nil {
+		if _, ok := err.(*csv.ParseError); !ok {
+			return importRecord{}, err
+		}
+	}
+	s.line++
+	rec := importRecord{line: s.line}
+	if err != nil {
+		return rec, &ImportError{Line: s.line, Err: err}
+	}
+	rec.body = make(map[string]json.RawMessage, len(row))
+	for n, cell := range row {
+		if cell == "" {
+			continue
+		}
+		c := s.cols[n]
+		v, err := c.decode(cell)
+		if err != nil {
+			return rec, &ImportError{Line: s.line, Err: err}
+		}
+		rec.body[c.name] = v
+	}
+	if err := rec.init(); err != nil {
+		return rec, err
+	}
+	return rec, nil
+}
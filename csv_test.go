@@ -0,0 +1,149 @@
+package parse_test
+
+import (
+	"bytes"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestExportCSV(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			switch r.URL.Path {
+			case "/1/schemas/Yak":
+				return jsonResponse(t, http.StatusOK, map[string]interface{}{
+					"className": "Yak",
+					"fields": map[string]interface{}{
+						"objectId":  map[string]string{"type": "String"},
+						"createdAt": map[string]string{"type": "Date"},
+						"name":      map[string]string{"type": "String"},
+						"age":       map[string]string{"type": "Number"},
+						"hairy":     map[string]string{"type": "Boolean"},
+						"born":      map[string]string{"type": "Date"},
+						"location":  map[string]string{"type": "GeoPoint"},
+						"owner":     map[string]string{"type": "Pointer", "targetClass": "_User"},
+						"photo":     map[string]string{"type": "File"},
+						"tags":      map[string]string{"type": "Array"},
+						"likes":     map[string]string{"type": "Relation", "targetClass": "_User"},
+					},
+				}), nil
+			case "/1/classes/Yak":
+				if res := endOfPages(t, r); res != nil {
+					return res, nil
+				}
+				return jsonResponse(t, http.StatusOK, map[string]interface{}{
+					"results": []map[string]interface{}{
+						{
+							"objectId":  "a",
+							"createdAt": "2015-03-07T21:52:12.000Z",
+							"name":      "Al, the yak",
+							"age":       7,
+							"hairy":     true,
+							"born":      map[string]string{"__type": "Date", "iso": "2008-01-02T03:04:05.000Z"},
+							"location":  map[string]interface{}{"__type": "GeoPoint", "latitude": 37.48, "longitude": -122.15},
+							"owner":     map[string]string{"__type": "Pointer", "className": "_User", "objectId": "u"},
+							"photo":     map[string]string{"__type": "File", "name": "al.jpg", "url": "http://x/al.jpg"},
+							"tags":      []string{"big", "brown"},
+						},
+						{"objectId": "b"},
+					},
+				}), nil
+			}
+			panic("unexpected request " + r.URL.Path)
+		}),
+	}
+	var buf bytes.Buffer
+	res, err := (&parse.Exporter{Client: c, ClassName: "Yak"}).ExportCSV(&buf)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res, &parse.ExportResult{Count: 2, LastID: "b"})
+	ensure.DeepEqual(t, buf.String(),
+		"objectId,age:Number,born:Date,createdAt:Date,hairy:Boolean,location:GeoPoint,name,owner:Pointer<_User>,photo:File,tags:Array\n"+
+			`a,7,2008-01-02T03:04:05.000Z,2015-03-07T21:52:12.000Z,true,"37.48,-122.15","Al, the yak",u,al.jpg,"[""big"",""brown""]"`+"\n"+
+			"b,,,,,,,,,\n")
+}
+
+func TestExportCSVKeys(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			return jsonResponse(t, http.StatusOK, map[string]interface{}{
+				"className": "Yak",
+				"fields": map[string]interface{}{
+					"likes": map[string]string{"type": "Relation", "targetClass": "_User"},
+				},
+			}), nil
+		}),
+	}
+	e := parse.Exporter{Client: c, ClassName: "Yak", Keys: []string{"name"}}
+	_, err := e.ExportCSV(&bytes.Buffer{})
+	ensure.Err(t, err, regexp.MustCompile("class Yak has no field name"))
+
+	e.Keys = []string{"likes"}
+	_, err = e.ExportCSV(&bytes.Buffer{})
+	ensure.Err(t, err, regexp.MustCompile("Relation fields cannot be used in CSV"))
+}
+
+func TestImportCSV(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			var body batchBody
+			decodeBody(t, r, &body)
+			ensure.DeepEqual(t, len(body.Requests), 2)
+			ensure.DeepEqual(t, body.Requests[0].Method, "PUT")
+			ensure.DeepEqual(t, body.Requests[0].Path, "/1/classes/Yak/a")
+			ensure.DeepEqual(t, body.Requests[0].Body, map[string]interface{}{
+				"name":     "Al, the yak",
+				"age":      7.0,
+				"hairy":    true,
+				"born":     map[string]interface{}{"__type": "Date", "iso": "2008-01-02T03:04:05.000Z"},
+				"location": map[string]interface{}{"__type": "GeoPoint", "latitude": 37.48, "longitude": -122.15},
+				"owner":    map[string]interface{}{"__type": "Pointer", "className": "_User", "objectId": "u"},
+				"photo":    map[string]interface{}{"__type": "File", "name": "al.jpg"},
+				"tags":     []interface{}{"big", "brown"},
+			})
+			ensure.DeepEqual(t, body.Requests[1].Method, "POST")
+			ensure.DeepEqual(t, body.Requests[1].Body, map[string]interface{}{"name": "Bo"})
+			return jsonResponse(t, http.StatusOK, []interface{}{
+				map[string]interface{}{"success": map[string]string{}},
+				map[string]interface{}{"success": map[string]string{"objectId": "b"}},
+			}), nil
+		}),
+	}
+	in := "objectId,age:Number,born:Date,createdAt:Date,hairy:Boolean,location:GeoPoint,name,owner:Pointer<_User>,photo:File,tags:Array\n" +
+		`a,7,2008-01-02T03:04:05.000Z,2015-03-07T21:52:12.000Z,true,"37.48,-122.15","Al, the yak",u,al.jpg,"[""big"",""brown""]"` + "\n" +
+		",,,,,,Bo,,,\n" +
+		",seven,,,,,,,,\n" +
+		",,,,,,,,\n"
+	res, err := (&parse.Importer{Client: c, ClassName: "Yak"}).ImportCSV(strings.NewReader(in))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res.Created, 1)
+	ensure.DeepEqual(t, res.Updated, 1)
+	ensure.DeepEqual(t, len(res.Errors), 2)
+	ensure.DeepEqual(t, res.Errors[0].Line, 4)
+	ensure.Err(t, res.Errors[0].Err, regexp.MustCompile(`invalid Number "seven"`))
+	ensure.DeepEqual(t, res.Errors[1].Line, 5)
+	ensure.Err(t, res.Errors[1].Err, regexp.MustCompile("wrong number of fields"))
+}
+
+func TestImportCSVHeader(t *testing.T) {
+	t.Parallel()
+	i := parse.Importer{ClassName: "Yak"}
+	for h, re := range map[string]string{
+		"likes:Relation<_User>\n": "Relation fields cannot be used in CSV",
+		":Number\n":               `invalid CSV column ":Number"`,
+		"age:\n":                  `invalid CSV column "age:"`,
+	} {
+		_, err := i.ImportCSV(strings.NewReader(h))
+		ensure.Err(t, err, regexp.MustCompile(re))
+	}
+	res, err := i.ImportCSV(strings.NewReader(""))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res, &parse.ImportResult{})
+}
@@ -0,0 +1,50 @@
+package parse
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"net/http/httputil"
+	"net/url"
+)
+
+// dumpRequest writes the request to Debug. The body is read and replaced, so
+// the request can still be sent.
+func (c *Client) dumpRequest(req *http.Request, attempt int) {
+	r := *req
+	r.Header = redactHeader(req.Header)
+	if u, err := url.Parse(c.redactURL(req.URL)); err == nil {
+		r.URL = u
+	}
+	dump, err := httputil.DumpRequestOut(&r, true)
+	req.Body = r.Body
+	c.dump(fmt.Sprintf("request attempt %d", attempt), dump, err)
+}
+
+// dumpResponse writes the response to Debug. The body is read and replaced, so
+// the response can still be used.
+func (c *Client) dumpResponse(res *http.Response, err error) {
+	if err != nil {
+		c.dump("response", nil, err)
+		return
+	}
+	header := res.Header
+	res.Header = redactHeader(header)
+	dump, err := httputil.DumpResponse(res, true)
+	res.Header = header
+	c.dump("response", dump, err)
+}
+
+func (c *Client) dump(title string, dump []byte, err error) {
+	var buf bytes.Buffer
+	fmt.Fprintf(&buf, "--- parse: %s\n", title)
+	if err != nil {
+		fmt.Fprintf(&buf, "error: %s\n", err)
+	} else {
+		buf.Write(c.redactDump(dump))
+		if len(dump) != 0 && dump[len(dump)-1] != '\n' {
+			buf.WriteByte('\n')
+		}
+	}
+	c.Debug.Write(buf.Bytes())
+}
@@ -0,0 +1,44 @@
+package parse_test
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestDebug(t *testing.T) {
+	t.Parallel()
+	var debug bytes.Buffer
+	c := &parse.Client{
+		Credentials: defaultRestAPIKey,
+		Debug:       &debug,
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			var body map[string]string
+			decodeBody(t, r, &body)
+			ensure.DeepEqual(t, body, map[string]string{"username": "al", "password": "secret"})
+			ensure.DeepEqual(t, r.Header.Get("X-Parse-REST-API-Key"), defaultRestAPIKey.RestAPIKey)
+			return jsonResponse(t, http.StatusCreated, map[string]string{"sessionToken": "r:token"}), nil
+		}),
+	}
+	var res map[string]string
+	_, err := c.Post(&url.URL{Path: "users"}, map[string]string{"username": "al", "password": "secret"}, &res)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res, map[string]string{"sessionToken": "r:token"})
+
+	dump := debug.String()
+	ensure.StringContains(t, dump, "--- parse: request attempt 1\nPOST /1/users HTTP/1.1")
+	ensure.StringContains(t, dump, "X-Parse-Rest-Api-Key: REDACTED")
+	ensure.StringContains(t, dump, `"password":"REDACTED"`)
+	ensure.StringContains(t, dump, `"username":"al"`)
+	ensure.StringContains(t, dump, "--- parse: response\n")
+	ensure.StringContains(t, dump, "201 Created")
+	ensure.StringContains(t, dump, `"sessionToken":"REDACTED"`)
+	ensure.False(t, strings.Contains(dump, "secret"))
+	ensure.False(t, strings.Contains(dump, "r:token"))
+	ensure.False(t, strings.Contains(dump, defaultRestAPIKey.RestAPIKey))
+}
@@ -0,0 +1,118 @@
+package parse
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"reflect"
+)
+
+// snippetContext is the number of bytes shown on each side of the position of
+// a decoding error.
+const snippetContext = 40
+
+// DecodeError is returned when a successful response cannot be decoded into
+// the result.
+type DecodeError struct {
+	// Type of the result, such as "*main.Yak".
+	Type string
+
+	// Offset in the response body where decoding failed.
+	Offset int64
+
+	// Snippet of the response body around the Offset, with credentials,
+	// session tokens and passwords redacted.
+	Snippet string
+
+	// Err is the error of the JSON decoder.
+	Err error
+}
+
+func (e *DecodeError) Error() string {
+	return fmt.Sprintf("parse: cannot decode response into %s at offset %d near %q: %s",
+		e.Type, e.Offset, e.Snippet, e.Err)
+}
+
+// Unwrap returns the error of the JSON decoder.
+func (e *DecodeError) Unwrap() error {
+	return e.Err
+}
+
+// decode decodes the response body into result.
+func (c *Client) decode(body []byte, result interface{}) error {
+	err := c.decodeBody(body, result)
+	if _, ok := err.(*json.UnmarshalTypeError); ok {
+		if t := reflect.TypeOf(result); c.Lenient || hasLenientFields(t, map[reflect.Type]bool{}) {
+			if coerced, cerr := c.coerceBody(body, t); cerr == nil {
+				err = c.decodeBody(coerced, result)
+			}
+		}
+	}
+	if err == nil {
+		return nil
+	}
+	var offset int64
+	switch e := err.(type) {
+	case *json.SyntaxError:
+		offset = e.Offset
+	case *json.UnmarshalTypeError:
+		offset = e.Offset
+	}
+	return &DecodeError{
+		Type:    reflect.TypeOf(result).String(),
+		Offset:  offset,
+		Snippet: c.snippet(body, offset),
+		Err:     err,
+	}
+}
+
+// snippet returns the redacted body around the offset. The body is redacted
+// before the window is cut, as a window starting inside a sensitive field
+// would no longer match. The body is split at the offset, or at the start of
+// the sensitive field containing it, to find the offset in the redacted body.
+func (c *Client) snippet(body []byte, offset int64) string {
+	if offset > int64(len(body)) {
+		offset = int64(len(body))
+	}
+	cut := offset
+	for _, m := range sensitiveJSON.FindAllIndex(body, -1) {
+		if int64(m[0]) < offset && offset < int64(m[1]) {
+			cut = int64(m[0])
+			break
+		}
+	}
+	head := c.redactDump(body[:cut])
+	red := append(append([]byte{}, head...), c.redactDump(body[cut:])...)
+	offset = int64(len(head))
+	start, end := offset-snippetContext, offset+snippetContext
+	if start < 0 {
+		start = 0
+	}
+	if end > int64(len(red)) {
+		end = int64(len(red))
+	}
+	return string(red[start:end])
+}
+
+func (c *Client) decodeBody(body []byte, result interface{}) error {
+	d := c.codec().NewDecoder(bytes.NewReader(body))
+	if c.DisallowUnknownFields {
+		d.DisallowUnknownFields()
+	}
+	if c.UseNumber {
+		d.UseNumber()
+	}
+	return d.Decode(result)
+}
+
+// coerceBody returns the body with the values that don't match the type of
+// the result coerced where allowed, by Lenient or by the field tags.
+func (c *Client) coerceBody(body []byte, t reflect.Type) ([]byte, error) {
+	d := json.NewDecoder(bytes.NewReader(body))
+	d.UseNumber()
+	var v interface{}
+	if err := d.Decode(&v); err != nil {
+		return nil, err
+	}
+	return json.Marshal(coerce(v, t, c.Lenient))
+}
@@ -0,0 +1,173 @@
+package parse_test
+
+import (
+	"encoding/json"
+	"errors"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func bodyResponse(body string) transportFunc {
+	return func(r *http.Request) (*http.Response, error) {
+		return &http.Response{
+			StatusCode: http.StatusOK,
+			Body:       ioutil.NopCloser(strings.NewReader(body)),
+		}, nil
+	}
+}
+
+type decodeYak struct {
+	Name string `json:"name"`
+	Age  int    `json:"age"`
+}
+
+func TestDecodeErrorType(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{Transport: bodyResponse(`{"name":"Al","age":"old","sessionToken":"r:secret"}`)}
+	var yak decodeYak
+	_, err := c.Get(nil, &yak)
+	var de *parse.DecodeError
+	ensure.True(t, errors.As(err, &de))
+	ensure.DeepEqual(t, de.Type, "*parse_test.decodeYak")
+	ensure.DeepEqual(t, de.Offset, int64(24))
+	ensure.StringContains(t, de.Snippet, `"age":"old"`)
+	ensure.StringContains(t, de.Snippet, `"sessionToken":"REDACTED"`)
+	var te *json.UnmarshalTypeError
+	ensure.True(t, errors.As(err, &te))
+	ensure.StringContains(t, err.Error(), "at offset 24")
+}
+
+func TestDecodeErrorSecretAtWindowStart(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{Transport: bodyResponse(
+		`{"name":"Al","password":"hunter2-secret-value","age":"old"}`)}
+	var yak decodeYak
+	_, err := c.Get(nil, &yak)
+	var de *parse.DecodeError
+	ensure.True(t, errors.As(err, &de))
+	ensure.DeepEqual(t, de.Offset, int64(58))
+	ensure.StringContains(t, de.Snippet, `"password":"REDACTED","age":"old"`)
+	ensure.StringDoesNotContain(t, err.Error(), "hunter2")
+}
+
+func TestDecodeErrorSyntax(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{Transport: bodyResponse(`{"results":[1,2,}`)}
+	var res map[string][]int
+	_, err := c.Get(nil, &res)
+	ensure.DeepEqual(t, err.Error(),
+		`parse: cannot decode response into *map[string][]int at offset 17 near "{\"results\":[1,2,}": invalid character '}' looking for beginning of value`)
+}
+
+func TestDisallowUnknownFields(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{Transport: bodyResponse(`{"name":"Al","age":3,"color":"brown"}`)}
+	var yak decodeYak
+	_, err := c.Get(nil, &yak)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, yak, decodeYak{Name: "Al", Age: 3})
+
+	c.DisallowUnknownFields = true
+	_, err = c.Get(nil, &yak)
+	ensure.Err(t, err, regexp.MustCompile(`unknown field "color"`))
+	var de *parse.DecodeError
+	ensure.True(t, errors.As(err, &de))
+}
+
+func TestUseNumber(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{Transport: bodyResponse(`{"id":9007199254740993}`)}
+	var res map[string]interface{}
+	_, err := c.Get(nil, &res)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res["id"], float64(9007199254740992))
+
+	c.UseNumber = true
+	res = nil
+	_, err = c.Get(nil, &res)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res["id"], json.Number("9007199254740993"))
+}
+
+type legacyYak struct {
+	Name    string  `json:"name"`
+	Age     int     `json:"age"`
+	Weight  float64 `json:"weight"`
+	Active  bool    `json:"active"`
+	Version string  `json:"version"`
+}
+
+func TestLenient(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{Transport: bodyResponse(`{"results":[
+		{"name":"Al","age":"3","weight":"12.5","active":1,"version":2},
+		{"name":"Bo","age":4,"weight":10,"active":"false","version":"1.1"},
+		{"name":"Cy","age":"","active":0}]}`)}
+	var res struct {
+		Results []legacyYak `json:"results"`
+	}
+	_, err := c.Get(nil, &res)
+	var te *json.UnmarshalTypeError
+	ensure.True(t, errors.As(err, &te))
+
+	c.Lenient = true
+	res.Results = nil
+	_, err = c.Get(nil, &res)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res.Results, []legacyYak{
+		{Name: "Al", Age: 3, Weight: 12.5, Active: true, Version: "2"},
+		{Name: "Bo", Age: 4, Weight: 10, Active: false, Version: "1.1"},
+		{Name: "Cy"},
+	})
+}
+
+func TestLenientNotJSONNumber(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{Lenient: true, Transport: bodyResponse(`{"age":"5","zip":"02139","weight":".5","legs":"+4"}`)}
+	var yak struct {
+		Age    int     `json:"age"`
+		Zip    int     `json:"zip"`
+		Weight float64 `json:"weight"`
+		Legs   int     `json:"legs"`
+	}
+	_, err := c.Get(nil, &yak)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, yak.Age, 5)
+	ensure.DeepEqual(t, yak.Zip, 2139)
+	ensure.DeepEqual(t, yak.Weight, 0.5)
+	ensure.DeepEqual(t, yak.Legs, 4)
+}
+
+func TestLenientInvalid(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{Lenient: true, Transport: bodyResponse(`{"name":"Al","age":"old"}`)}
+	var yak legacyYak
+	_, err := c.Get(nil, &yak)
+	var de *parse.DecodeError
+	ensure.True(t, errors.As(err, &de))
+	ensure.StringContains(t, de.Snippet, `"age":"old"`)
+}
+
+func TestLenientTag(t *testing.T) {
+	t.Parallel()
+	var yak struct {
+		Age    int  `json:"age" parse:"lenient"`
+		Active bool `json:"active"`
+	}
+	c := &parse.Client{Transport: bodyResponse(`{"age":"3","active":true}`)}
+	_, err := c.Get(nil, &yak)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, yak.Age, 3)
+	ensure.True(t, yak.Active)
+
+	// Fields without the tag are not coerced.
+	c.Transport = bodyResponse(`{"age":"3","active":1}`)
+	_, err = c.Get(nil, &yak)
+	ensure.Err(t, err, regexp.MustCompile(`cannot unmarshal number into Go struct field .*active of type bool`))
+}
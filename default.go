@@ -0,0 +1,38 @@
+package parse
+
+import (
+	"net/http"
+	"net/url"
+)
+
+// DefaultClient is used by the package level functions. Configure it once,
+// usually with its Credentials, before using them:
+//
+//	parse.DefaultClient.Credentials = parse.RestAPIKey{ApplicationID: id, RestAPIKey: key}
+//	_, err := parse.Get(&url.URL{Path: "classes/GameScore/" + id}, &score)
+var DefaultClient = &Client{}
+
+// Get is DefaultClient.Get.
+func Get(u *url.URL, result interface{}) (*http.Response, error) {
+	return DefaultClient.Get(u, result)
+}
+
+// Post is DefaultClient.Post.
+func Post(u *url.URL, body, result interface{}) (*http.Response, error) {
+	return DefaultClient.Post(u, body, result)
+}
+
+// Put is DefaultClient.Put.
+func Put(u *url.URL, body, result interface{}) (*http.Response, error) {
+	return DefaultClient.Put(u, body, result)
+}
+
+// Delete is DefaultClient.Delete.
+func Delete(u *url.URL, result interface{}) (*http.Response, error) {
+	return DefaultClient.Delete(u, result)
+}
+
+// Query is DefaultClient.Query.
+func Query(className string, p *Params, result interface{}) (*http.Response, error) {
+	return DefaultClient.Query(className, p, result)
+}
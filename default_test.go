@@ -0,0 +1,43 @@
+package parse_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+// TestDefaultClient is not parallel since it replaces the DefaultClient.
+func TestDefaultClient(t *testing.T) {
+	var methods []string
+	saved := parse.DefaultClient
+	defer func() { parse.DefaultClient = saved }()
+	parse.DefaultClient = &parse.Client{
+		Credentials: defaultRestAPIKey,
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Header.Get("X-Parse-REST-API-Key"), defaultRestAPIKey.RestAPIKey)
+			methods = append(methods, r.Method+" "+r.URL.Path)
+			return jsonResponse(t, http.StatusOK, map[string]interface{}{}), nil
+		}),
+	}
+	u := &url.URL{Path: "classes/Yak/abc"}
+	_, err := parse.Get(u, nil)
+	ensure.Nil(t, err)
+	_, err = parse.Post(&url.URL{Path: "classes/Yak"}, map[string]string{"name": "yak"}, nil)
+	ensure.Nil(t, err)
+	_, err = parse.Put(u, map[string]string{"name": "yak"}, nil)
+	ensure.Nil(t, err)
+	_, err = parse.Delete(u, nil)
+	ensure.Nil(t, err)
+	_, err = parse.Query("Yak", nil, nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, methods, []string{
+		"GET /1/classes/Yak/abc",
+		"POST /1/classes/Yak",
+		"PUT /1/classes/Yak/abc",
+		"DELETE /1/classes/Yak/abc",
+		"GET /1/classes/Yak",
+	})
+}
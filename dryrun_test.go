@@ -0,0 +1,67 @@
+package parse_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestDryRun(t *testing.T) {
+	t.Parallel()
+	var logs []parse.RequestLog
+	sent := 0
+	c := &parse.Client{
+		DryRun: true,
+		Logger: parse.LoggerFunc(func(l parse.RequestLog) { logs = append(logs, l) }),
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			sent++
+			ensure.DeepEqual(t, r.Method, "GET")
+			return jsonResponse(t, http.StatusOK, map[string]string{"objectId": "y1"}), nil
+		}),
+	}
+	o := parse.ObjectClient{Client: c, Path: "classes/Yak"}
+	created, err := o.Post(map[string]string{"name": "Al"})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(created.ID), 10)
+	ensure.False(t, created.CreatedAt.IsZero())
+	updated, err := o.Put("y1", map[string]string{"name": "Bo"})
+	ensure.Nil(t, err)
+	ensure.False(t, updated.UpdatedAt.IsZero())
+	_, err = o.Delete("y1")
+	ensure.Nil(t, err)
+	var got map[string]string
+	_, err = o.Get("y1", &got)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, got, map[string]string{"objectId": "y1"})
+
+	res, err := c.Batch([]parse.BatchRequest{
+		{Method: "POST", Path: "classes/Yak", Body: map[string]string{}},
+		{Method: "DELETE", Path: "classes/Yak/y1"},
+	})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(res), 2)
+	var batchCreated parse.CreateResponse
+	ensure.Nil(t, json.Unmarshal(res[0].Success, &batchCreated))
+	ensure.DeepEqual(t, len(batchCreated.ID), 10)
+	ensure.DeepEqual(t, string(res[1].Success), "{}")
+
+	ensure.DeepEqual(t, sent, 1)
+	var dry []string
+	for _, l := range logs {
+		if l.DryRun {
+			dry = append(dry, l.Method)
+		}
+	}
+	ensure.DeepEqual(t, dry, []string{"POST", "PUT", "DELETE", "POST"})
+}
+
+func TestDryRunInvalidBody(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{DryRun: true, Logger: parse.LoggerFunc(func(parse.RequestLog) {})}
+	_, err := c.Post(&url.URL{Path: "classes/Yak"}, json.RawMessage(`{"name":`), nil)
+	ensure.NotNil(t, err)
+}
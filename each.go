@@ -0,0 +1,158 @@
+package parse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+const (
+	defaultEachBatchSize      = 1000
+	defaultEachRateLimitPause = 30 * time.Second
+)
+
+var errEachWhereObjectID = errors.New("parse: each where clause cannot constrain objectId")
+
+// EachOptions configures ObjectClient.Each. The zero value fetches pages of
+// 1000 objects as fast as the server answers.
+type EachOptions struct {
+	// BatchSize is the number of objects fetched per request. Defaults to
+	// 1000, the maximum allowed by the API.
+	BatchSize int
+
+	// RequestsPerSecond optionally limits the rate of the requests, so that a
+	// background job leaves the request limit of the application to
+	// interactive traffic.
+	RequestsPerSecond float64
+
+	// RateLimitPause is how long to pause after the server rejects a request
+	// for exceeding the request limit, when it doesn't say how long with a
+	// Retry-After header. The request is then sent again. Defaults to 30
+	// seconds.
+	RateLimitPause time.Duration
+
+	// Reporter optionally receives progress reports. The matching objects
+	// are counted first, so that the reports have a Total.
+	Reporter ProgressReporter
+}
+
+// Each calls fn with every object matching the where clause. Objects are
+// fetched in objectId order and each page starts after the last objectId seen,
+// like Exporter, so the where clause cannot constrain objectId. A nil where
+// matches all objects and nil options use the defaults. Rate limited requests
+// pause and resume instead of failing. An error returned by fn stops the
+// traversal and is returned.
+func (o *ObjectClient) Each(where Where, opt *EachOptions, fn func(json.RawMessage) error) error {
+	return o.EachCtx(context.Background(), where, opt, fn)
+}
+
+// EachCtx is Each with a context used for the requests and pauses.
+func (o *ObjectClient) EachCtx(ctx context.Context, where Where, opt *EachOptions, fn func(json.RawMessage) error) error {
+	if _, ok := where["objectId"]; ok {
+		return errEachWhereObjectID
+	}
+	if opt == nil {
+		opt = &EachOptions{}
+	}
+	batchSize := opt.BatchSize
+	if batchSize <= 0 {
+		batchSize = defaultEachBatchSize
+	}
+	pause := opt.RateLimitPause
+	if pause <= 0 {
+		pause = defaultEachRateLimitPause
+	}
+	var interval time.Duration
+	if opt.RequestsPerSecond > 0 {
+		interval = time.Duration(float64(time.Second) / opt.RequestsPerSecond)
+	}
+	clock := o.Client.clock()
+
+	var p *progress
+	if opt.Reporter != nil {
+		total, err := o.CountCtx(ctx, where)
+		if err != nil {
+			return err
+		}
+		p = newProgress(opt.Reporter, o.Client, o.Path, total)
+	}
+
+	var (
+		after string
+		count int
+		last  time.Time
+	)
+	for {
+		if interval > 0 && !last.IsZero() {
+			if err := sleep(ctx, clock, interval-clock.Now().Sub(last)); err != nil {
+				return err
+			}
+		}
+		last = clock.Now()
+
+		w := make(Where, len(where)+1)
+		for k, v := range where {
+			w[k] = v
+		}
+		if after != "" {
+			w.GreaterThan("objectId", after)
+		}
+		params := Params{Order: []string{"objectId"}, Limit: batchSize}
+		if len(w) != 0 {
+			params.Where = w
+		}
+		var page struct {
+			Results []json.RawMessage `json:"results"`
+		}
+		_, err := o.QueryCtx(ctx, &params, &page)
+		var rerr *RateLimitError
+		if errors.As(err, &rerr) {
+			d := rerr.RetryAfter
+			if d <= 0 {
+				d = pause
+			}
+			if err := sleep(ctx, clock, d); err != nil {
+				return err
+			}
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		for _, raw := range page.Results {
+			var id struct {
+				ID string `json:"objectId"`
+			}
+			if err := json.Unmarshal(raw, &id); err != nil {
+				return err
+			}
+			if err := fn(raw); err != nil {
+				return err
+			}
+			count++
+			after = id.ID
+		}
+		// The server may return fewer objects than asked for when its
+		// maxLimit is lower, so only an empty page ends the traversal.
+		done := len(page.Results) == 0
+		p.report(count, 0, done)
+		if done {
+			return nil
+		}
+	}
+}
+
+// sleep waits for d on the clock, or until the context is done.
+func sleep(ctx context.Context, clock Clock, d time.Duration) error {
+	if d <= 0 {
+		return nil
+	}
+	select {
+	case <-clock.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
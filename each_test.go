@@ -0,0 +1,138 @@
+package parse_test
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+	"github.com/facebookgo/parse/parsetest"
+)
+
+func TestEach(t *testing.T) {
+	t.Parallel()
+	s := parsetest.NewServer()
+	defer s.Close()
+	o := parse.ObjectClient{Client: s.Client(), Path: "classes/Yak"}
+	yaks := make([]map[string]int, 5)
+	for n := range yaks {
+		yaks[n] = map[string]int{"n": n}
+	}
+	_, err := o.PostAll(yaks)
+	ensure.Nil(t, err)
+
+	var seen []int
+	var p progressReports
+	err = o.Each(parse.Where{}.GreaterThan("n", 0), &parse.EachOptions{BatchSize: 2, Reporter: &p}, func(raw json.RawMessage) error {
+		var y struct {
+			N int `json:"n"`
+		}
+		ensure.Nil(t, json.Unmarshal(raw, &y))
+		seen = append(seen, y.N)
+		return nil
+	})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(seen), 4)
+	last := p.reports[len(p.reports)-1]
+	ensure.DeepEqual(t, last.Processed, 4)
+	ensure.DeepEqual(t, last.Total, 4)
+	ensure.True(t, last.Done)
+
+	stop := errors.New("stop")
+	err = o.Each(nil, nil, func(json.RawMessage) error { return stop })
+	ensure.True(t, err == stop)
+
+	err = o.Each(parse.Where{}.EqualTo("objectId", "a"), nil, nil)
+	ensure.Err(t, err, regexp.MustCompile("cannot constrain objectId"))
+}
+
+func TestEachMaxLimit(t *testing.T) {
+	t.Parallel()
+	s := parsetest.NewServer()
+	defer s.Close()
+	c := s.Client()
+	o := parse.ObjectClient{Client: c, Path: "classes/Yak"}
+	_, err := o.PostAll([]map[string]int{{"n": 1}, {"n": 2}, {"n": 3}})
+	ensure.Nil(t, err)
+
+	// The server returns at most 2 objects, fewer than the BatchSize.
+	c.Middleware = append(c.Middleware, func(next http.RoundTripper) http.RoundTripper {
+		return transportFunc(func(r *http.Request) (*http.Response, error) {
+			q := r.URL.Query()
+			q.Set("limit", "2")
+			r.URL.RawQuery = q.Encode()
+			return next.RoundTrip(r)
+		})
+	})
+	count := 0
+	err = o.Each(nil, nil, func(json.RawMessage) error {
+		count++
+		return nil
+	})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, count, 3)
+}
+
+func TestEachThrottled(t *testing.T) {
+	t.Parallel()
+	clock := parsetest.NewClock(time.Date(2015, 6, 16, 1, 2, 3, 0, time.UTC))
+	calls := 0
+	c := &parse.Client{
+		Clock: clock,
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			switch calls {
+			case 1:
+				return jsonResponse(t, http.StatusOK, map[string]interface{}{
+					"results": []map[string]string{{"objectId": "a"}},
+				}), nil
+			case 2:
+				res := jsonResponse(t, http.StatusTooManyRequests, map[string]interface{}{"code": 155, "error": "limit"})
+				res.Header = http.Header{"Retry-After": {"5"}}
+				return res, nil
+			}
+			ensure.DeepEqual(t, r.URL.Query().Get("where"), `{"objectId":{"$gt":"a"}}`)
+			return jsonResponse(t, http.StatusOK, map[string]interface{}{"results": []interface{}{}}), nil
+		}),
+	}
+	o := parse.ObjectClient{Client: c, Path: "classes/Yak"}
+	done := make(chan error)
+	go func() {
+		done <- o.Each(nil, &parse.EachOptions{BatchSize: 1, RequestsPerSecond: 1}, func(json.RawMessage) error {
+			return nil
+		})
+	}()
+	waitFor := func(d time.Duration) {
+		for clock.Waiters() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		clock.Advance(d)
+	}
+	waitFor(time.Second)
+	waitFor(5 * time.Second)
+	ensure.Nil(t, <-done)
+	ensure.DeepEqual(t, calls, 3)
+}
+
+func TestEachCancelDuringPause(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Clock: parsetest.NewClock(time.Time{}),
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			return jsonResponse(t, http.StatusTooManyRequests, map[string]interface{}{"code": 155, "error": "limit"}), nil
+		}),
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	o := parse.ObjectClient{Client: c, Path: "classes/Yak"}
+	done := make(chan error)
+	go func() {
+		done <- o.EachCtx(ctx, nil, nil, func(json.RawMessage) error { return nil })
+	}()
+	cancel()
+	ensure.True(t, errors.Is(<-done, context.Canceled))
+}
@@ -0,0 +1,86 @@
+package parse
+
+import "errors"
+
+// The error codes of the Parse API, found in the Code of an Error.
+const (
+	CodeOtherCause                  = -1
+	CodeInternalServerError         = 1
+	CodeConnectionFailed            = 100
+	CodeObjectNotFound              = 101
+	CodeInvalidQuery                = 102
+	CodeInvalidClassName            = 103
+	CodeMissingObjectID             = 104
+	CodeInvalidKeyName              = 105
+	CodeInvalidPointer              = 106
+	CodeInvalidJSON                 = 107
+	CodeCommandUnavailable          = 108
+	CodeNotInitialized              = 109
+	CodeIncorrectType               = 111
+	CodeInvalidChannelName          = 112
+	CodePushMisconfigured           = 115
+	CodeObjectTooLarge              = 116
+	CodeOperationForbidden          = 119
+	CodeCacheMiss                   = 120
+	CodeInvalidNestedKey            = 121
+	CodeInvalidFileName             = 122
+	CodeInvalidACL                  = 123
+	CodeTimeout                     = 124
+	CodeInvalidEmailAddress         = 125
+	CodeMissingContentType          = 126
+	CodeMissingContentLength        = 127
+	CodeInvalidContentLength        = 128
+	CodeFileTooLarge                = 129
+	CodeFileSaveError               = 130
+	CodeDuplicateValue              = 137
+	CodeInvalidRoleName             = 139
+	CodeExceededQuota               = 140
+	CodeScriptFailed                = 141
+	CodeValidationFailed            = 142
+	CodeFileDeleteFailed            = 153
+	CodeRequestLimitExceeded        = 155
+	CodeInvalidEventName            = 160
+	CodeUsernameMissing             = 200
+	CodePasswordMissing             = 201
+	CodeUsernameTaken               = 202
+	CodeEmailTaken                  = 203
+	CodeEmailMissing                = 204
+	CodeEmailNotFound               = 205
+	CodeSessionMissing              = 206
+	CodeMustCreateUserThroughSignup = 207
+	CodeAccountAlreadyLinked        = 208
+	CodeInvalidSessionToken         = 209
+	CodeLinkedIDMissing             = 250
+	CodeInvalidLinkedSession        = 251
+	CodeUnsupportedService          = 252
+)
+
+// HasCode reports whether err is, or wraps, an *Error with the given code.
+func HasCode(err error, code int) bool {
+	var e *Error
+	return errors.As(err, &e) && e != nil && e.Code == code
+}
+
+// IsObjectNotFound reports whether err says the object does not exist, or
+// isn't visible with the credentials used.
+func IsObjectNotFound(err error) bool {
+	return HasCode(err, CodeObjectNotFound)
+}
+
+// IsInvalidSessionToken reports whether err says the session token is invalid
+// or expired, and the user needs to log in again.
+func IsInvalidSessionToken(err error) bool {
+	return HasCode(err, CodeInvalidSessionToken)
+}
+
+// IsDuplicateValue reports whether err says a unique index rejected the value
+// of a field.
+func IsDuplicateValue(err error) bool {
+	return HasCode(err, CodeDuplicateValue)
+}
+
+// IsUsernameTaken reports whether err says a user with the username already
+// exists.
+func IsUsernameTaken(err error) bool {
+	return HasCode(err, CodeUsernameTaken)
+}
@@ -0,0 +1,46 @@
+package parse_test
+
+import (
+	"errors"
+	"fmt"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestHasCode(t *testing.T) {
+	t.Parallel()
+	notFound := &parse.Error{Code: parse.CodeObjectNotFound, Message: "object not found"}
+	ensure.True(t, parse.IsObjectNotFound(notFound))
+	ensure.True(t, parse.IsObjectNotFound(&parse.ImportError{Line: 1, Err: notFound}))
+	ensure.True(t, parse.HasCode(&parse.RateLimitError{Err: &parse.Error{Code: parse.CodeRequestLimitExceeded}}, parse.CodeRequestLimitExceeded))
+	ensure.True(t, parse.IsInvalidSessionToken(&parse.Error{Code: parse.CodeInvalidSessionToken}))
+	ensure.True(t, parse.IsDuplicateValue(&parse.Error{Code: 137}))
+	ensure.True(t, parse.IsUsernameTaken(&parse.Error{Code: 202}))
+
+	ensure.False(t, parse.IsObjectNotFound(nil))
+	ensure.False(t, parse.IsObjectNotFound((*parse.Error)(nil)))
+	ensure.False(t, parse.IsObjectNotFound(&parse.Error{Code: parse.CodeInvalidQuery}))
+	ensure.False(t, parse.IsObjectNotFound(&parse.RawError{StatusCode: 404}))
+	ensure.False(t, parse.IsObjectNotFound(errors.New("object not found")))
+}
+
+func TestErrorsIs(t *testing.T) {
+	t.Parallel()
+	err := fmt.Errorf("loading yak: %w", &parse.RateLimitError{
+		Err: &parse.Error{Code: parse.CodeRequestLimitExceeded, Message: "slow down", StatusCode: 429},
+	})
+	ensure.True(t, errors.Is(err, &parse.Error{Code: parse.CodeRequestLimitExceeded}))
+	ensure.False(t, errors.Is(err, &parse.Error{Code: parse.CodeObjectNotFound}))
+	ensure.True(t, parse.HasCode(err, parse.CodeRequestLimitExceeded))
+
+	var apiErr *parse.Error
+	ensure.True(t, errors.As(err, &apiErr))
+	ensure.DeepEqual(t, apiErr.StatusCode, 429)
+	var rateErr *parse.RateLimitError
+	ensure.True(t, errors.As(err, &rateErr))
+
+	importErr := &parse.ImportError{Line: 3, Err: &parse.Error{Code: parse.CodeObjectNotFound}}
+	ensure.True(t, errors.Is(importErr, &parse.Error{Code: parse.CodeObjectNotFound}))
+}
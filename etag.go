@@ -0,0 +1,142 @@
+package parse
+
+import (
+	"bytes"
+	"container/list"
+	"crypto/sha256"
+	"encoding/hex"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+const defaultETagCacheSize = 1000
+
+// The headers that can change the response to the same URL.
+var etagKeyHeaders = []string{
+	applicationIDHeader,
+	masterKeyHeader,
+	restAPIKeyHeader,
+	javaScriptKeyHeader,
+	sessionTokenHeader,
+}
+
+// ETagCache keeps the responses of GET requests that have an ETag, and sends
+// If-None-Match when the URL is fetched again. When the server answers 304 Not
+// Modified the kept response is returned instead, so polling unchanged config
+// or objects costs no response body. Add its Middleware to a Client:
+//
+//	c.Middleware = append(c.Middleware, (&parse.ETagCache{}).Middleware)
+//
+// Responses are kept per URL and credentials, so users don't see each other's
+// objects. The zero value is ready to use and safe for concurrent use.
+type ETagCache struct {
+	// MaxEntries is the number of responses kept, the least recently used
+	// being dropped first. Defaults to 1000.
+	MaxEntries int
+
+	mu      sync.Mutex
+	entries map[string]*list.Element
+	lru     list.List
+}
+
+type etagEntry struct {
+	key    string
+	etag   string
+	header http.Header
+	body   []byte
+}
+
+func etagKey(r *http.Request) string {
+	return r.URL.String() + " " + credentialsHash(r.Header)
+}
+
+// credentialsHash identifies the credentials in the headers.
+func credentialsHash(header http.Header) string {
+	h := sha256.New()
+	for _, k := range etagKeyHeaders {
+		h.Write([]byte(header.Get(k)))
+		h.Write([]byte{0})
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+func (e *ETagCache) get(key string) *etagEntry {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	el, ok := e.entries[key]
+	if !ok {
+		return nil
+	}
+	e.lru.MoveToFront(el)
+	return el.Value.(*etagEntry)
+}
+
+func (e *ETagCache) put(en *etagEntry) {
+	e.mu.Lock()
+	defer e.mu.Unlock()
+	if e.entries == nil {
+		e.entries = make(map[string]*list.Element)
+	}
+	if el, ok := e.entries[en.key]; ok {
+		el.Value = en
+		e.lru.MoveToFront(el)
+		return
+	}
+	e.entries[en.key] = e.lru.PushFront(en)
+	max := e.MaxEntries
+	if max <= 0 {
+		max = defaultETagCacheSize
+	}
+	for e.lru.Len() > max {
+		el := e.lru.Back()
+		e.lru.Remove(el)
+		delete(e.entries, el.Value.(*etagEntry).key)
+	}
+}
+
+// Middleware sends If-None-Match for kept responses, and keeps new ones.
+func (e *ETagCache) Middleware(next http.RoundTripper) http.RoundTripper {
+	return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if requestMethod(r) != "GET" || r.Header.Get("If-None-Match") != "" {
+			return next.RoundTrip(r)
+		}
+		key := etagKey(r)
+		cached := e.get(key)
+		if cached != nil {
+			r = r.Clone(r.Context())
+			r.Header.Set("If-None-Match", cached.etag)
+		}
+		res, err := next.RoundTrip(r)
+		if err != nil {
+			return res, err
+		}
+		if res.StatusCode == http.StatusNotModified && cached != nil {
+			res.Body.Close()
+			header := cached.header.Clone()
+			return &http.Response{
+				Status:        "200 OK",
+				StatusCode:    http.StatusOK,
+				Proto:         res.Proto,
+				ProtoMajor:    res.ProtoMajor,
+				ProtoMinor:    res.ProtoMinor,
+				Header:        header,
+				Body:          ioutil.NopCloser(bytes.NewReader(cached.body)),
+				ContentLength: int64(len(cached.body)),
+				Request:       r,
+			}, nil
+		}
+		etag := res.Header.Get("ETag")
+		if res.StatusCode != http.StatusOK || etag == "" {
+			return res, nil
+		}
+		body, err := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		e.put(&etagEntry{key: key, etag: etag, header: res.Header.Clone(), body: body})
+		res.Body = ioutil.NopCloser(bytes.NewReader(body))
+		return res, nil
+	})
+}
@@ -0,0 +1,115 @@
+package parse_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+// etagServer serves the body with its ETag, answering 304 when it matches.
+type etagServer struct {
+	body    string
+	etag    string
+	matches []string
+}
+
+func (s *etagServer) RoundTrip(r *http.Request) (*http.Response, error) {
+	s.matches = append(s.matches, r.Header.Get("If-None-Match"))
+	if s.etag != "" && r.Header.Get("If-None-Match") == s.etag {
+		return &http.Response{
+			StatusCode: http.StatusNotModified,
+			Header:     http.Header{"Etag": {s.etag}},
+			Body:       ioutil.NopCloser(bytes.NewReader(nil)),
+		}, nil
+	}
+	res := &http.Response{
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       ioutil.NopCloser(bytes.NewReader([]byte(s.body))),
+	}
+	if s.etag != "" {
+		res.Header.Set("ETag", s.etag)
+	}
+	return res, nil
+}
+
+func TestETagCache(t *testing.T) {
+	t.Parallel()
+	s := &etagServer{body: `{"name":"a"}`, etag: `"1"`}
+	c := &parse.Client{
+		Transport:  s,
+		Middleware: []parse.Middleware{(&parse.ETagCache{}).Middleware},
+	}
+	u := &url.URL{Path: "classes/Yak/abc"}
+	get := func() string {
+		var res struct{ Name string }
+		_, err := c.Get(u, &res)
+		ensure.Nil(t, err)
+		return res.Name
+	}
+	ensure.DeepEqual(t, get(), "a")
+	ensure.DeepEqual(t, get(), "a")
+	s.body, s.etag = `{"name":"b"}`, `"2"`
+	ensure.DeepEqual(t, get(), "b")
+	ensure.DeepEqual(t, get(), "b")
+	ensure.DeepEqual(t, s.matches, []string{"", `"1"`, `"1"`, `"2"`})
+}
+
+func TestETagCachePerCredentials(t *testing.T) {
+	t.Parallel()
+	s := &etagServer{body: `{}`, etag: `"1"`}
+	cache := &parse.ETagCache{}
+	c := &parse.Client{
+		Transport:  s,
+		Middleware: []parse.Middleware{cache.Middleware},
+	}
+	user := func(token string) *parse.Client {
+		return c.WithCredentials(parse.SessionToken{
+			ApplicationID: defaultApplicationID,
+			RestAPIKey:    "r",
+			SessionToken:  token,
+		})
+	}
+	_, err := user("a").Get(nil, nil)
+	ensure.Nil(t, err)
+	_, err = user("b").Get(nil, nil)
+	ensure.Nil(t, err)
+	_, err = user("a").Get(nil, nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, s.matches, []string{"", "", `"1"`})
+}
+
+func TestETagCacheMaxEntries(t *testing.T) {
+	t.Parallel()
+	s := &etagServer{body: `{}`, etag: `"1"`}
+	c := &parse.Client{
+		Transport:  s,
+		Middleware: []parse.Middleware{(&parse.ETagCache{MaxEntries: 1}).Middleware},
+	}
+	for _, p := range []string{"a", "b", "a", "a"} {
+		_, err := c.Get(&url.URL{Path: p}, nil)
+		ensure.Nil(t, err)
+	}
+	ensure.DeepEqual(t, s.matches, []string{"", "", "", `"1"`})
+}
+
+func TestETagCacheIgnoresWrites(t *testing.T) {
+	t.Parallel()
+	s := &etagServer{body: `{}`, etag: `"1"`}
+	c := &parse.Client{
+		Transport:  s,
+		Middleware: []parse.Middleware{(&parse.ETagCache{}).Middleware},
+	}
+	for i := 0; i < 2; i++ {
+		_, err := c.Put(nil, map[string]string{}, nil)
+		ensure.Nil(t, err)
+	}
+	_, err := c.Get(nil, nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, s.matches, []string{"", "", ""})
+}
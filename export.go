@@ -0,0 +1,219 @@
+package parse
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"time"
+)
+
+const defaultExportBatchSize = 1000
+
+var errExportWhereObjectID = errors.New("parse: export where clause cannot constrain objectId")
+
+// Exporter walks all objects in a class and writes them out as newline
+// delimited JSON, one object per line. Objects are fetched in objectId order
+// and each page starts after the last objectId seen, so unlike skip based
+// pagination the walk stays correct and fast for arbitrarily large classes.
+//
+// The Client usually needs to be configured with the Master Key so that ACLs
+// don't hide objects from the export.
+type Exporter struct {
+	Client    *Client
+	ClassName string
+
+	// Keys restricts the exported fields. The objectId is always included. If
+	// empty all fields are exported.
+	Keys []string
+
+	// Where optionally restricts the exported objects. It cannot constrain
+	// objectId, since that is used for pagination.
+	Where Where
+
+	// BatchSize is the number of objects fetched per request. Defaults to
+	// 1000, the maximum allowed by the API.
+	BatchSize int
+
+	// Interval is the minimum time between requests, to avoid exhausting the
+	// request limit of the application.
+	Interval time.Duration
+
+	// After resumes an export. Only objects with an objectId greater than
+	// After are exported. Set it to ExportResult.LastID of an interrupted
+	// export to continue where it stopped.
+	After string
+
+	// Checkpoint optionally persists the progress of the export after every
+	// page, and is used instead of After to resume when it has been saved
+	// before. A completed export clears it. Objects written after the last
+	// save may be written again when resuming, so the output of a resumed
+	// export can repeat up to a page worth of objects.
+	Checkpoint Checkpoint
+
+	// Reporter optionally receives progress reports. The matching objects
+	// are counted first, so that the reports have a Total.
+	Reporter ProgressReporter
+}
+
+// ExportResult describes a completed or interrupted export.
+type ExportResult struct {
+	// Count is the number of objects written.
+	Count int
+
+	// LastID is the objectId of the last object written, or the objectId the
+	// export started after if nothing was written.
+	LastID string
+}
+
+// Export writes all matching objects to w. On error the returned result
+// describes the objects that were written before the failure.
+func (e *Exporter) Export(w io.Writer) (*ExportResult, error) {
+	after, err := e.start()
+	if err != nil {
+		return nil, err
+	}
+	var line bytes.Buffer
+	return e.each(after, nil, func(raw json.RawMessage) error {
+		line.Reset()
+		if err := json.Compact(&line, raw); err != nil {
+			return err
+		}
+		line.WriteByte('\n')
+		_, err := w.Write(line.Bytes())
+		return err
+	})
+}
+
+// start validates the Exporter and returns the objectId to start after.
+func (e *Exporter) start() (string, error) {
+	if e.ClassName == "" {
+		return "", errEmptyClassName
+	}
+	if _, ok := e.Where["objectId"]; ok {
+		return "", errExportWhereObjectID
+	}
+	if e.Checkpoint != nil {
+		id, err := e.Checkpoint.Load()
+		if err != nil {
+			return "", err
+		}
+		if id != "" {
+			return id, nil
+		}
+	}
+	return e.After, nil
+}
+
+// each calls fn with every matching object after the given objectId, in
+// objectId order. The optional flush is called after every page, before the
+// checkpoint is saved.
+func (e *Exporter) each(after string, flush func() error, fn func(json.RawMessage) error) (*ExportResult, error) {
+	res := &ExportResult{LastID: after}
+	var p *progress
+	if e.Reporter != nil {
+		o := ObjectClient{Client: e.Client, Path: "classes/" + e.ClassName}
+		total, err := o.Count(e.where(after))
+		if err != nil {
+			return res, err
+		}
+		p = newProgress(e.Reporter, e.Client, e.ClassName, total)
+	}
+	clock := e.Client.clock()
+	var last time.Time
+	for {
+		if e.Interval > 0 {
+			if !last.IsZero() {
+				if err := sleep(context.Background(), clock, e.Interval-clock.Now().Sub(last)); err != nil {
+					return res, err
+				}
+			}
+			last = clock.Now()
+		}
+
+		page, err := e.page(res.LastID)
+		if err != nil {
+			return res, err
+		}
+		for _, raw := range page {
+			var id struct {
+				ID string `json:"objectId"`
+			}
+			if err := json.Unmarshal(raw, &id); err != nil {
+				return res, err
+			}
+			if err := fn(raw); err != nil {
+				return res, err
+			}
+			res.Count++
+			res.LastID = id.ID
+		}
+		if flush != nil {
+			if err := flush(); err != nil {
+				return res, err
+			}
+		}
+		// The server may return fewer objects than asked for when its
+		// maxLimit is lower, so only an empty page ends the export.
+		done := len(page) == 0
+		p.report(res.Count, 0, done)
+		if e.Checkpoint != nil {
+			id := res.LastID
+			if done {
+				id = ""
+			}
+			if err := e.Checkpoint.Save(id); err != nil {
+				return res, err
+			}
+		}
+		if done {
+			return res, nil
+		}
+	}
+}
+
+func (e *Exporter) batchSize() int {
+	if e.BatchSize > 0 {
+		return e.BatchSize
+	}
+	return defaultExportBatchSize
+}
+
+// where returns the where clause of the objects after the objectId, or nil
+// when there is none.
+func (e *Exporter) where(after string) Where {
+	where := make(Where, len(e.Where)+1)
+	for k, v := range e.Where {
+		where[k] = v
+	}
+	if after != "" {
+		where.GreaterThan("objectId", after)
+	}
+	if len(where) == 0 {
+		return nil
+	}
+	return where
+}
+
+func (e *Exporter) page(after string) ([]json.RawMessage, error) {
+	where := e.where(after)
+	p := Params{Order: []string{"objectId"}, Limit: e.batchSize()}
+	if len(where) != 0 {
+		p.Where = where
+	}
+	if len(e.Keys) != 0 {
+		p.Keys = append([]string{"objectId"}, e.Keys...)
+	}
+	u, err := p.URL("classes/" + e.ClassName)
+	if err != nil {
+		return nil, err
+	}
+	var page struct {
+		Results []json.RawMessage `json:"results"`
+	}
+	if _, err := e.Client.Get(u, &page); err != nil {
+		return nil, err
+	}
+	return page.Results, nil
+}
@@ -0,0 +1,148 @@
+package parse_test
+
+import (
+	"bytes"
+	"errors"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+	"github.com/facebookgo/parse/parsetest"
+)
+
+// endOfPages returns an empty page for a request of the objects after an
+// objectId, which ends an export, or nil for the first page.
+func endOfPages(t testing.TB, r *http.Request) *http.Response {
+	if !strings.Contains(r.URL.Query().Get("where"), `"objectId":{"$gt"`) {
+		return nil
+	}
+	return jsonResponse(t, http.StatusOK, map[string]interface{}{"results": []interface{}{}})
+}
+
+func TestExport(t *testing.T) {
+	t.Parallel()
+	pages := []map[string]interface{}{
+		{"results": []map[string]interface{}{
+			{"objectId": "a", "name": "alpha"},
+			{"objectId": "b", "name": "beta"},
+		}},
+		{"results": []map[string]interface{}{
+			{"objectId": "c", "name": "gamma"},
+		}},
+		{"results": []interface{}{}},
+	}
+	wheres := []string{
+		`{"hairy":true}`,
+		`{"hairy":true,"objectId":{"$gt":"b"}}`,
+		`{"hairy":true,"objectId":{"$gt":"c"}}`,
+	}
+	calls := 0
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Method, "GET")
+			ensure.DeepEqual(t, r.URL.Path, "/1/classes/Yak")
+			q := r.URL.Query()
+			ensure.DeepEqual(t, q.Get("order"), "objectId")
+			ensure.DeepEqual(t, q.Get("limit"), "2")
+			ensure.DeepEqual(t, q.Get("keys"), "objectId,name")
+			ensure.DeepEqual(t, q.Get("where"), wheres[calls])
+			res := jsonResponse(t, http.StatusOK, pages[calls])
+			calls++
+			return res, nil
+		}),
+	}
+	var buf bytes.Buffer
+	e := parse.Exporter{
+		Client:    c,
+		ClassName: "Yak",
+		Keys:      []string{"name"},
+		Where:     parse.Where{}.EqualTo("hairy", true),
+		BatchSize: 2,
+	}
+	res, err := e.Export(&buf)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res, &parse.ExportResult{Count: 3, LastID: "c"})
+	ensure.DeepEqual(t, calls, 3)
+	ensure.DeepEqual(t, buf.String(),
+		`{"name":"alpha","objectId":"a"}`+"\n"+
+			`{"name":"beta","objectId":"b"}`+"\n"+
+			`{"name":"gamma","objectId":"c"}`+"\n")
+}
+
+func TestExportMaxLimit(t *testing.T) {
+	t.Parallel()
+	s := parsetest.NewServer()
+	defer s.Close()
+	o := parse.ObjectClient{Client: s.Client(), Path: "classes/Yak"}
+	_, err := o.PostAll([]map[string]int{{"n": 1}, {"n": 2}, {"n": 3}})
+	ensure.Nil(t, err)
+
+	// The server returns at most 2 objects, fewer than the BatchSize.
+	c := s.Client()
+	c.Middleware = append(c.Middleware, func(next http.RoundTripper) http.RoundTripper {
+		return transportFunc(func(r *http.Request) (*http.Response, error) {
+			q := r.URL.Query()
+			q.Set("limit", "2")
+			r.URL.RawQuery = q.Encode()
+			return next.RoundTrip(r)
+		})
+	})
+	res, err := (&parse.Exporter{Client: c, ClassName: "Yak"}).Export(&bytes.Buffer{})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res.Count, 3)
+}
+
+func TestExportResume(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			q := r.URL.Query()
+			ensure.DeepEqual(t, q.Get("where"), `{"objectId":{"$gt":"m"}}`)
+			ensure.DeepEqual(t, q.Get("limit"), "1000")
+			ensure.DeepEqual(t, q.Get("keys"), "")
+			return jsonResponse(t, http.StatusOK, map[string]interface{}{
+				"results": []interface{}{},
+			}), nil
+		}),
+	}
+	e := parse.Exporter{Client: c, ClassName: "Yak", After: "m"}
+	res, err := e.Export(&bytes.Buffer{})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res, &parse.ExportResult{LastID: "m"})
+}
+
+func TestExportInterrupted(t *testing.T) {
+	t.Parallel()
+	calls := 0
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			if calls == 2 {
+				return nil, errors.New("connection reset")
+			}
+			return jsonResponse(t, http.StatusOK, map[string]interface{}{
+				"results": []map[string]string{{"objectId": "a"}},
+			}), nil
+		}),
+	}
+	e := parse.Exporter{Client: c, ClassName: "Yak", BatchSize: 1}
+	res, err := e.Export(&bytes.Buffer{})
+	ensure.Err(t, err, regexp.MustCompile("connection reset"))
+	ensure.DeepEqual(t, res, &parse.ExportResult{Count: 1, LastID: "a"})
+}
+
+func TestExportErrors(t *testing.T) {
+	t.Parallel()
+	_, err := (&parse.Exporter{}).Export(&bytes.Buffer{})
+	ensure.Err(t, err, regexp.MustCompile("empty class name"))
+
+	e := parse.Exporter{
+		ClassName: "Yak",
+		Where:     parse.Where{}.EqualTo("objectId", "a"),
+	}
+	_, err = e.Export(&bytes.Buffer{})
+	ensure.Err(t, err, regexp.MustCompile("cannot constrain objectId"))
+}
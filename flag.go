@@ -0,0 +1,78 @@
+package parse
+
+import (
+	"errors"
+	"flag"
+	"os"
+)
+
+var errNoCredentials = errors.New("parse: a master key, rest api key or session token is required")
+
+// CredentialsFlag provides command line flags for the Credentials of a
+// Client:
+//
+//	var cf parse.CredentialsFlag
+//	cf.Register(flag.CommandLine)
+//	flag.Parse()
+//	cr, err := cf.Credentials()
+type CredentialsFlag struct {
+	ApplicationID string
+	MasterKey     string
+	RestAPIKey    string
+	JavaScriptKey string
+	SessionToken  string
+}
+
+// Register adds the -application-id, -master-key, -rest-api-key,
+// -javascript-key and -session-token flags to the FlagSet. The current values
+// are used as the defaults.
+func (f *CredentialsFlag) Register(fs *flag.FlagSet) {
+	fs.StringVar(&f.ApplicationID, "application-id", f.ApplicationID, "parse application id")
+	fs.StringVar(&f.MasterKey, "master-key", f.MasterKey, "parse master key")
+	fs.StringVar(&f.RestAPIKey, "rest-api-key", f.RestAPIKey, "parse rest api key")
+	fs.StringVar(&f.JavaScriptKey, "javascript-key", f.JavaScriptKey, "parse javascript key, used instead of the rest api key")
+	fs.StringVar(&f.SessionToken, "session-token", f.SessionToken, "parse session token, used with the rest api key or javascript key")
+}
+
+// Credentials returns the Credentials described by the flags. The Master Key
+// is preferred, followed by the Session Token, the Rest API Key and finally
+// the JavaScript Key.
+func (f *CredentialsFlag) Credentials() (Credentials, error) {
+	if f.ApplicationID == "" {
+		return nil, errEmptyApplicationID
+	}
+	switch {
+	case f.MasterKey != "":
+		return MasterKey{ApplicationID: f.ApplicationID, MasterKey: f.MasterKey}, nil
+	case f.SessionToken != "":
+		return SessionToken{
+			ApplicationID: f.ApplicationID,
+			RestAPIKey:    f.RestAPIKey,
+			JavaScriptKey: f.JavaScriptKey,
+			SessionToken:  f.SessionToken,
+		}, nil
+	case f.RestAPIKey != "":
+		return RestAPIKey{ApplicationID: f.ApplicationID, RestAPIKey: f.RestAPIKey}, nil
+	case f.JavaScriptKey != "":
+		return JavaScriptKey{ApplicationID: f.ApplicationID, JavaScriptKey: f.JavaScriptKey}, nil
+	}
+	return nil, errNoCredentials
+}
+
+// CredentialsFromEnv returns the Credentials in the environment variables named
+// by the prefix followed by _APPLICATION_ID, _MASTER_KEY, _REST_API_KEY,
+// _JAVASCRIPT_KEY and _SESSION_TOKEN, chosen like CredentialsFlag does. The
+// prefix defaults to PARSE, for PARSE_APPLICATION_ID and so on.
+func CredentialsFromEnv(prefix string) (Credentials, error) {
+	if prefix == "" {
+		prefix = "PARSE"
+	}
+	f := CredentialsFlag{
+		ApplicationID: os.Getenv(prefix + "_APPLICATION_ID"),
+		MasterKey:     os.Getenv(prefix + "_MASTER_KEY"),
+		RestAPIKey:    os.Getenv(prefix + "_REST_API_KEY"),
+		JavaScriptKey: os.Getenv(prefix + "_JAVASCRIPT_KEY"),
+		SessionToken:  os.Getenv(prefix + "_SESSION_TOKEN"),
+	}
+	return f.Credentials()
+}
@@ -0,0 +1,110 @@
+package parse_test
+
+import (
+	"flag"
+	"io/ioutil"
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestCredentialsFlag(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Args        []string
+		Credentials parse.Credentials
+	}{
+		{
+			Args:        []string{"-application-id=a", "-master-key=m", "-rest-api-key=r"},
+			Credentials: parse.MasterKey{ApplicationID: "a", MasterKey: "m"},
+		},
+		{
+			Args:        []string{"-application-id=a", "-rest-api-key=r", "-session-token=s"},
+			Credentials: parse.SessionToken{ApplicationID: "a", RestAPIKey: "r", SessionToken: "s"},
+		},
+		{
+			Args:        []string{"-application-id=a", "-rest-api-key=r"},
+			Credentials: parse.RestAPIKey{ApplicationID: "a", RestAPIKey: "r"},
+		},
+		{
+			Args:        []string{"-application-id=a", "-javascript-key=j"},
+			Credentials: parse.JavaScriptKey{ApplicationID: "a", JavaScriptKey: "j"},
+		},
+		{
+			Args:        []string{"-application-id=a", "-javascript-key=j", "-session-token=s"},
+			Credentials: parse.SessionToken{ApplicationID: "a", JavaScriptKey: "j", SessionToken: "s"},
+		},
+	}
+	for _, c := range cases {
+		var cf parse.CredentialsFlag
+		fs := flag.NewFlagSet("test", flag.ContinueOnError)
+		cf.Register(fs)
+		ensure.Nil(t, fs.Parse(c.Args))
+		cr, err := cf.Credentials()
+		ensure.Nil(t, err)
+		ensure.DeepEqual(t, cr, c.Credentials)
+	}
+}
+
+func TestCredentialsFlagDefaults(t *testing.T) {
+	t.Parallel()
+	cf := parse.CredentialsFlag{ApplicationID: "a", MasterKey: "m"}
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	cf.Register(fs)
+	ensure.Nil(t, fs.Parse([]string{"-master-key=m2"}))
+	cr, err := cf.Credentials()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, cr, parse.MasterKey{ApplicationID: "a", MasterKey: "m2"})
+}
+
+func TestCredentialsFlagErrors(t *testing.T) {
+	t.Parallel()
+	_, err := (&parse.CredentialsFlag{MasterKey: "m"}).Credentials()
+	ensure.Err(t, err, regexp.MustCompile("empty ApplicationID"))
+	_, err = (&parse.CredentialsFlag{ApplicationID: "a"}).Credentials()
+	ensure.Err(t, err, regexp.MustCompile("key or session token is required"))
+
+	fs := flag.NewFlagSet("test", flag.ContinueOnError)
+	fs.SetOutput(ioutil.Discard)
+	(&parse.CredentialsFlag{}).Register(fs)
+	ensure.NotNil(t, fs.Parse([]string{"-app-id=a"}))
+}
+
+// TestCredentialsFromEnv is not parallel since it changes the environment.
+func TestCredentialsFromEnv(t *testing.T) {
+	for k, v := range map[string]string{
+		"PARSE_TEST_APPLICATION_ID": "a",
+		"PARSE_TEST_REST_API_KEY":   "r",
+		"PARSE_TEST_SESSION_TOKEN":  "s",
+	} {
+		ensure.Nil(t, os.Setenv(k, v))
+		defer os.Unsetenv(k)
+	}
+	cr, err := parse.CredentialsFromEnv("PARSE_TEST")
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, cr, parse.SessionToken{ApplicationID: "a", RestAPIKey: "r", SessionToken: "s"})
+
+	_, err = parse.CredentialsFromEnv("PARSE_MISSING")
+	ensure.Err(t, err, regexp.MustCompile("empty ApplicationID"))
+}
+
+// TestCredentialsFromEnvDefaultPrefix is not parallel since it changes the
+// environment.
+func TestCredentialsFromEnvDefaultPrefix(t *testing.T) {
+	for _, k := range []string{"PARSE_APPLICATION_ID", "PARSE_MASTER_KEY", "PARSE_REST_API_KEY", "PARSE_SESSION_TOKEN"} {
+		if v, ok := os.LookupEnv(k); ok {
+			defer os.Setenv(k, v)
+		} else {
+			defer os.Unsetenv(k)
+		}
+		os.Unsetenv(k)
+	}
+	ensure.Nil(t, os.Setenv("PARSE_APPLICATION_ID", "a"))
+	ensure.Nil(t, os.Setenv("PARSE_MASTER_KEY", "m"))
+	cr, err := parse.CredentialsFromEnv("")
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, cr, parse.MasterKey{ApplicationID: "a", MasterKey: "m"})
+}
@@ -0,0 +1,57 @@
+package parse
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+)
+
+var errEmptyFunctionName = errors.New("parse: cannot use empty function name")
+
+// CallFunction calls the cloud function with the given name and params, and
+// unmarshals the value returned by the function into result. The function
+// runs with the Client Credentials, so use WithCredentials to call it as a
+// user with a SessionToken. Errors raised by the function are returned as an
+// Error.
+func (c *Client) CallFunction(name string, params, result interface{}) error {
+	if name == "" {
+		return errEmptyFunctionName
+	}
+	if params == nil {
+		params = struct{}{}
+	}
+	var res struct {
+		Result json.RawMessage `json:"result"`
+	}
+	_, err := c.Post(&url.URL{Path: "functions/" + name}, params, &res)
+	if err != nil {
+		return cloudCodeError(err)
+	}
+	if result == nil || res.Result == nil {
+		return nil
+	}
+	return c.decode(res.Result, result)
+}
+
+// cloudCodeError converts errors with a non string message, as raised by
+// cloud code using an object as the error, into an Error.
+func cloudCodeError(err error) error {
+	raw, ok := err.(*RawError)
+	if !ok {
+		return err
+	}
+	var e struct {
+		Code  int             `json:"code"`
+		Error json.RawMessage `json:"error"`
+	}
+	if json.Unmarshal(raw.Body, &e) != nil || e.Code == 0 {
+		return err
+	}
+	return &Error{
+		Code:       e.Code,
+		Message:    string(e.Error),
+		StatusCode: raw.StatusCode,
+		URL:        raw.URL,
+		RequestID:  raw.RequestID,
+	}
+}
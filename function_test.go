@@ -0,0 +1,116 @@
+package parse_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestCallFunction(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Method, "POST")
+			ensure.DeepEqual(t, r.URL.Path, "/1/functions/hello")
+			ensure.DeepEqual(t, r.Header.Get("X-Parse-Session-Token"), "st")
+			var m map[string]interface{}
+			decodeBody(t, r, &m)
+			ensure.DeepEqual(t, m, map[string]interface{}{"name": "yak"})
+			return jsonResponse(t, http.StatusOK, map[string]interface{}{
+				"result": map[string]string{"greeting": "hello yak"},
+			}), nil
+		}),
+	}
+	c = c.WithCredentials(parse.SessionToken{
+		ApplicationID: defaultApplicationID,
+		RestAPIKey:    "rk",
+		SessionToken:  "st",
+	})
+	var result struct {
+		Greeting string `json:"greeting"`
+	}
+	ensure.Nil(t, c.CallFunction("hello", map[string]string{"name": "yak"}, &result))
+	ensure.DeepEqual(t, result.Greeting, "hello yak")
+}
+
+func TestCallFunctionNoParams(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			var m map[string]interface{}
+			decodeBody(t, r, &m)
+			ensure.DeepEqual(t, m, map[string]interface{}{})
+			return jsonResponse(t, http.StatusOK, map[string]interface{}{}), nil
+		}),
+	}
+	ensure.Nil(t, c.CallFunction("hello", nil, nil))
+}
+
+func TestCallFunctionError(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			return jsonResponse(t, http.StatusBadRequest, parse.Error{
+				Code:    141,
+				Message: "bad yak",
+			}), nil
+		}),
+	}
+	err := c.CallFunction("hello", nil, nil)
+	ensure.DeepEqual(t, err, &parse.Error{
+		Code:       141,
+		Message:    "bad yak",
+		StatusCode: http.StatusBadRequest,
+		URL:        "https://api.parse.com/1/functions/hello",
+	})
+}
+
+func TestCallFunctionObjectError(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusBadRequest,
+				Body: ioutil.NopCloser(bytes.NewReader(
+					[]byte(`{"code":141,"error":{"reason":"bad yak"}}`))),
+			}, nil
+		}),
+	}
+	err := c.CallFunction("hello", nil, nil)
+	ensure.DeepEqual(t, err, &parse.Error{
+		Code:       141,
+		Message:    `{"reason":"bad yak"}`,
+		StatusCode: http.StatusBadRequest,
+		URL:        "https://api.parse.com/1/functions/hello",
+	})
+}
+
+func TestCallFunctionRawError(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusBadGateway,
+				Body:       ioutil.NopCloser(strings.NewReader("<html>")),
+			}, nil
+		}),
+	}
+	err := c.CallFunction("hello", nil, nil)
+	ensure.DeepEqual(t, err, &parse.RawError{
+		StatusCode: http.StatusBadGateway,
+		Body:       []byte("<html>"),
+		URL:        "https://api.parse.com/1/functions/hello",
+	})
+}
+
+func TestCallFunctionEmptyName(t *testing.T) {
+	t.Parallel()
+	var c parse.Client
+	ensure.Err(t, c.CallFunction("", nil, nil), regexp.MustCompile("empty function name"))
+}
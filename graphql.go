@@ -0,0 +1,110 @@
+package parse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+var (
+	errEmptyGraphQLQuery = errors.New("parse: GraphQL query cannot be empty")
+
+	graphQLURL = &url.URL{Path: "/graphql"}
+)
+
+// GraphQLClient sends queries and mutations to the GraphQL API of a Parse
+// Server, using the credentials and other settings of the Client.
+type GraphQLClient struct {
+	Client *Client
+
+	// URL of the GraphQL endpoint. Defaults to /graphql on the host of the
+	// Client BaseURL, where Parse Server mounts it.
+	URL *url.URL
+}
+
+// GraphQLRequest is a query or mutation.
+type GraphQLRequest struct {
+	Query         string                 `json:"query"`
+	OperationName string                 `json:"operationName,omitempty"`
+	Variables     map[string]interface{} `json:"variables,omitempty"`
+}
+
+// GraphQLLocation is a position in the query.
+type GraphQLLocation struct {
+	Line   int `json:"line"`
+	Column int `json:"column"`
+}
+
+// GraphQLError is an error of a GraphQL response.
+type GraphQLError struct {
+	Message    string                 `json:"message"`
+	Locations  []GraphQLLocation      `json:"locations,omitempty"`
+	Path       []interface{}          `json:"path,omitempty"`
+	Extensions map[string]interface{} `json:"extensions,omitempty"`
+}
+
+func (e *GraphQLError) Error() string {
+	return "parse: graphql: " + e.Message
+}
+
+// GraphQLErrors are the errors of a GraphQL response.
+type GraphQLErrors []*GraphQLError
+
+func (e GraphQLErrors) Error() string {
+	msgs := make([]string, len(e))
+	for i, err := range e {
+		msgs[i] = err.Message
+	}
+	return "parse: graphql: " + strings.Join(msgs, "; ")
+}
+
+type graphQLResponse struct {
+	Data       json.RawMessage `json:"data"`
+	Errors     GraphQLErrors   `json:"errors"`
+	Extensions json.RawMessage `json:"extensions"`
+}
+
+func (g *GraphQLClient) url() *url.URL {
+	if g.URL != nil {
+		return g.URL
+	}
+	return g.Client.baseURL().ResolveReference(graphQLURL)
+}
+
+// Do sends the request and unmarshals the data of the response into result.
+// Errors in the response are returned as GraphQLErrors, after unmarshalling
+// the partial data if there is any.
+func (g *GraphQLClient) Do(req *GraphQLRequest, result interface{}) error {
+	return g.DoCtx(context.Background(), req, result)
+}
+
+// DoCtx is Do with a context used for the request.
+func (g *GraphQLClient) DoCtx(ctx context.Context, req *GraphQLRequest, result interface{}) error {
+	if req.Query == "" {
+		return errEmptyGraphQLQuery
+	}
+	if err := g.Client.require(ctx, FeatureGraphQL); err != nil {
+		return err
+	}
+	var res graphQLResponse
+	hr := (&http.Request{Method: "POST", URL: g.url()}).WithContext(ctx)
+	if _, err := g.Client.Do(hr, req, &res); err != nil {
+		// Invalid queries are rejected with a 400 listing the errors.
+		var raw *RawError
+		if !errors.As(err, &raw) || json.Unmarshal(raw.Body, &res) != nil || len(res.Errors) == 0 {
+			return err
+		}
+	}
+	if result != nil && len(res.Data) != 0 && string(res.Data) != "null" {
+		if err := g.Client.decode(res.Data, result); err != nil {
+			return err
+		}
+	}
+	if len(res.Errors) != 0 {
+		return res.Errors
+	}
+	return nil
+}
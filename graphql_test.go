@@ -0,0 +1,127 @@
+package parse_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+const yakQuery = `query Yak($id: ID!) { yak(id: $id) { name } }`
+
+func TestGraphQL(t *testing.T) {
+	t.Parallel()
+	g := parse.GraphQLClient{
+		Client: &parse.Client{
+			BaseURL:     &url.URL{Scheme: "https", Host: "example.com", Path: "/parse/"},
+			Credentials: defaultRestAPIKey,
+			Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+				ensure.DeepEqual(t, r.Method, "POST")
+				ensure.DeepEqual(t, r.URL.String(), "https://example.com/graphql")
+				ensure.DeepEqual(t, r.Header.Get("X-Parse-Application-Id"), defaultApplicationID)
+				ensure.DeepEqual(t, r.Header.Get("X-Parse-REST-API-Key"), defaultRestAPIKey.RestAPIKey)
+				var req parse.GraphQLRequest
+				decodeBody(t, r, &req)
+				ensure.DeepEqual(t, req, parse.GraphQLRequest{
+					Query:     yakQuery,
+					Variables: map[string]interface{}{"id": "abc"},
+				})
+				return jsonResponse(t, http.StatusOK, map[string]interface{}{
+					"data": map[string]interface{}{"yak": map[string]string{"name": "yak"}},
+				}), nil
+			}),
+		},
+	}
+	var res struct {
+		Yak struct{ Name string }
+	}
+	err := g.Do(&parse.GraphQLRequest{
+		Query:     yakQuery,
+		Variables: map[string]interface{}{"id": "abc"},
+	}, &res)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res.Yak.Name, "yak")
+}
+
+func TestGraphQLErrors(t *testing.T) {
+	t.Parallel()
+	g := parse.GraphQLClient{
+		URL: &url.URL{Scheme: "https", Host: "example.com", Path: "/api/graphql"},
+		Client: &parse.Client{
+			Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+				ensure.DeepEqual(t, r.URL.Path, "/api/graphql")
+				return jsonResponse(t, http.StatusOK, map[string]interface{}{
+					"data": map[string]interface{}{"yak": map[string]string{"name": "yak"}, "owner": nil},
+					"errors": []map[string]interface{}{{
+						"message":   "Permission denied",
+						"path":      []string{"owner"},
+						"locations": []map[string]int{{"line": 1, "column": 3}},
+					}},
+				}), nil
+			}),
+		},
+	}
+	var res struct {
+		Yak struct{ Name string }
+	}
+	err := g.Do(&parse.GraphQLRequest{Query: yakQuery}, &res)
+	ensure.DeepEqual(t, err, parse.GraphQLErrors{{
+		Message:   "Permission denied",
+		Path:      []interface{}{"owner"},
+		Locations: []parse.GraphQLLocation{{Line: 1, Column: 3}},
+	}})
+	ensure.DeepEqual(t, err.Error(), "parse: graphql: Permission denied")
+	ensure.DeepEqual(t, res.Yak.Name, "yak")
+}
+
+func TestGraphQLInvalidQuery(t *testing.T) {
+	t.Parallel()
+	g := parse.GraphQLClient{
+		Client: &parse.Client{
+			Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+				return jsonResponse(t, http.StatusBadRequest, map[string]interface{}{
+					"errors": []map[string]string{{"message": "Syntax Error"}, {"message": "Unknown field"}},
+				}), nil
+			}),
+		},
+	}
+	err := g.Do(&parse.GraphQLRequest{Query: "{"}, nil)
+	ensure.DeepEqual(t, err.Error(), "parse: graphql: Syntax Error; Unknown field")
+}
+
+func TestGraphQLHTTPError(t *testing.T) {
+	t.Parallel()
+	g := parse.GraphQLClient{
+		Client: &parse.Client{
+			Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+				return jsonResponse(t, http.StatusUnauthorized, map[string]string{"error": "unauthorized"}), nil
+			}),
+		},
+	}
+	err := g.Do(&parse.GraphQLRequest{Query: yakQuery}, nil)
+	ensure.DeepEqual(t, err.(*parse.Error).Message, "unauthorized")
+}
+
+func TestGraphQLEmptyQuery(t *testing.T) {
+	t.Parallel()
+	err := (&parse.GraphQLClient{Client: &parse.Client{}}).Do(&parse.GraphQLRequest{}, nil)
+	ensure.Err(t, err, regexp.MustCompile("query cannot be empty"))
+}
+
+func TestGraphQLUnsupported(t *testing.T) {
+	t.Parallel()
+	var infos int
+	g := parse.GraphQLClient{
+		Client: &parse.Client{
+			Capabilities: &parse.Capabilities{},
+			Transport:    serverVersion(t, "3.4.0", &infos),
+		},
+	}
+	err := g.DoCtx(context.Background(), &parse.GraphQLRequest{Query: yakQuery}, nil)
+	ensure.True(t, errors.Is(err, parse.ErrUnsupportedByServer))
+}
@@ -0,0 +1,82 @@
+package parse
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+)
+
+// gzipBody closes both the gzip reader and the response body.
+type gzipBody struct {
+	*gzip.Reader
+	body io.ReadCloser
+}
+
+func (b *gzipBody) Close() error {
+	b.Reader.Close()
+	return b.body.Close()
+}
+
+// gzipTransport asks for compressed responses and decompresses them when Gzip
+// is set, and compresses request bodies of at least GzipRequests bytes.
+func (c *Client) gzipTransport(next http.RoundTripper) http.RoundTripper {
+	return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if c.Gzip || c.GzipRequests > 0 {
+			r = r.Clone(r.Context())
+		}
+		if c.GzipRequests > 0 && r.Body != nil && r.Header.Get("Content-Encoding") == "" {
+			if err := gzipRequest(r, c.GzipRequests); err != nil {
+				return nil, err
+			}
+		}
+		if c.Gzip && r.Header.Get("Accept-Encoding") == "" {
+			r.Header.Set("Accept-Encoding", "gzip")
+		}
+		res, err := next.RoundTrip(r)
+		if err != nil || !c.Gzip || !strings.EqualFold(res.Header.Get("Content-Encoding"), "gzip") {
+			return res, err
+		}
+		zr, err := gzip.NewReader(res.Body)
+		if err != nil {
+			res.Body.Close()
+			return nil, err
+		}
+		res.Body = &gzipBody{Reader: zr, body: res.Body}
+		res.Header.Del("Content-Encoding")
+		res.Header.Del("Content-Length")
+		res.ContentLength = -1
+		res.Uncompressed = true
+		return res, nil
+	})
+}
+
+// gzipRequest compresses the body of the request if it has at least min
+// bytes.
+func gzipRequest(r *http.Request, min int) error {
+	body, err := ioutil.ReadAll(r.Body)
+	r.Body.Close()
+	if err != nil {
+		return err
+	}
+	if len(body) >= min {
+		var buf bytes.Buffer
+		zw := gzip.NewWriter(&buf)
+		if _, err := zw.Write(body); err != nil {
+			return err
+		}
+		if err := zw.Close(); err != nil {
+			return err
+		}
+		body = buf.Bytes()
+		r.Header.Set("Content-Encoding", "gzip")
+	}
+	r.Body = ioutil.NopCloser(bytes.NewReader(body))
+	r.GetBody = func() (io.ReadCloser, error) {
+		return ioutil.NopCloser(bytes.NewReader(body)), nil
+	}
+	r.ContentLength = int64(len(body))
+	return nil
+}
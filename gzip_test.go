@@ -0,0 +1,90 @@
+package parse_test
+
+import (
+	"bytes"
+	"compress/gzip"
+	"io/ioutil"
+	"net/http"
+	"strings"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func gzipped(t *testing.T, s string) []byte {
+	var buf bytes.Buffer
+	zw := gzip.NewWriter(&buf)
+	_, err := zw.Write([]byte(s))
+	ensure.Nil(t, err)
+	ensure.Nil(t, zw.Close())
+	return buf.Bytes()
+}
+
+func TestGzipResponse(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Gzip: true,
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Header.Get("Accept-Encoding"), "gzip")
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Header:     http.Header{"Content-Encoding": {"gzip"}},
+				Body:       ioutil.NopCloser(bytes.NewReader(gzipped(t, `{"name":"yak"}`))),
+			}, nil
+		}),
+	}
+	var res struct{ Name string }
+	_, err := c.Get(nil, &res)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res.Name, "yak")
+}
+
+func TestGzipErrorResponse(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Gzip: true,
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusNotFound,
+				Header:     http.Header{"Content-Encoding": {"gzip"}},
+				Body:       ioutil.NopCloser(bytes.NewReader(gzipped(t, `{"code":101,"error":"not found"}`))),
+			}, nil
+		}),
+	}
+	_, err := c.Get(nil, nil)
+	ensure.True(t, parse.IsObjectNotFound(err))
+}
+
+func TestGzipRequests(t *testing.T) {
+	t.Parallel()
+	var bodies [][]byte
+	var compressed []bool
+	c := &parse.Client{
+		GzipRequests: 100,
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			var b []byte
+			var err error
+			if r.Header.Get("Content-Encoding") == "gzip" {
+				zr, err := gzip.NewReader(r.Body)
+				ensure.Nil(t, err)
+				b, err = ioutil.ReadAll(zr)
+			} else {
+				b, err = ioutil.ReadAll(r.Body)
+			}
+			ensure.Nil(t, err)
+			bodies = append(bodies, b)
+			compressed = append(compressed, r.Header.Get("Content-Encoding") == "gzip")
+			ensure.DeepEqual(t, r.Header.Get("Accept-Encoding"), "")
+			return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+		}),
+	}
+	long := strings.Repeat("yak", 100)
+	_, err := c.Post(nil, map[string]string{"name": long}, nil)
+	ensure.Nil(t, err)
+	_, err = c.Post(nil, map[string]string{"name": "yak"}, nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, string(bodies[0]), `{"name":"`+long+`"}`)
+	ensure.DeepEqual(t, string(bodies[1]), `{"name":"yak"}`)
+	ensure.DeepEqual(t, compressed, []bool{true, false})
+}
@@ -0,0 +1,116 @@
+package parse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"time"
+)
+
+// RequestInfo summarizes a request for the OnRequest and OnResponse hooks of a
+// Client.
+type RequestInfo struct {
+	// Context of the request, which can carry the identity of the caller.
+	Context context.Context
+
+	Method string
+
+	// URL is the requested URL, with passwords, session tokens and keys
+	// redacted.
+	URL string
+
+	// Endpoint is the API without object IDs, such as "classes/Yak".
+	Endpoint string
+
+	// ObjectID is the object ID or file name in the URL, if any.
+	ObjectID string
+}
+
+// ResponseInfo describes the outcome of a request for the OnResponse hook of a
+// Client.
+type ResponseInfo struct {
+	Request RequestInfo
+
+	// StatusCode of the response, or 0 if none was received.
+	StatusCode int
+
+	// Err is the error returned for the request, such as an *Error.
+	Err error
+
+	// ObjectID is the objectId in the response, such as the ID of a created
+	// object, or else the object ID of the request.
+	ObjectID string
+
+	// Duration is the time taken by the request, including retries.
+	Duration time.Duration
+}
+
+// call tracks a request for the hooks and the audit sink of a Client.
+type call struct {
+	info   RequestInfo
+	start  time.Time
+	writes []AuditRecord
+}
+
+func (c *Client) hooked() bool {
+	return c.OnRequest != nil || c.OnResponse != nil || c.Audit != nil
+}
+
+// buffered reports whether successful response bodies are buffered.
+func (c *Client) buffered() bool {
+	return c.OnResponse != nil || c.Audit != nil
+}
+
+// startCall calls OnRequest, and returns nil if there are no hooks.
+func (c *Client) startCall(req *http.Request) (*call, error) {
+	if !c.hooked() {
+		return nil, nil
+	}
+	cl := &call{
+		start: time.Now(),
+		info: RequestInfo{
+			Context: req.Context(),
+			Method:  requestMethod(req),
+			URL:     c.redactURL(req.URL),
+		},
+	}
+	cl.info.Endpoint, cl.info.ObjectID = c.splitPath(req.URL)
+	if c.Audit != nil {
+		if err := c.auditRequest(cl, req); err != nil {
+			return nil, err
+		}
+	}
+	if c.OnRequest != nil {
+		c.OnRequest(cl.info)
+	}
+	return cl, nil
+}
+
+// finish calls OnResponse and the audit sink with the outcome of a request. The
+// body is the response body of a successful request.
+func (cl *call) finish(c *Client, res *http.Response, body []byte, err error) {
+	if cl == nil {
+		return
+	}
+	info := ResponseInfo{
+		Request:  cl.info,
+		Err:      err,
+		ObjectID: cl.info.ObjectID,
+		Duration: time.Since(cl.start),
+	}
+	if res != nil {
+		info.StatusCode = res.StatusCode
+	}
+	var o struct {
+		ID string `json:"objectId"`
+	}
+	if len(body) != 0 && body[0] == '{' && json.Unmarshal(body, &o) == nil && o.ID != "" {
+		info.ObjectID = o.ID
+	}
+	if c.OnResponse != nil {
+		c.OnResponse(info)
+	}
+	if c.Audit != nil {
+		c.auditResponse(cl, info, body)
+	}
+}
@@ -0,0 +1,64 @@
+package parse_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestHooks(t *testing.T) {
+	t.Parallel()
+	var reqs []parse.RequestInfo
+	var ress []parse.ResponseInfo
+	c := &parse.Client{
+		OnRequest:  func(info parse.RequestInfo) { reqs = append(reqs, info) },
+		OnResponse: func(info parse.ResponseInfo) { ress = append(ress, info) },
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			switch r.Method {
+			case "POST":
+				return jsonResponse(t, http.StatusCreated, map[string]string{"objectId": "new1"}), nil
+			case "PUT":
+				return jsonResponse(t, http.StatusOK, map[string]string{"updatedAt": "2015-03-07T21:52:12.000Z"}), nil
+			}
+			return jsonResponse(t, http.StatusNotFound, map[string]interface{}{"code": 101, "error": "object not found"}), nil
+		}),
+	}
+	ctx := context.WithValue(context.Background(), ctxKey("user"), "al")
+	var created parse.CreateResponse
+	_, err := c.PostCtx(ctx, &url.URL{Path: "classes/Yak"}, map[string]string{}, &created)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, created.ID, "new1")
+	_, err = c.Put(&url.URL{Path: "classes/Yak/y1"}, map[string]string{}, nil)
+	ensure.Nil(t, err)
+	_, err = c.Delete(&url.URL{Path: "classes/Yak/y2", RawQuery: "sessionToken=r:abc"}, nil)
+	ensure.NotNil(t, err)
+
+	ensure.DeepEqual(t, len(reqs), 3)
+	ensure.DeepEqual(t, reqs[0].Context.Value(ctxKey("user")), "al")
+	reqs[0].Context = nil
+	ensure.DeepEqual(t, reqs[0], parse.RequestInfo{
+		Method:   "POST",
+		URL:      "https://api.parse.com/1/classes/Yak",
+		Endpoint: "classes/Yak",
+	})
+	ensure.DeepEqual(t, reqs[1].ObjectID, "y1")
+	ensure.DeepEqual(t, reqs[2].URL, "https://api.parse.com/1/classes/Yak/y2?sessionToken=REDACTED")
+
+	ensure.DeepEqual(t, len(ress), 3)
+	ensure.DeepEqual(t, ress[0].StatusCode, http.StatusCreated)
+	ensure.DeepEqual(t, ress[0].ObjectID, "new1")
+	ensure.DeepEqual(t, ress[1].ObjectID, "y1")
+	ensure.Nil(t, ress[1].Err)
+	ensure.DeepEqual(t, ress[2].ObjectID, "y2")
+	ensure.DeepEqual(t, ress[2].Err, &parse.Error{
+		Code:       101,
+		Message:    "object not found",
+		StatusCode: http.StatusNotFound,
+		URL:        "https://api.parse.com/1/classes/Yak/y2?sessionToken=REDACTED",
+	})
+	ensure.DeepEqual(t, ress[2].Request.Method, "DELETE")
+}
@@ -0,0 +1,67 @@
+package parse
+
+import (
+	"context"
+	"crypto/rand"
+	"fmt"
+	"net/http"
+	"strconv"
+)
+
+const requestIDHeader = "X-Parse-Request-Id"
+
+type idempotencyKey struct{}
+
+// WithIdempotencyKey returns a context that sets the X-Parse-Request-Id header
+// of the POST and PUT requests made with it to key. Parse Server, with its
+// idempotency option enabled, processes a request only once per key, so a key
+// reused for a request sent again, for example by a queue restarted after a
+// crash, doesn't create an object twice. The batch requests of PostAll,
+// PutAll and DeleteAll use the key followed by "-" and the index of the
+// batch, such as "key-1" for the second one.
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+// batchIdempotencyKey returns the context for the batch at index n of a
+// request split into several, with its own key derived from that of ctx.
+func batchIdempotencyKey(ctx context.Context, n int) context.Context {
+	key, ok := ctx.Value(idempotencyKey{}).(string)
+	if !ok || key == "" {
+		return ctx
+	}
+	return WithIdempotencyKey(ctx, key+"-"+strconv.Itoa(n))
+}
+
+// setRequestID adds the X-Parse-Request-Id header to POST and PUT requests
+// unless the request already has one. The header is set before the request
+// is sent, so retries of the request repeat it.
+func (c *Client) setRequestID(req *http.Request) error {
+	if req.Header.Get(requestIDHeader) != "" || (req.Method != "POST" && req.Method != "PUT") {
+		return nil
+	}
+	if key, ok := req.Context().Value(idempotencyKey{}).(string); ok && key != "" {
+		req.Header.Set(requestIDHeader, key)
+		return nil
+	}
+	if !c.IdempotencyKeys {
+		return nil
+	}
+	key, err := newRequestID()
+	if err != nil {
+		return err
+	}
+	req.Header.Set(requestIDHeader, key)
+	return nil
+}
+
+// newRequestID returns a random version 4 UUID.
+func newRequestID() (string, error) {
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return "", err
+	}
+	b[6] = b[6]&0x0f | 0x40
+	b[8] = b[8]&0x3f | 0x80
+	return fmt.Sprintf("%x-%x-%x-%x-%x", b[0:4], b[4:6], b[6:8], b[8:10], b[10:]), nil
+}
@@ -0,0 +1,132 @@
+package parse_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"regexp"
+	"sort"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+	"github.com/facebookgo/parse/parsetest"
+)
+
+func requestIDs(t *testing.T, c *parse.Client, ids *[]string) {
+	c.Transport = transportFunc(func(r *http.Request) (*http.Response, error) {
+		*ids = append(*ids, r.Header.Get("X-Parse-Request-Id"))
+		return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+	})
+}
+
+func TestIdempotencyKeysGenerated(t *testing.T) {
+	t.Parallel()
+	var ids []string
+	c := &parse.Client{IdempotencyKeys: true}
+	requestIDs(t, c, &ids)
+	u := &url.URL{Path: "classes/Yak"}
+	_, err := c.Post(u, map[string]string{}, nil)
+	ensure.Nil(t, err)
+	_, err = c.Put(u, map[string]string{}, nil)
+	ensure.Nil(t, err)
+	_, err = c.Get(u, nil)
+	ensure.Nil(t, err)
+	_, err = c.Delete(u, nil)
+	ensure.Nil(t, err)
+
+	ensure.DeepEqual(t, len(ids), 4)
+	uuid := regexp.MustCompile(`^[0-9a-f]{8}-[0-9a-f]{4}-4[0-9a-f]{3}-[89ab][0-9a-f]{3}-[0-9a-f]{12}$`)
+	ensure.True(t, uuid.MatchString(ids[0]), ids[0])
+	ensure.True(t, uuid.MatchString(ids[1]), ids[1])
+	ensure.NotDeepEqual(t, ids[0], ids[1])
+	ensure.DeepEqual(t, ids[2:], []string{"", ""})
+}
+
+func TestIdempotencyKeysDisabled(t *testing.T) {
+	t.Parallel()
+	var ids []string
+	c := &parse.Client{}
+	requestIDs(t, c, &ids)
+	_, err := c.Post(&url.URL{Path: "classes/Yak"}, map[string]string{}, nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, ids, []string{""})
+}
+
+func TestIdempotencyKeyExplicit(t *testing.T) {
+	t.Parallel()
+	var ids []string
+	c := &parse.Client{}
+	requestIDs(t, c, &ids)
+	ctx := parse.WithIdempotencyKey(context.Background(), "yak-1")
+	_, err := c.PostCtx(ctx, &url.URL{Path: "classes/Yak"}, map[string]string{}, nil)
+	ensure.Nil(t, err)
+
+	req := http.Request{
+		Method: "POST",
+		URL:    &url.URL{Path: "classes/Yak"},
+		Header: http.Header{"X-Parse-Request-Id": {"yak-2"}},
+	}
+	_, err = c.WithContext(ctx).Do(&req, map[string]string{}, nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, ids, []string{"yak-1", "yak-2"})
+
+	// Reads made with the context don't use the key.
+	_, err = c.GetCtx(ctx, &url.URL{Path: "classes/Yak"}, nil)
+	ensure.Nil(t, err)
+	_, err = c.DeleteCtx(ctx, &url.URL{Path: "classes/Yak/abc"}, nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, ids, []string{"yak-1", "yak-2", "", ""})
+}
+
+func TestIdempotencyKeyBatches(t *testing.T) {
+	t.Parallel()
+	s := parsetest.NewServer()
+	defer s.Close()
+	var mu sync.Mutex
+	var ids []string
+	c := s.Client()
+	c.Middleware = append(c.Middleware, func(next http.RoundTripper) http.RoundTripper {
+		return parse.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+			mu.Lock()
+			ids = append(ids, r.Header.Get("X-Parse-Request-Id"))
+			mu.Unlock()
+			return next.RoundTrip(r)
+		})
+	})
+	o := parse.ObjectClient{Client: c, Path: "classes/Yak", Concurrency: 2}
+	yaks := make([]map[string]int, parse.MaxBatchSize+1)
+	for n := range yaks {
+		yaks[n] = map[string]int{"n": n}
+	}
+	ctx := parse.WithIdempotencyKey(context.Background(), "yaks")
+	res, err := o.PostAllCtx(ctx, yaks)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(res), len(yaks))
+	sort.Strings(ids)
+	ensure.DeepEqual(t, ids, []string{"yaks-0", "yaks-1"})
+	ensure.DeepEqual(t, len(s.Objects("Yak")), len(yaks))
+}
+
+func TestIdempotencyKeyRetried(t *testing.T) {
+	t.Parallel()
+	var ids []string
+	c := &parse.Client{
+		IdempotencyKeys: true,
+		Retry:           &parse.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, RetryPOST: true},
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ids = append(ids, r.Header.Get("X-Parse-Request-Id"))
+			if len(ids) < 3 {
+				return jsonResponse(t, http.StatusServiceUnavailable, map[string]string{}), nil
+			}
+			return jsonResponse(t, http.StatusCreated, map[string]string{}), nil
+		}),
+	}
+	_, err := c.Post(&url.URL{Path: "classes/Yak"}, map[string]string{}, nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(ids), 3)
+	ensure.NotDeepEqual(t, ids[0], "")
+	ensure.DeepEqual(t, ids, []string{ids[0], ids[0], ids[0]})
+}
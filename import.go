@@ -0,0 +1,275 @@
+package parse
+
+import (
+	"bufio"
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io"
+	"sort"
+	"sync"
+)
+
+// Importer loads newline delimited JSON objects, such as the output of an
+// Exporter, into a class using batch requests.
+//
+// Objects with an objectId are updated in place, and created with that
+// objectId if they don't exist yet. This makes re-running an import of the
+// same data safe: objects already loaded by an earlier run are overwritten
+// rather than duplicated. Creating objects with a given objectId requires a
+// server that allows custom object IDs. Objects without an objectId are
+// always created.
+type Importer struct {
+	Client    *Client
+	ClassName string
+
+	// BatchSize is the number of objects sent per batch request. Defaults to
+	// and cannot exceed MaxBatchSize.
+	BatchSize int
+
+	// Concurrency is the number of batch requests in flight. Defaults to 1.
+	Concurrency int
+
+	// Reporter optionally receives progress reports. The reports have no
+	// Total, since the input is read as it goes.
+	Reporter ProgressReporter
+}
+
+// ImportError reports a record that could not be imported.
+type ImportError struct {
+	// Line is the 1 based line number of the record in the input.
+	Line     int
+	ObjectID string
+	Err      error
+}
+
+func (e *ImportError) Error() string {
+	if e.ObjectID == "" {
+		return fmt.Sprintf("parse: import of line %d failed: %s", e.Line, e.Err)
+	}
+	return fmt.Sprintf("parse: import of line %d with objectId %q failed: %s",
+		e.Line, e.ObjectID, e.Err)
+}
+
+// Unwrap returns the error of the record.
+func (e *ImportError) Unwrap() error {
+	return e.Err
+}
+
+// ImportResult describes a completed import.
+type ImportResult struct {
+	Created int
+	Updated int
+
+	// Errors has the records that failed, ordered by line.
+	Errors []*ImportError
+}
+
+type importRecord struct {
+	line int
+	id   string
+	body map[string]json.RawMessage
+}
+
+// importSource produces the records of an import. Errors of type
+// *ImportError are reported and skip the record, other errors stop the import.
+// The end of the input is signaled with io.EOF.
+type importSource interface {
+	next() (importRecord, error)
+}
+
+// Import reads all records from r. Records that fail don't stop the import and
+// are reported in the result. The returned error is only set when reading
+// from r fails, in which case the result covers the records read until then.
+func (i *Importer) Import(r io.Reader) (*ImportResult, error) {
+	return i.run(&jsonImportSource{r: bufio.NewReader(r)})
+}
+
+func (i *Importer) run(src importSource) (*ImportResult, error) {
+	if i.ClassName == "" {
+		return nil, errEmptyClassName
+	}
+
+	var (
+		res  ImportResult
+		mu   sync.Mutex
+		pool = Pool{Size: i.concurrency()}
+		p    = newProgress(i.Reporter, i.Client, i.ClassName, 0)
+	)
+	// report is called with mu held.
+	report := func(done bool) {
+		p.report(res.Created+res.Updated+len(res.Errors), len(res.Errors), done)
+	}
+	send := func(batch []importRecord) {
+		pool.Go(func() error {
+			created, updated, errs := i.importBatch(batch)
+			mu.Lock()
+			res.Created += created
+			res.Updated += updated
+			res.Errors = append(res.Errors, errs...)
+			report(false)
+			mu.Unlock()
+			return nil
+		})
+	}
+
+	var (
+		batch   []importRecord
+		readErr error
+	)
+	for {
+		rec, err := src.next()
+		if ierr, ok := err.(*ImportError); ok {
+			mu.Lock()
+			res.Errors = append(res.Errors, ierr)
+			mu.Unlock()
+			continue
+		}
+		if err != nil {
+			if err != io.EOF {
+				readErr = err
+			}
+			break
+		}
+		if batch = append(batch, rec); len(batch) == i.batchSize() {
+			send(batch)
+			batch = nil
+		}
+	}
+	if len(batch) != 0 {
+		send(batch)
+	}
+	pool.Wait()
+	if readErr == nil {
+		report(true)
+	}
+
+	sort.Sort(importErrorsByLine(res.Errors))
+	return &res, readErr
+}
+
+type jsonImportSource struct {
+	r    *bufio.Reader
+	line int
+	eof  bool
+}
+
+func (s *jsonImportSource) next() (importRecord, error) {
+	for !s.eof {
+		b, err := s.r.ReadBytes('\n')
+		if err == io.EOF {
+			s.eof = true
+		} else if err != nil {
+			return importRecord{}, err
+		}
+		s.line++
+		if b = bytes.TrimSpace(b); len(b) == 0 {
+			continue
+		}
+		rec := importRecord{line: s.line}
+		if err := json.Unmarshal(b, &rec.body); err != nil {
+			return rec, &ImportError{Line: s.line, Err: err}
+		}
+		if err := rec.init(); err != nil {
+			return rec, err
+		}
+		return rec, nil
+	}
+	return importRecord{}, io.EOF
+}
+
+// init sets the objectId of the record and drops the fields that can't be
+// written.
+func (r *importRecord) init() error {
+	if raw, ok := r.body["objectId"]; ok {
+		if err := json.Unmarshal(raw, &r.id); err != nil {
+			return &ImportError{Line: r.line, Err: err}
+		}
+	}
+	delete(r.body, "createdAt")
+	delete(r.body, "updatedAt")
+	return nil
+}
+
+func (i *Importer) importBatch(batch []importRecord) (created, updated int, errs []*ImportError) {
+	path := "classes/" + i.ClassName
+	reqs := make([]BatchRequest, len(batch))
+	for n, rec := range batch {
+		if rec.id == "" {
+			reqs[n] = BatchRequest{Method: "POST", Path: path, Body: rec.body}
+			continue
+		}
+		body := make(map[string]json.RawMessage, len(rec.body))
+		for k, v := range rec.body {
+			if k != "objectId" {
+				body[k] = v
+			}
+		}
+		reqs[n] = BatchRequest{Method: "PUT", Path: path + "/" + rec.id, Body: body}
+	}
+
+	res, err := i.Client.Batch(reqs)
+	if err != nil {
+		for _, rec := range batch {
+			errs = append(errs, &ImportError{Line: rec.line, ObjectID: rec.id, Err: err})
+		}
+		return 0, 0, errs
+	}
+
+	var missing []importRecord
+	for n, r := range res {
+		rec := batch[n]
+		switch {
+		case r.Error == nil && rec.id == "":
+			created++
+		case r.Error == nil:
+			updated++
+		case rec.id != "" && r.Error.Code == CodeObjectNotFound:
+			missing = append(missing, rec)
+		default:
+			errs = append(errs, &ImportError{Line: rec.line, ObjectID: rec.id, Err: r.Error})
+		}
+	}
+	if len(missing) == 0 {
+		return created, updated, errs
+	}
+
+	// Records that should have been updated but don't exist yet are created
+	// with their objectId.
+	reqs = reqs[:0]
+	for _, rec := range missing {
+		reqs = append(reqs, BatchRequest{Method: "POST", Path: path, Body: rec.body})
+	}
+	res, err = i.Client.Batch(reqs)
+	for n, rec := range missing {
+		switch {
+		case err != nil:
+			errs = append(errs, &ImportError{Line: rec.line, ObjectID: rec.id, Err: err})
+		case res[n].Error != nil:
+			errs = append(errs, &ImportError{Line: rec.line, ObjectID: rec.id, Err: res[n].Error})
+		default:
+			created++
+		}
+	}
+	return created, updated, errs
+}
+
+func (i *Importer) batchSize() int {
+	if i.BatchSize > 0 && i.BatchSize < MaxBatchSize {
+		return i.BatchSize
+	}
+	return MaxBatchSize
+}
+
+func (i *Importer) concurrency() int {
+	if i.Concurrency > 0 {
+		return i.Concurrency
+	}
+	return 1
+}
+
+type importErrorsByLine []*ImportError
+
+func (e importErrorsByLine) Len() int           { return len(e) }
+func (e importErrorsByLine) Less(i, j int) bool { return e[i].Line < e[j].Line }
+func (e importErrorsByLine) Swap(i, j int)      { e[i], e[j] = e[j], e[i] }
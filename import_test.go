@@ -0,0 +1,142 @@
+package parse_test
+
+import (
+	"errors"
+	"io"
+	"net/http"
+	"regexp"
+	"strings"
+	"sync/atomic"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+type batchBody struct {
+	Requests []struct {
+		Method string                 `json:"method"`
+		Path   string                 `json:"path"`
+		Body   map[string]interface{} `json:"body"`
+	} `json:"requests"`
+}
+
+func TestImport(t *testing.T) {
+	t.Parallel()
+	calls := 0
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.URL.Path, "/1/batch")
+			var body batchBody
+			decodeBody(t, r, &body)
+			calls++
+			switch calls {
+			case 1:
+				ensure.DeepEqual(t, len(body.Requests), 3)
+				ensure.DeepEqual(t, body.Requests[0].Method, "PUT")
+				ensure.DeepEqual(t, body.Requests[0].Path, "/1/classes/Yak/a")
+				ensure.DeepEqual(t, body.Requests[0].Body, map[string]interface{}{"name": "alpha"})
+				ensure.DeepEqual(t, body.Requests[1].Method, "PUT")
+				ensure.DeepEqual(t, body.Requests[1].Path, "/1/classes/Yak/b")
+				ensure.DeepEqual(t, body.Requests[2].Method, "POST")
+				ensure.DeepEqual(t, body.Requests[2].Path, "/1/classes/Yak")
+				ensure.DeepEqual(t, body.Requests[2].Body, map[string]interface{}{"name": "gamma"})
+				return jsonResponse(t, http.StatusOK, []interface{}{
+					map[string]interface{}{"success": map[string]string{}},
+					map[string]interface{}{"error": map[string]interface{}{"code": 101, "error": "object not found"}},
+					map[string]interface{}{"success": map[string]string{"objectId": "c"}},
+				}), nil
+			case 2:
+				ensure.DeepEqual(t, len(body.Requests), 1)
+				ensure.DeepEqual(t, body.Requests[0].Method, "POST")
+				ensure.DeepEqual(t, body.Requests[0].Path, "/1/classes/Yak")
+				ensure.DeepEqual(t, body.Requests[0].Body, map[string]interface{}{
+					"objectId": "b",
+					"name":     "beta",
+				})
+				return jsonResponse(t, http.StatusOK, []interface{}{
+					map[string]interface{}{"success": map[string]string{"objectId": "b"}},
+				}), nil
+			case 3:
+				ensure.DeepEqual(t, len(body.Requests), 1)
+				return jsonResponse(t, http.StatusOK, []interface{}{
+					map[string]interface{}{"error": map[string]interface{}{"code": 142, "error": "name required"}},
+				}), nil
+			}
+			panic("unexpected request")
+		}),
+	}
+	in := strings.Join([]string{
+		`{"objectId":"a","name":"alpha","createdAt":"2015-01-01T00:00:00.000Z"}`,
+		`{"objectId":"b","name":"beta","updatedAt":"2015-01-01T00:00:00.000Z"}`,
+		``,
+		`{"name":"gamma"}`,
+		`{"name":`,
+		`{}`,
+	}, "\n")
+	i := parse.Importer{Client: c, ClassName: "Yak", BatchSize: 3}
+	res, err := i.Import(strings.NewReader(in))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, calls, 3)
+	ensure.DeepEqual(t, res.Created, 2)
+	ensure.DeepEqual(t, res.Updated, 1)
+	ensure.DeepEqual(t, len(res.Errors), 2)
+	ensure.DeepEqual(t, res.Errors[0].Line, 5)
+	ensure.Err(t, res.Errors[0], regexp.MustCompile("line 5 failed"))
+	ensure.DeepEqual(t, res.Errors[1].Line, 6)
+	ensure.DeepEqual(t, res.Errors[1].Err, &parse.Error{Code: 142, Message: "name required"})
+}
+
+func TestImportConcurrency(t *testing.T) {
+	t.Parallel()
+	var calls int32
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			if atomic.AddInt32(&calls, 1) == 2 {
+				return nil, errors.New("connection reset")
+			}
+			return jsonResponse(t, http.StatusOK, []interface{}{
+				map[string]interface{}{"success": map[string]string{}},
+			}), nil
+		}),
+	}
+	in := strings.Repeat(`{"name":"yak"}`+"\n", 4)
+	i := parse.Importer{Client: c, ClassName: "Yak", BatchSize: 1, Concurrency: 3}
+	res, err := i.Import(strings.NewReader(in))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, atomic.LoadInt32(&calls), int32(4))
+	ensure.DeepEqual(t, res.Created, 3)
+	ensure.DeepEqual(t, len(res.Errors), 1)
+	ensure.Err(t, res.Errors[0].Err, regexp.MustCompile("connection reset"))
+}
+
+type errReader struct{ io.Reader }
+
+func (r errReader) Read(p []byte) (int, error) {
+	n, err := r.Reader.Read(p)
+	if err == io.EOF {
+		err = errors.New("disk on fire")
+	}
+	return n, err
+}
+
+func TestImportReadError(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			return jsonResponse(t, http.StatusOK, []interface{}{
+				map[string]interface{}{"success": map[string]string{}},
+			}), nil
+		}),
+	}
+	i := parse.Importer{Client: c, ClassName: "Yak"}
+	res, err := i.Import(errReader{strings.NewReader(`{"name":"yak"}` + "\n")})
+	ensure.Err(t, err, regexp.MustCompile("disk on fire"))
+	ensure.DeepEqual(t, res.Created, 1)
+}
+
+func TestImportEmptyClassName(t *testing.T) {
+	t.Parallel()
+	_, err := (&parse.Importer{}).Import(strings.NewReader(""))
+	ensure.Err(t, err, regexp.MustCompile("empty class name"))
+}
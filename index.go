@@ -0,0 +1,117 @@
+package parse
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+)
+
+// IndexKey is a single field of an Index. The Value is 1 or -1 for ascending
+// and descending indexes, or a string for special indexes such as "2dsphere".
+type IndexKey struct {
+	Field string
+	Value interface{}
+}
+
+// Ascending indexes the field in ascending order.
+func Ascending(field string) IndexKey {
+	return IndexKey{Field: field, Value: 1}
+}
+
+// Descending indexes the field in descending order.
+func Descending(field string) IndexKey {
+	return IndexKey{Field: field, Value: -1}
+}
+
+// Sphere2D indexes the GeoPoint field for geospatial queries.
+func Sphere2D(field string) IndexKey {
+	return IndexKey{Field: field, Value: "2dsphere"}
+}
+
+// Text indexes the string field for full text search.
+func Text(field string) IndexKey {
+	return IndexKey{Field: field, Value: "text"}
+}
+
+// Index is the definition of an index. An index with multiple keys is a
+// composite index, in which the order of the keys is significant.
+type Index []IndexKey
+
+// MarshalJSON encodes the Index as an object, preserving the order of the
+// keys.
+func (x Index) MarshalJSON() ([]byte, error) {
+	var buf bytes.Buffer
+	buf.WriteByte('{')
+	for i, k := range x {
+		if i != 0 {
+			buf.WriteByte(',')
+		}
+		field, err := json.Marshal(k.Field)
+		if err != nil {
+			return nil, err
+		}
+		value, err := json.Marshal(k.Value)
+		if err != nil {
+			return nil, err
+		}
+		buf.Write(field)
+		buf.WriteByte(':')
+		buf.Write(value)
+	}
+	buf.WriteByte('}')
+	return buf.Bytes(), nil
+}
+
+// UnmarshalJSON decodes the Index, preserving the order of the keys.
+func (x *Index) UnmarshalJSON(b []byte) error {
+	dec := json.NewDecoder(bytes.NewReader(b))
+	dec.UseNumber()
+	if t, err := dec.Token(); err != nil || t != json.Delim('{') {
+		return fmt.Errorf("parse: cannot decode %s as an Index", b)
+	}
+	*x = nil
+	for dec.More() {
+		t, err := dec.Token()
+		if err != nil {
+			return err
+		}
+		field := t.(string)
+		var value interface{}
+		if err := dec.Decode(&value); err != nil {
+			return err
+		}
+		if n, ok := value.(json.Number); ok {
+			i, err := n.Int64()
+			if err != nil {
+				return err
+			}
+			value = int(i)
+		}
+		*x = append(*x, IndexKey{Field: field, Value: value})
+	}
+	return nil
+}
+
+// AddIndexes creates the indexes, keyed by name, on the given class and
+// returns the resulting schema.
+func (c *SchemaClient) AddIndexes(className string, indexes map[string]Index) (*Schema, error) {
+	return c.Update(&Schema{ClassName: className, Indexes: indexes})
+}
+
+// DeleteIndexes drops the indexes with the given names from the given class
+// and returns the resulting schema.
+func (c *SchemaClient) DeleteIndexes(className string, names ...string) (*Schema, error) {
+	if className == "" {
+		return nil, errEmptyClassName
+	}
+	del := make(map[string]json.RawMessage, len(names))
+	for _, n := range names {
+		del[n] = json.RawMessage(`{"__op":"Delete"}`)
+	}
+	body := map[string]interface{}{"className": className, "indexes": del}
+	var res Schema
+	if _, err := c.Client.Put(schemaURL(className), body, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
@@ -0,0 +1,64 @@
+package parse_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestIndexJSON(t *testing.T) {
+	t.Parallel()
+	x := parse.Index{
+		parse.Descending("score"),
+		parse.Ascending("name"),
+		parse.Sphere2D("location"),
+		parse.Text("bio"),
+	}
+	b := jsonB(t, x)
+	ensure.DeepEqual(t, string(b), `{"score":-1,"name":1,"location":"2dsphere","bio":"text"}`)
+	var x2 parse.Index
+	ensure.Nil(t, json.Unmarshal(b, &x2))
+	ensure.DeepEqual(t, x2, x)
+}
+
+func TestIndexUnmarshalInvalid(t *testing.T) {
+	t.Parallel()
+	var x parse.Index
+	ensure.Err(t, json.Unmarshal([]byte(`[]`), &x), regexp.MustCompile("cannot decode"))
+}
+
+func TestSchemaIndexes(t *testing.T) {
+	t.Parallel()
+	var bodies []string
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Method, "PUT")
+			ensure.DeepEqual(t, r.URL.Path, "/1/schemas/Yak")
+			var m json.RawMessage
+			decodeBody(t, r, &m)
+			bodies = append(bodies, string(m))
+			return jsonResponse(t, http.StatusOK, map[string]interface{}{
+				"className": "Yak",
+				"indexes": map[string]interface{}{
+					"_id_": map[string]int{"_id": 1},
+				},
+			}), nil
+		}),
+	}
+	sc := parse.SchemaClient{Client: c}
+	s, err := sc.AddIndexes("Yak", map[string]parse.Index{
+		"herd_age": {parse.Ascending("herd"), parse.Descending("age")},
+	})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, s.Indexes, map[string]parse.Index{"_id_": {parse.Ascending("_id")}})
+	_, err = sc.DeleteIndexes("Yak", "herd_age")
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, bodies, []string{
+		`{"className":"Yak","indexes":{"herd_age":{"herd":1,"age":-1}}}`,
+		`{"className":"Yak","indexes":{"herd_age":{"__op":"Delete"}}}`,
+	})
+}
@@ -0,0 +1,100 @@
+package parse
+
+import "time"
+
+const installationsPath = "installations/"
+
+// Installation is a device registered for push notifications. The optional
+// fields are Null types, so that an Installation can also be used to clear
+// them or to set the badge to zero. A nil field is left out.
+type Installation struct {
+	ID               string      `json:"objectId,omitempty"`
+	CreatedAt        *time.Time  `json:"createdAt,omitempty"`
+	UpdatedAt        *time.Time  `json:"updatedAt,omitempty"`
+	DeviceType       string      `json:"deviceType,omitempty"`
+	DeviceToken      *NullString `json:"deviceToken,omitempty"`
+	InstallationID   string      `json:"installationId,omitempty"`
+	PushType         *NullString `json:"pushType,omitempty"`
+	GCMSenderID      *NullString `json:"GCMSenderId,omitempty"`
+	Channels         []string    `json:"channels,omitempty"`
+	Badge            *NullInt    `json:"badge,omitempty"`
+	TimeZone         *NullString `json:"timeZone,omitempty"`
+	LocaleIdentifier *NullString `json:"localeIdentifier,omitempty"`
+	AppName          *NullString `json:"appName,omitempty"`
+	AppIdentifier    *NullString `json:"appIdentifier,omitempty"`
+	AppVersion       *NullString `json:"appVersion,omitempty"`
+	ParseVersion     *NullString `json:"parseVersion,omitempty"`
+}
+
+// InstallationsClient provides access to the installations endpoint. Creating
+// installations is allowed with the Rest API Key, all other operations
+// require the Master Key.
+type InstallationsClient struct {
+	Client *Client
+}
+
+func (c *InstallationsClient) objects() *ObjectClient {
+	return &ObjectClient{Client: c.Client, Path: installationsPath}
+}
+
+// Create registers a new installation. The ID, CreatedAt and UpdatedAt fields
+// are managed by the API and are not sent.
+func (c *InstallationsClient) Create(i *Installation) (*CreateResponse, error) {
+	i2 := *i
+	i2.ID = ""
+	i2.CreatedAt = nil
+	i2.UpdatedAt = nil
+	return c.objects().Post(&i2)
+}
+
+// Get fetches the installation with the given ID.
+func (c *InstallationsClient) Get(id string) (*Installation, error) {
+	var i Installation
+	if _, err := c.objects().Get(id, &i); err != nil {
+		return nil, err
+	}
+	return &i, nil
+}
+
+// Update modifies the installation with the given ID using the fields in v,
+// such as an Installation with only the fields to change set.
+func (c *InstallationsClient) Update(id string, v interface{}) (*UpdateResponse, error) {
+	return c.objects().Put(id, v)
+}
+
+// Delete removes the installation with the given ID.
+func (c *InstallationsClient) Delete(id string) error {
+	_, err := c.objects().Delete(id)
+	return err
+}
+
+// Subscribe adds the installation to the given channels. Channels it is
+// already subscribed to are left as-is.
+func (c *InstallationsClient) Subscribe(id string, channels ...string) (*UpdateResponse, error) {
+	return c.Update(id, map[string]interface{}{
+		"channels": AddUnique(stringsToObjects(channels)),
+	})
+}
+
+// Unsubscribe removes the installation from the given channels.
+func (c *InstallationsClient) Unsubscribe(id string, channels ...string) (*UpdateResponse, error) {
+	return c.Update(id, map[string]interface{}{
+		"channels": Remove(stringsToObjects(channels)),
+	})
+}
+
+// SetBadge sets the badge of an iOS installation.
+func (c *InstallationsClient) SetBadge(id string, badge int) (*UpdateResponse, error) {
+	return c.Update(id, map[string]interface{}{"badge": badge})
+}
+
+// IncrementBadge atomically increments the badge of an iOS installation by
+// the given amount.
+func (c *InstallationsClient) IncrementBadge(id string, amount int) (*UpdateResponse, error) {
+	return c.Update(id, map[string]interface{}{"badge": Increment(amount)})
+}
+
+// ResetBadge clears the badge of an iOS installation.
+func (c *InstallationsClient) ResetBadge(id string) (*UpdateResponse, error) {
+	return c.SetBadge(id, 0)
+}
@@ -0,0 +1,163 @@
+package parse_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestInstallationsCreate(t *testing.T) {
+	t.Parallel()
+	now := time.Now()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Method, "POST")
+			ensure.DeepEqual(t, r.URL.Path, "/1/installations/")
+			var m map[string]interface{}
+			decodeBody(t, r, &m)
+			ensure.DeepEqual(t, m, map[string]interface{}{
+				"deviceType":  "ios",
+				"deviceToken": "abc",
+				"channels":    []interface{}{"yaks"},
+			})
+			return jsonResponse(t, http.StatusCreated, map[string]string{
+				"objectId": "xyz",
+			}), nil
+		}),
+	}
+	ic := parse.InstallationsClient{Client: c}
+	i := &parse.Installation{
+		ID:          "ignored",
+		CreatedAt:   &now,
+		DeviceType:  "ios",
+		DeviceToken: &parse.NullString{Value: "abc", Valid: true},
+		Channels:    []string{"yaks"},
+	}
+	res, err := ic.Create(i)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res.ID, "xyz")
+	ensure.DeepEqual(t, i.ID, "ignored")
+}
+
+func TestInstallationsGet(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.URL.Path, "/1/installations/xyz")
+			return jsonResponse(t, http.StatusOK, map[string]interface{}{
+				"objectId":   "xyz",
+				"deviceType": "android",
+				"badge":      3,
+				"timeZone":   "America/Los_Angeles",
+			}), nil
+		}),
+	}
+	ic := parse.InstallationsClient{Client: c}
+	i, err := ic.Get("xyz")
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, i, &parse.Installation{
+		ID:         "xyz",
+		DeviceType: "android",
+		Badge:      &parse.NullInt{Value: 3, Valid: true},
+		TimeZone:   &parse.NullString{Value: "America/Los_Angeles", Valid: true},
+	})
+}
+
+func TestInstallationClear(t *testing.T) {
+	t.Parallel()
+	b, err := json.Marshal(parse.Installation{
+		Badge:    &parse.NullInt{Valid: true},
+		TimeZone: &parse.NullString{},
+	})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, string(b), `{"badge":0,"timeZone":null}`)
+}
+
+func TestInstallationsUpdateAndDelete(t *testing.T) {
+	t.Parallel()
+	var methods []string
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			methods = append(methods, r.Method)
+			ensure.DeepEqual(t, r.URL.Path, "/1/installations/xyz")
+			return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+		}),
+	}
+	ic := parse.InstallationsClient{Client: c}
+	_, err := ic.Update("xyz", map[string]string{"appVersion": "2.0"})
+	ensure.Nil(t, err)
+	ensure.Nil(t, ic.Delete("xyz"))
+	ensure.DeepEqual(t, methods, []string{"PUT", "DELETE"})
+}
+
+func TestInstallationsSubscribe(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Method, "PUT")
+			ensure.DeepEqual(t, r.URL.Path, "/1/installations/xyz")
+			var m map[string]interface{}
+			decodeBody(t, r, &m)
+			ensure.DeepEqual(t, m, map[string]interface{}{
+				"channels": map[string]interface{}{
+					"__op":    "AddUnique",
+					"objects": []interface{}{"a", "b"},
+				},
+			})
+			return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+		}),
+	}
+	ic := parse.InstallationsClient{Client: c}
+	_, err := ic.Subscribe("xyz", "a", "b")
+	ensure.Nil(t, err)
+}
+
+func TestInstallationsUnsubscribe(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			var m map[string]interface{}
+			decodeBody(t, r, &m)
+			ensure.DeepEqual(t, m, map[string]interface{}{
+				"channels": map[string]interface{}{
+					"__op":    "Remove",
+					"objects": []interface{}{"a"},
+				},
+			})
+			return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+		}),
+	}
+	ic := parse.InstallationsClient{Client: c}
+	_, err := ic.Unsubscribe("xyz", "a")
+	ensure.Nil(t, err)
+}
+
+func TestInstallationsBadge(t *testing.T) {
+	t.Parallel()
+	var bodies []map[string]interface{}
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.URL.Path, "/1/installations/xyz")
+			var m map[string]interface{}
+			decodeBody(t, r, &m)
+			bodies = append(bodies, m)
+			return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+		}),
+	}
+	ic := parse.InstallationsClient{Client: c}
+	_, err := ic.SetBadge("xyz", 5)
+	ensure.Nil(t, err)
+	_, err = ic.IncrementBadge("xyz", 1)
+	ensure.Nil(t, err)
+	_, err = ic.ResetBadge("xyz")
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, bodies, []map[string]interface{}{
+		{"badge": float64(5)},
+		{"badge": map[string]interface{}{"__op": "Increment", "amount": float64(1)}},
+		{"badge": float64(0)},
+	})
+}
@@ -0,0 +1,108 @@
+package parse
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/url"
+	"time"
+)
+
+const (
+	jobStatusIDHeader      = "X-Parse-Job-Status-Id"
+	jobStatusPath          = "classes/_JobStatus/"
+	defaultJobPollInterval = time.Second
+)
+
+// Job statuses.
+const (
+	JobRunning   = "running"
+	JobSucceeded = "succeeded"
+	JobFailed    = "failed"
+)
+
+var (
+	errEmptyJobName  = errors.New("parse: cannot use empty job name")
+	errNoJobStatusID = errors.New("parse: job status ID not provided by server")
+)
+
+// JobStatus is the status of a triggered job.
+type JobStatus struct {
+	ID         string                 `json:"objectId"`
+	JobName    string                 `json:"jobName"`
+	Status     string                 `json:"status"`
+	Message    string                 `json:"message"`
+	Params     map[string]interface{} `json:"params"`
+	FinishedAt *Date                  `json:"finishedAt"`
+}
+
+// JobError is returned when a job fails.
+type JobError struct {
+	Status *JobStatus
+}
+
+func (e *JobError) Error() string {
+	return fmt.Sprintf("parse: job %s failed with message=%q", e.Status.JobName, e.Status.Message)
+}
+
+// TriggerJob starts the background job with the given name and params, and
+// returns the ID of the _JobStatus object tracking it. It requires the Master
+// Key.
+func (c *Client) TriggerJob(name string, params interface{}) (string, error) {
+	if name == "" {
+		return "", errEmptyJobName
+	}
+	if params == nil {
+		params = struct{}{}
+	}
+	res, err := c.Post(&url.URL{Path: "jobs/" + name}, params, nil)
+	if err != nil {
+		return "", err
+	}
+	id := res.Header.Get(jobStatusIDHeader)
+	if id == "" {
+		return "", errNoJobStatusID
+	}
+	return id, nil
+}
+
+// GetJobStatus fetches the status of the job with the given status ID. It
+// requires the Master Key.
+func (c *Client) GetJobStatus(id string) (*JobStatus, error) {
+	var status JobStatus
+	if _, err := c.Get(&url.URL{Path: jobStatusPath + id}, &status); err != nil {
+		return nil, err
+	}
+	return &status, nil
+}
+
+// WaitJob polls the status of the job with the given status ID at the given
+// interval until it finishes. A JobError is returned if the job failed. A zero
+// interval means defaultJobPollInterval.
+func (c *Client) WaitJob(id string, interval time.Duration) (*JobStatus, error) {
+	return c.WaitJobCtx(context.Background(), id, interval)
+}
+
+// WaitJobCtx is WaitJob with a context used for the requests and the waits
+// between them, so that the context can stop waiting for a stuck job.
+func (c *Client) WaitJobCtx(ctx context.Context, id string, interval time.Duration) (*JobStatus, error) {
+	if interval == 0 {
+		interval = defaultJobPollInterval
+	}
+	cc := c.WithContext(ctx)
+	for {
+		status, err := cc.GetJobStatus(id)
+		if err != nil {
+			return nil, err
+		}
+		switch status.Status {
+		case JobSucceeded:
+			return status, nil
+		case JobFailed:
+			return status, &JobError{Status: status}
+		}
+		if err := sleep(ctx, c.clock(), interval); err != nil {
+			return nil, err
+		}
+	}
+}
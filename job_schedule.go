@@ -0,0 +1,174 @@
+package parse
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+	"regexp"
+	"time"
+)
+
+const jobSchedulesPath = "cloud_code/jobs/"
+
+var (
+	errEmptyJobScheduleName = errors.New("parse: job schedule requires JobName")
+	errJobScheduleTimeOfDay = errors.New("parse: job schedule TimeOfDay must be formatted as HH:MM:SS")
+	errJobScheduleDay       = errors.New("parse: job schedule DaysOfWeek must be between 0 and 6")
+	errJobScheduleRepeat    = errors.New("parse: job schedule RepeatMinutes cannot be negative")
+
+	timeOfDay = regexp.MustCompile(`^([01][0-9]|2[0-3]):[0-5][0-9]:[0-5][0-9]$`)
+)
+
+// JobSchedule is a recurring background job, stored in the _JobSchedule
+// class.
+type JobSchedule struct {
+	ID          string
+	JobName     string
+	Description string
+	Params      map[string]interface{}
+
+	// StartAfter is when the schedule becomes active.
+	StartAfter time.Time
+
+	// DaysOfWeek the job runs on, where 0 is Sunday. Empty means every day.
+	DaysOfWeek []int
+
+	// TimeOfDay the job runs at, formatted as HH:MM:SS in UTC.
+	TimeOfDay string
+
+	// RepeatMinutes runs the job every given number of minutes after
+	// TimeOfDay. Zero means the job runs once a day.
+	RepeatMinutes int
+}
+
+// The API stores the params as a JSON encoded string.
+type jobScheduleJSON struct {
+	ID            string `json:"objectId,omitempty"`
+	JobName       string `json:"jobName"`
+	Description   string `json:"description,omitempty"`
+	Params        string `json:"params,omitempty"`
+	StartAfter    string `json:"startAfter,omitempty"`
+	DaysOfWeek    []int  `json:"daysOfWeek,omitempty"`
+	TimeOfDay     string `json:"timeOfDay,omitempty"`
+	RepeatMinutes int    `json:"repeatMinutes,omitempty"`
+}
+
+// MarshalJSON encodes the JobSchedule.
+func (s JobSchedule) MarshalJSON() ([]byte, error) {
+	j := jobScheduleJSON{
+		ID:            s.ID,
+		JobName:       s.JobName,
+		Description:   s.Description,
+		DaysOfWeek:    s.DaysOfWeek,
+		TimeOfDay:     s.TimeOfDay,
+		RepeatMinutes: s.RepeatMinutes,
+	}
+	if s.Params != nil {
+		p, err := json.Marshal(s.Params)
+		if err != nil {
+			return nil, err
+		}
+		j.Params = string(p)
+	}
+	if !s.StartAfter.IsZero() {
+		j.StartAfter = s.StartAfter.UTC().Format(dateLayout)
+	}
+	return json.Marshal(j)
+}
+
+// UnmarshalJSON decodes the JobSchedule.
+func (s *JobSchedule) UnmarshalJSON(b []byte) error {
+	var j jobScheduleJSON
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	*s = JobSchedule{
+		ID:            j.ID,
+		JobName:       j.JobName,
+		Description:   j.Description,
+		DaysOfWeek:    j.DaysOfWeek,
+		TimeOfDay:     j.TimeOfDay,
+		RepeatMinutes: j.RepeatMinutes,
+	}
+	if j.Params != "" {
+		if err := json.Unmarshal([]byte(j.Params), &s.Params); err != nil {
+			return err
+		}
+	}
+	if j.StartAfter != "" {
+		t, err := time.Parse(time.RFC3339Nano, j.StartAfter)
+		if err != nil {
+			return err
+		}
+		s.StartAfter = t
+	}
+	return nil
+}
+
+func (s *JobSchedule) validate() error {
+	if s.JobName == "" {
+		return errEmptyJobScheduleName
+	}
+	if s.TimeOfDay != "" && !timeOfDay.MatchString(s.TimeOfDay) {
+		return errJobScheduleTimeOfDay
+	}
+	for _, d := range s.DaysOfWeek {
+		if d < 0 || d > 6 {
+			return errJobScheduleDay
+		}
+	}
+	if s.RepeatMinutes < 0 {
+		return errJobScheduleRepeat
+	}
+	return nil
+}
+
+type jobScheduleBody struct {
+	JobSchedule *JobSchedule `json:"job_schedule"`
+}
+
+// JobSchedulesClient manages scheduled jobs. It requires the Master Key.
+type JobSchedulesClient struct {
+	Client *Client
+}
+
+// List fetches all job schedules.
+func (c *JobSchedulesClient) List() ([]*JobSchedule, error) {
+	var schedules []*JobSchedule
+	if _, err := c.Client.Get(&url.URL{Path: jobSchedulesPath}, &schedules); err != nil {
+		return nil, err
+	}
+	return schedules, nil
+}
+
+// Create adds a new job schedule.
+func (c *JobSchedulesClient) Create(s *JobSchedule) (*CreateResponse, error) {
+	if err := s.validate(); err != nil {
+		return nil, err
+	}
+	s2 := *s
+	s2.ID = ""
+	var res CreateResponse
+	_, err := c.Client.Post(&url.URL{Path: jobSchedulesPath}, jobScheduleBody{&s2}, &res)
+	if err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Update replaces the job schedule with the given ID.
+func (c *JobSchedulesClient) Update(id string, s *JobSchedule) error {
+	if err := s.validate(); err != nil {
+		return err
+	}
+	s2 := *s
+	s2.ID = ""
+	_, err := c.Client.Put(&url.URL{Path: jobSchedulesPath + id}, jobScheduleBody{&s2}, nil)
+	return err
+}
+
+// Delete removes the job schedule with the given ID.
+func (c *JobSchedulesClient) Delete(id string) error {
+	_, err := c.Client.Delete(&url.URL{Path: jobSchedulesPath + id}, nil)
+	return err
+}
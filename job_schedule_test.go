@@ -0,0 +1,113 @@
+package parse_test
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestJobSchedulesCreate(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Method, "POST")
+			ensure.DeepEqual(t, r.URL.Path, "/1/cloud_code/jobs/")
+			var m map[string]interface{}
+			decodeBody(t, r, &m)
+			ensure.DeepEqual(t, m, map[string]interface{}{
+				"job_schedule": map[string]interface{}{
+					"jobName":       "cleanup",
+					"description":   "nightly cleanup",
+					"params":        `{"days":7}`,
+					"startAfter":    "2015-06-16T00:00:00.000Z",
+					"daysOfWeek":    []interface{}{float64(1), float64(3)},
+					"timeOfDay":     "02:30:00",
+					"repeatMinutes": float64(60),
+				},
+			})
+			return jsonResponse(t, http.StatusOK, map[string]string{"objectId": "xyz"}), nil
+		}),
+	}
+	jc := parse.JobSchedulesClient{Client: c}
+	res, err := jc.Create(&parse.JobSchedule{
+		ID:            "ignored",
+		JobName:       "cleanup",
+		Description:   "nightly cleanup",
+		Params:        map[string]interface{}{"days": 7},
+		StartAfter:    time.Date(2015, 6, 16, 0, 0, 0, 0, time.UTC),
+		DaysOfWeek:    []int{1, 3},
+		TimeOfDay:     "02:30:00",
+		RepeatMinutes: 60,
+	})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res.ID, "xyz")
+}
+
+func TestJobSchedulesList(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Method, "GET")
+			ensure.DeepEqual(t, r.URL.Path, "/1/cloud_code/jobs/")
+			return jsonResponse(t, http.StatusOK, []interface{}{
+				map[string]interface{}{
+					"objectId":   "xyz",
+					"jobName":    "cleanup",
+					"params":     `{"days":7}`,
+					"startAfter": "2015-06-16T00:00:00.000Z",
+					"timeOfDay":  "02:30:00",
+				},
+			}), nil
+		}),
+	}
+	jc := parse.JobSchedulesClient{Client: c}
+	schedules, err := jc.List()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, schedules, []*parse.JobSchedule{
+		{
+			ID:         "xyz",
+			JobName:    "cleanup",
+			Params:     map[string]interface{}{"days": float64(7)},
+			StartAfter: time.Date(2015, 6, 16, 0, 0, 0, 0, time.UTC),
+			TimeOfDay:  "02:30:00",
+		},
+	})
+}
+
+func TestJobSchedulesUpdateDelete(t *testing.T) {
+	t.Parallel()
+	var methods []string
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			methods = append(methods, r.Method)
+			ensure.DeepEqual(t, r.URL.Path, "/1/cloud_code/jobs/xyz")
+			return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+		}),
+	}
+	jc := parse.JobSchedulesClient{Client: c}
+	ensure.Nil(t, jc.Update("xyz", &parse.JobSchedule{JobName: "cleanup"}))
+	ensure.Nil(t, jc.Delete("xyz"))
+	ensure.DeepEqual(t, methods, []string{"PUT", "DELETE"})
+}
+
+func TestJobScheduleInvalid(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Schedule *parse.JobSchedule
+		Error    string
+	}{
+		{&parse.JobSchedule{}, "requires JobName"},
+		{&parse.JobSchedule{JobName: "a", TimeOfDay: "25:00:00"}, "TimeOfDay must be"},
+		{&parse.JobSchedule{JobName: "a", DaysOfWeek: []int{7}}, "DaysOfWeek must be"},
+		{&parse.JobSchedule{JobName: "a", RepeatMinutes: -1}, "RepeatMinutes cannot be negative"},
+	}
+	jc := parse.JobSchedulesClient{Client: &parse.Client{}}
+	for _, c := range cases {
+		_, err := jc.Create(c.Schedule)
+		ensure.Err(t, err, regexp.MustCompile(c.Error))
+	}
+}
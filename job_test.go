@@ -0,0 +1,106 @@
+package parse_test
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestTriggerJob(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Method, "POST")
+			ensure.DeepEqual(t, r.URL.Path, "/1/jobs/cleanup")
+			var m map[string]interface{}
+			decodeBody(t, r, &m)
+			ensure.DeepEqual(t, m, map[string]interface{}{"days": float64(7)})
+			res := jsonResponse(t, http.StatusOK, map[string]string{})
+			res.Header.Set("X-Parse-Job-Status-Id", "status")
+			return res, nil
+		}),
+	}
+	id, err := c.TriggerJob("cleanup", map[string]int{"days": 7})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, id, "status")
+}
+
+func TestTriggerJobErrors(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+		}),
+	}
+	_, err := c.TriggerJob("cleanup", nil)
+	ensure.Err(t, err, regexp.MustCompile("job status ID not provided"))
+	_, err = c.TriggerJob("", nil)
+	ensure.Err(t, err, regexp.MustCompile("empty job name"))
+}
+
+func TestWaitJob(t *testing.T) {
+	t.Parallel()
+	statuses := []string{"running", "running", "succeeded"}
+	calls := 0
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.URL.Path, "/1/classes/_JobStatus/status")
+			status := statuses[calls]
+			calls++
+			return jsonResponse(t, http.StatusOK, map[string]string{
+				"objectId": "status",
+				"jobName":  "cleanup",
+				"status":   status,
+			}), nil
+		}),
+	}
+	status, err := c.WaitJob("status", time.Millisecond)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, status, &parse.JobStatus{
+		ID:      "status",
+		JobName: "cleanup",
+		Status:  parse.JobSucceeded,
+	})
+	ensure.DeepEqual(t, calls, 3)
+}
+
+func TestWaitJobFailed(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			return jsonResponse(t, http.StatusOK, map[string]interface{}{
+				"objectId": "status",
+				"jobName":  "cleanup",
+				"status":   "failed",
+				"message":  "out of yaks",
+				"finishedAt": map[string]string{
+					"__type": "Date",
+					"iso":    "2015-06-16T01:02:03.000Z",
+				},
+			}), nil
+		}),
+	}
+	status, err := c.WaitJob("status", time.Millisecond)
+	ensure.Err(t, err, regexp.MustCompile(`job cleanup failed with message="out of yaks"`))
+	ensure.DeepEqual(t, status.Status, parse.JobFailed)
+	ensure.True(t, time.Time(*status.FinishedAt).Equal(time.Date(2015, 6, 16, 1, 2, 3, 0, time.UTC)))
+}
+
+func TestWaitJobCtx(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			cancel()
+			return jsonResponse(t, http.StatusOK, map[string]string{"status": "running"}), nil
+		}),
+	}
+	_, err := c.WaitJobCtx(ctx, "status", time.Hour)
+	ensure.True(t, errors.Is(err, context.Canceled))
+}
@@ -0,0 +1,110 @@
+package parse
+
+import (
+	"fmt"
+	"reflect"
+	"strings"
+)
+
+// keyEscaper and keyUnescaper map the characters Parse doesn't allow in keys to
+// percent escapes, and back.
+var (
+	keyEscaper   = strings.NewReplacer("%", "%25", ".", "%2E", "$", "%24")
+	keyUnescaper = strings.NewReplacer("%25", "%", "%2E", ".", "%2e", ".", "%24", "$")
+)
+
+// whereOperators are the keys of a where clause that combine other clauses.
+var whereOperators = map[string]bool{"$or": true, "$and": true, "$nor": true}
+
+// KeyError is returned when an update body or a where clause has a key that
+// the server would reject or misinterpret, like one with a dot, which is read
+// as a path into an Object field, or one starting with a dollar sign, which is
+// read as an operator.
+type KeyError struct {
+	Key    string
+	Reason string
+}
+
+func (e *KeyError) Error() string {
+	return fmt.Sprintf("parse: invalid key %q: %s", e.Key, e.Reason)
+}
+
+// ValidateKey returns a *KeyError if the key is empty or has a dot or a dollar
+// sign. Use EscapeKey for keys of Object values that may have them.
+func ValidateKey(key string) error {
+	switch {
+	case key == "":
+		return &KeyError{Key: key, Reason: "key is empty"}
+	case strings.Contains(key, "."):
+		return &KeyError{Key: key, Reason: "key contains a dot"}
+	case strings.Contains(key, "$"):
+		return &KeyError{Key: key, Reason: "key contains a dollar sign"}
+	}
+	return nil
+}
+
+// EscapeKey returns the key with dots, dollar signs and percent signs replaced
+// by their percent escapes, such as "%2E" for a dot, so that it can be stored
+// as a key of an Object value. UnescapeKey reverses it.
+func EscapeKey(key string) string {
+	return keyEscaper.Replace(key)
+}
+
+// UnescapeKey returns the key escaped by EscapeKey.
+func UnescapeKey(key string) string {
+	return keyUnescaper.Replace(key)
+}
+
+// validateBody checks the keys of an update body when it is a map. The keys of
+// structs come from their tags, and other values are sent as they are.
+func validateBody(v interface{}) error {
+	rv := reflect.ValueOf(v)
+	if rv.Kind() != reflect.Map || rv.Type().Key().Kind() != reflect.String {
+		return nil
+	}
+	for _, k := range rv.MapKeys() {
+		if err := ValidateKey(k.String()); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// validate checks the keys of the where clause, including those of the
+// clauses combined with $or, $and and $nor. The constraints of a key, like
+// $gt, are not checked.
+func (w Where) validate() error {
+	for k, v := range w {
+		if !whereOperators[k] {
+			if k == "$relatedTo" {
+				continue
+			}
+			if err := ValidateKey(k); err != nil {
+				return err
+			}
+			continue
+		}
+		var clauses []interface{}
+		switch c := v.(type) {
+		case []Where:
+			for _, w := range c {
+				clauses = append(clauses, w)
+			}
+		case []interface{}:
+			clauses = c
+		}
+		for _, c := range clauses {
+			var sub Where
+			switch c := c.(type) {
+			case Where:
+				sub = c
+			case map[string]interface{}:
+				sub = Where(c)
+			}
+			if err := sub.validate(); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package parse_test
+
+import (
+	"encoding/json"
+	"errors"
+	"net/http"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestValidateKey(t *testing.T) {
+	t.Parallel()
+	ensure.Nil(t, parse.ValidateKey("name"))
+	ensure.DeepEqual(t, parse.ValidateKey("a.b").Error(), `parse: invalid key "a.b": key contains a dot`)
+	ensure.DeepEqual(t, parse.ValidateKey("$inc").Error(), `parse: invalid key "$inc": key contains a dollar sign`)
+	ensure.DeepEqual(t, parse.ValidateKey("").Error(), `parse: invalid key "": key is empty`)
+}
+
+func TestEscapeKey(t *testing.T) {
+	t.Parallel()
+	for _, k := range []string{"name", "a.b", "$price", "100%.5", "%2E"} {
+		e := parse.EscapeKey(k)
+		ensure.Nil(t, parse.ValidateKey(e))
+		ensure.DeepEqual(t, parse.UnescapeKey(e), k)
+	}
+	ensure.DeepEqual(t, parse.EscapeKey("v1.2$"), "v1%2E2%24")
+}
+
+func TestKeyErrorPut(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+		panic("not reached")
+	})}
+	o := c.Class("Yak")
+	_, err := o.Put("xyz", map[string]interface{}{"stats.weight": 3})
+	var ke *parse.KeyError
+	ensure.True(t, errors.As(err, &ke))
+	ensure.DeepEqual(t, ke.Key, "stats.weight")
+
+	_, err = o.Post(map[string]json.RawMessage{"$set": json.RawMessage("{}")})
+	ensure.True(t, errors.As(err, &ke))
+
+	_, err = o.PostAll([]map[string]int{{"ok": 1}, {"a.b": 2}})
+	ensure.True(t, errors.As(err, &ke))
+	ensure.DeepEqual(t, ke.Key, "a.b")
+}
+
+func TestKeyErrorWhere(t *testing.T) {
+	t.Parallel()
+	p := parse.Params{Where: parse.Where{
+		"$or": []parse.Where{
+			parse.Where{}.EqualTo("name", "Al"),
+			parse.Where{}.GreaterThan("stats.age", 3),
+		},
+	}}
+	_, err := p.Values()
+	ensure.DeepEqual(t, err.Error(), `parse: invalid key "stats.age": key contains a dot`)
+
+	p.Where = parse.Where{}.GreaterThan("age", 3).Exists("name")
+	_, err = p.Values()
+	ensure.Nil(t, err)
+
+	// Other where clauses are sent as they are.
+	p.Where = map[string]interface{}{"stats.age": 3}
+	_, err = p.Values()
+	ensure.Nil(t, err)
+}
@@ -0,0 +1,173 @@
+package parse
+
+import (
+	"encoding/json"
+	"math"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var unmarshalerType = reflect.TypeOf((*json.Unmarshaler)(nil)).Elem()
+
+// hasLenientFields reports whether t has struct fields tagged with
+// parse:"lenient", directly or in nested structs, slices and maps.
+func hasLenientFields(t reflect.Type, seen map[reflect.Type]bool) bool {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if seen[t] {
+		return false
+	}
+	seen[t] = true
+	switch t.Kind() {
+	case reflect.Struct:
+		for i := 0; i < t.NumField(); i++ {
+			sf := t.Field(i)
+			if lenientTag(sf) || hasLenientFields(sf.Type, seen) {
+				return true
+			}
+		}
+	case reflect.Slice, reflect.Array, reflect.Map:
+		return hasLenientFields(t.Elem(), seen)
+	}
+	return false
+}
+
+func lenientTag(sf reflect.StructField) bool {
+	for _, opt := range strings.Split(sf.Tag.Get("parse"), ",") {
+		if opt == "lenient" {
+			return true
+		}
+	}
+	return false
+}
+
+// coerce converts the decoded JSON value v to suit the type t, where lenient
+// says whether the value may be coerced or only its children. Numbers stored
+// as strings become numbers, 0, 1 and their strings become booleans, and
+// numbers and booleans become strings. Other values are left alone, so that
+// decoding fails on them as usual.
+func coerce(v interface{}, t reflect.Type, lenient bool) interface{} {
+	for t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if reflect.PtrTo(t).Implements(unmarshalerType) {
+		return v
+	}
+	switch t.Kind() {
+	case reflect.Struct:
+		m, ok := v.(map[string]interface{})
+		if !ok {
+			return v
+		}
+		coerceFields(m, t, lenient)
+	case reflect.Slice, reflect.Array:
+		if a, ok := v.([]interface{}); ok {
+			for i := range a {
+				a[i] = coerce(a[i], t.Elem(), lenient)
+			}
+		}
+	case reflect.Map:
+		if m, ok := v.(map[string]interface{}); ok {
+			for k := range m {
+				m[k] = coerce(m[k], t.Elem(), lenient)
+			}
+		}
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		if !lenient {
+			return v
+		}
+		switch x := v.(type) {
+		case string:
+			s := strings.TrimSpace(x)
+			if s == "" {
+				return nil
+			}
+			if f, err := strconv.ParseFloat(s, 64); err == nil && !math.IsInf(f, 0) && !math.IsNaN(f) {
+				// Numbers like "02139" or "+5" are not valid JSON.
+				if !json.Valid([]byte(s)) {
+					s = strconv.FormatFloat(f, 'f', -1, 64)
+				}
+				return json.Number(s)
+			}
+		case bool:
+			if x {
+				return json.Number("1")
+			}
+			return json.Number("0")
+		}
+	case reflect.Bool:
+		if !lenient {
+			return v
+		}
+		switch x := v.(type) {
+		case json.Number:
+			if f, err := x.Float64(); err == nil {
+				return f != 0
+			}
+		case string:
+			s := strings.TrimSpace(x)
+			if s == "" {
+				return nil
+			}
+			if b, err := strconv.ParseBool(s); err == nil {
+				return b
+			}
+		}
+	case reflect.String:
+		if !lenient {
+			return v
+		}
+		switch x := v.(type) {
+		case json.Number:
+			return x.String()
+		case bool:
+			return strconv.FormatBool(x)
+		}
+	}
+	return v
+}
+
+// coerceFields coerces the values of m for the fields of the struct type t,
+// matching names the way encoding/json does.
+func coerceFields(m map[string]interface{}, t reflect.Type, lenient bool) {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" && !sf.Anonymous {
+			continue
+		}
+		name := sf.Name
+		if tag := sf.Tag.Get("json"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			if n := strings.Split(tag, ",")[0]; n != "" {
+				name = n
+			}
+		}
+		ft := sf.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		fieldLenient := lenient || lenientTag(sf)
+		if sf.Anonymous && sf.Tag.Get("json") == "" && ft.Kind() == reflect.Struct {
+			coerceFields(m, ft, fieldLenient)
+			continue
+		}
+		key, ok := name, false
+		if _, ok = m[name]; !ok {
+			for k := range m {
+				if strings.EqualFold(k, name) {
+					key, ok = k, true
+					break
+				}
+			}
+		}
+		if ok {
+			m[key] = coerce(m[key], sf.Type, fieldLenient)
+		}
+	}
+}
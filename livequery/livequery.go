@@ -0,0 +1,448 @@
+// Package livequery subscribes to changes of Parse objects using the
+// LiveQuery server of Parse Server. The events are decoded into the types of
+// the channels they are delivered on, and the Client reconnects and
+// subscribes again after network failures:
+//
+//	c := &livequery.Client{URL: u, Credentials: cr}
+//	created := make(chan *Yak)
+//	sub, err := c.Subscribe(livequery.Query{ClassName: "Yak"}, livequery.Events{Create: created})
+//	go c.Run(ctx)
+//	for yak := range created {
+//		...
+//	}
+package livequery
+
+import (
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sync"
+	"time"
+
+	"github.com/facebookgo/parse"
+)
+
+const (
+	defaultMinBackoff = time.Second
+	defaultMaxBackoff = 30 * time.Second
+)
+
+var (
+	errNoURL       = errors.New("livequery: Client needs a URL")
+	errRunning     = errors.New("livequery: Client is already running")
+	errNoClassName = errors.New("livequery: Query needs a ClassName")
+)
+
+// The headers of the Credentials and the fields of the connect message they
+// are sent in.
+var connectFields = map[string]string{
+	"X-Parse-Application-Id": "applicationId",
+	"X-Parse-Rest-Api-Key":   "restAPIKey",
+	"X-Parse-Javascript-Key": "javascriptKey",
+	"X-Parse-Master-Key":     "masterKey",
+	"X-Parse-Client-Key":     "clientKey",
+	"X-Parse-Session-Token":  "sessionToken",
+}
+
+// Query selects the objects of a Subscription.
+type Query struct {
+	ClassName string      `json:"className"`
+	Where     parse.Where `json:"where,omitempty"`
+
+	// Fields optionally restricts the fields of the objects of the events.
+	Fields []string `json:"fields,omitempty"`
+}
+
+// Events are the channels the events of a Subscription are delivered on.
+// Each one is optional, and must be a channel of a type the objects decode
+// into, such as chan *Yak or chan map[string]interface{}. The Client waits
+// for every event to be received, so the channels must be read until the
+// Subscription is removed or the Client stops.
+type Events struct {
+	// Create receives objects created matching the Query.
+	Create interface{}
+
+	// Update receives objects that matched the Query and still do after an
+	// update.
+	Update interface{}
+
+	// Delete receives deleted objects that matched the Query.
+	Delete interface{}
+
+	// Enter receives objects that match the Query after an update, and
+	// didn't before.
+	Enter interface{}
+
+	// Leave receives objects that matched the Query before an update, and
+	// don't anymore.
+	Leave interface{}
+}
+
+// Client is a connection to a LiveQuery server. Subscriptions can be added
+// and removed at any time, and are sent to the server by Run.
+type Client struct {
+	// URL of the LiveQuery server, such as wss://example.com/parse.
+	URL *url.URL
+
+	// Credentials are sent in the connect message. SessionToken
+	// credentials restrict the events to the objects the user can read.
+	Credentials parse.Credentials
+
+	// Dialer optionally replaces the default Dialer.
+	Dialer *net.Dialer
+
+	// TLSConfig optionally configures wss connections.
+	TLSConfig *tls.Config
+
+	// MinBackoff is the wait before the first reconnection after a
+	// failure, doubled after each failed one up to MaxBackoff. They default
+	// to 1 and 30 seconds.
+	MinBackoff time.Duration
+	MaxBackoff time.Duration
+
+	// Clock optionally replaces the real clock for the reconnection waits,
+	// in tests.
+	Clock parse.Clock
+
+	// Error is optionally called with the errors that make the Client
+	// reconnect, the errors the server reports and the events that cannot
+	// be decoded.
+	Error func(error)
+
+	mu      sync.Mutex
+	subs    map[int]*Subscription
+	nextID  int
+	conn    *conn
+	running bool
+}
+
+// Subscription is a Query whose events are delivered while the Client runs.
+type Subscription struct {
+	client *Client
+	id     int
+	query  Query
+	events map[string]reflect.Value
+
+	// removed is closed by Unsubscribe, abandoning a waiting delivery.
+	removed chan struct{}
+	once    sync.Once
+}
+
+// subscribeRequest is the message subscribing to a Query.
+type subscribeRequest struct {
+	Op           string `json:"op"`
+	RequestID    int    `json:"requestId"`
+	Query        Query  `json:"query"`
+	SessionToken string `json:"sessionToken,omitempty"`
+}
+
+// message is a message of the server.
+type message struct {
+	Op        string          `json:"op"`
+	RequestID int             `json:"requestId"`
+	Object    json.RawMessage `json:"object"`
+	Code      int             `json:"code"`
+	Error     string          `json:"error"`
+	Reconnect bool            `json:"reconnect"`
+}
+
+// Subscribe adds a subscription to the events of the Query. It is sent to
+// the server when the Client is connected, and again after reconnecting.
+func (c *Client) Subscribe(q Query, e Events) (*Subscription, error) {
+	if q.ClassName == "" {
+		return nil, errNoClassName
+	}
+	s := &Subscription{
+		client:  c,
+		query:   q,
+		events:  make(map[string]reflect.Value),
+		removed: make(chan struct{}),
+	}
+	for op, ch := range map[string]interface{}{
+		"create": e.Create,
+		"update": e.Update,
+		"delete": e.Delete,
+		"enter":  e.Enter,
+		"leave":  e.Leave,
+	} {
+		if ch == nil {
+			continue
+		}
+		v := reflect.ValueOf(ch)
+		if v.Kind() != reflect.Chan || v.Type().ChanDir()&reflect.SendDir == 0 {
+			return nil, fmt.Errorf("livequery: %s events need a channel, not %T", op, ch)
+		}
+		s.events[op] = v
+	}
+
+	c.mu.Lock()
+	if c.subs == nil {
+		c.subs = make(map[int]*Subscription)
+	}
+	c.nextID++
+	s.id = c.nextID
+	c.subs[s.id] = s
+	cn := c.conn
+	c.mu.Unlock()
+
+	if cn != nil {
+		// A failure breaks the connection, which Run restores with the
+		// subscription included.
+		c.subscribe(cn, s)
+	}
+	return s, nil
+}
+
+// Unsubscribe removes the subscription. Once it returns no new events are
+// delivered, and an event waiting to be received is dropped.
+func (s *Subscription) Unsubscribe() error {
+	s.once.Do(func() { close(s.removed) })
+	c := s.client
+	c.mu.Lock()
+	delete(c.subs, s.id)
+	cn := c.conn
+	c.mu.Unlock()
+	if cn == nil {
+		return nil
+	}
+	b, err := json.Marshal(map[string]interface{}{"op": "unsubscribe", "requestId": s.id})
+	if err != nil {
+		return err
+	}
+	return cn.writeMessage(b)
+}
+
+// Run connects to the server and delivers the events until the context is
+// done, reconnecting with a backoff after failures.
+func (c *Client) Run(ctx context.Context) error {
+	if c.URL == nil {
+		return errNoURL
+	}
+	c.mu.Lock()
+	if c.running {
+		c.mu.Unlock()
+		return errRunning
+	}
+	c.running = true
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		c.running = false
+		c.mu.Unlock()
+	}()
+
+	backoff := c.minBackoff()
+	for {
+		connected, err := c.session(ctx)
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		c.report(err)
+		if connected {
+			backoff = c.minBackoff()
+		}
+		if err := sleep(ctx, c.clock(), backoff); err != nil {
+			return err
+		}
+		if backoff *= 2; backoff > c.maxBackoff() {
+			backoff = c.maxBackoff()
+		}
+	}
+}
+
+// session connects, subscribes and delivers the events until the connection
+// fails. It reports whether the server accepted the connection.
+func (c *Client) session(ctx context.Context) (bool, error) {
+	cn, err := dial(ctx, c.URL, c.Dialer, c.TLSConfig)
+	if err != nil {
+		return false, err
+	}
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			cn.c.Close()
+		case <-done:
+		}
+	}()
+	defer cn.close()
+
+	if err := c.connect(cn); err != nil {
+		return false, err
+	}
+
+	c.mu.Lock()
+	c.conn = cn
+	subs := make([]*Subscription, 0, len(c.subs))
+	for _, s := range c.subs {
+		subs = append(subs, s)
+	}
+	c.mu.Unlock()
+	defer func() {
+		c.mu.Lock()
+		c.conn = nil
+		c.mu.Unlock()
+	}()
+	for _, s := range subs {
+		if err := c.subscribe(cn, s); err != nil {
+			return true, err
+		}
+	}
+
+	for {
+		b, err := cn.readMessage()
+		if err != nil {
+			return true, err
+		}
+		var m message
+		if err := json.Unmarshal(b, &m); err != nil {
+			return true, err
+		}
+		if m.Op == "error" {
+			err := &parse.Error{Code: m.Code, Message: m.Error}
+			if m.Reconnect {
+				return true, err
+			}
+			c.report(err)
+			continue
+		}
+		if err := c.deliver(ctx, m); err != nil {
+			return true, err
+		}
+	}
+}
+
+// connect sends the connect message with the Credentials and waits for the
+// server to accept it.
+func (c *Client) connect(cn *conn) error {
+	connect := map[string]string{"op": "connect"}
+	if c.Credentials != nil {
+		r := &http.Request{Header: make(http.Header)}
+		if err := c.Credentials.Modify(r); err != nil {
+			return err
+		}
+		for h, f := range connectFields {
+			if v := r.Header.Get(h); v != "" {
+				connect[f] = v
+			}
+		}
+	}
+	b, err := json.Marshal(connect)
+	if err != nil {
+		return err
+	}
+	if err := cn.writeMessage(b); err != nil {
+		return err
+	}
+	b, err = cn.readMessage()
+	if err != nil {
+		return err
+	}
+	var m message
+	if err := json.Unmarshal(b, &m); err != nil {
+		return err
+	}
+	switch m.Op {
+	case "connected":
+		return nil
+	case "error":
+		return &parse.Error{Code: m.Code, Message: m.Error}
+	}
+	return fmt.Errorf("livequery: unexpected %q message instead of connected", m.Op)
+}
+
+// subscribe sends the subscribe message of the Subscription.
+func (c *Client) subscribe(cn *conn, s *Subscription) error {
+	req := subscribeRequest{Op: "subscribe", RequestID: s.id, Query: s.query}
+	if c.Credentials != nil {
+		r := &http.Request{Header: make(http.Header)}
+		if err := c.Credentials.Modify(r); err != nil {
+			return err
+		}
+		req.SessionToken = r.Header.Get("X-Parse-Session-Token")
+	}
+	b, err := json.Marshal(req)
+	if err != nil {
+		return err
+	}
+	return cn.writeMessage(b)
+}
+
+// deliver sends the object of an event on the channel of its Subscription,
+// waiting for it to be received or for the context to be done.
+func (c *Client) deliver(ctx context.Context, m message) error {
+	c.mu.Lock()
+	s := c.subs[m.RequestID]
+	c.mu.Unlock()
+	if s == nil {
+		return nil
+	}
+	ch, ok := s.events[m.Op]
+	if !ok {
+		return nil
+	}
+	t := ch.Type().Elem()
+	v := reflect.New(t)
+	if err := json.Unmarshal(m.Object, v.Interface()); err != nil {
+		c.report(fmt.Errorf("livequery: cannot decode %s event of %s: %s", m.Op, s.query.ClassName, err))
+		return nil
+	}
+	chosen, _, _ := reflect.Select([]reflect.SelectCase{
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(ctx.Done())},
+		{Dir: reflect.SelectRecv, Chan: reflect.ValueOf(s.removed)},
+		{Dir: reflect.SelectSend, Chan: ch, Send: v.Elem()},
+	})
+	if chosen == 0 {
+		return ctx.Err()
+	}
+	return nil
+}
+
+func (c *Client) report(err error) {
+	if c.Error != nil && err != nil {
+		c.Error(err)
+	}
+}
+
+func (c *Client) minBackoff() time.Duration {
+	if c.MinBackoff > 0 {
+		return c.MinBackoff
+	}
+	return defaultMinBackoff
+}
+
+func (c *Client) maxBackoff() time.Duration {
+	if c.MaxBackoff > 0 {
+		return c.MaxBackoff
+	}
+	return defaultMaxBackoff
+}
+
+type realClock struct{}
+
+func (realClock) Now() time.Time                         { return time.Now() }
+func (realClock) After(d time.Duration) <-chan time.Time { return time.After(d) }
+
+func (c *Client) clock() parse.Clock {
+	if c.Clock == nil {
+		return realClock{}
+	}
+	return c.Clock
+}
+
+// sleep waits on the clock for d, or until the context is done.
+func sleep(ctx context.Context, clock parse.Clock, d time.Duration) error {
+	select {
+	case <-clock.After(d):
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
@@ -0,0 +1,314 @@
+package livequery_test
+
+import (
+	"bufio"
+	"context"
+	"crypto/sha1"
+	"encoding/base64"
+	"encoding/binary"
+	"encoding/json"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"regexp"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+	"github.com/facebookgo/parse/livequery"
+	"github.com/facebookgo/parse/parsetest"
+)
+
+// fakeServer is a LiveQuery server whose connections are handed to the test.
+type fakeServer struct {
+	*httptest.Server
+	conns chan *serverConn
+}
+
+// serverConn is the server side of a WebSocket connection.
+type serverConn struct {
+	t  *testing.T
+	c  net.Conn
+	br *bufio.Reader
+}
+
+func newFakeServer(t *testing.T) *fakeServer {
+	s := &fakeServer{conns: make(chan *serverConn, 4)}
+	s.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		h := sha1.New()
+		h.Write([]byte(r.Header.Get("Sec-WebSocket-Key") + "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"))
+		c, brw, err := w.(http.Hijacker).Hijack()
+		ensure.Nil(t, err)
+		brw.WriteString("HTTP/1.1 101 Switching Protocols\r\n" +
+			"Upgrade: websocket\r\nConnection: Upgrade\r\n" +
+			"Sec-WebSocket-Accept: " + base64.StdEncoding.EncodeToString(h.Sum(nil)) + "\r\n\r\n")
+		ensure.Nil(t, brw.Flush())
+		s.conns <- &serverConn{t: t, c: c, br: brw.Reader}
+	}))
+	return s
+}
+
+func (s *fakeServer) url() *url.URL {
+	u, _ := url.Parse(s.URL)
+	u.Scheme = "ws"
+	return u
+}
+
+// accept returns the next connection, after answering its connect message.
+func (s *fakeServer) accept() (*serverConn, map[string]interface{}) {
+	select {
+	case sc := <-s.conns:
+		connect := sc.read()
+		sc.write(map[string]interface{}{"op": "connected", "clientId": 1})
+		sc.readPong()
+		return sc, connect
+	case <-time.After(5 * time.Second):
+		panic("no connection")
+	}
+}
+
+// read returns the next message of the client, which must be masked.
+func (sc *serverConn) read() map[string]interface{} {
+	var h [2]byte
+	_, err := io.ReadFull(sc.br, h[:])
+	ensure.Nil(sc.t, err)
+	ensure.DeepEqual(sc.t, h[0], byte(0x81))
+	ensure.True(sc.t, h[1]&0x80 != 0, "unmasked frame")
+	n := int(h[1] & 0x7f)
+	if n == 126 {
+		var b [2]byte
+		_, err = io.ReadFull(sc.br, b[:])
+		ensure.Nil(sc.t, err)
+		n = int(binary.BigEndian.Uint16(b[:]))
+	}
+	var mask [4]byte
+	_, err = io.ReadFull(sc.br, mask[:])
+	ensure.Nil(sc.t, err)
+	b := make([]byte, n)
+	_, err = io.ReadFull(sc.br, b)
+	ensure.Nil(sc.t, err)
+	for i := range b {
+		b[i] ^= mask[i%4]
+	}
+	var m map[string]interface{}
+	ensure.Nil(sc.t, json.Unmarshal(b, &m))
+	return m
+}
+
+// write sends a message, split in two frames to exercise continuations.
+func (sc *serverConn) write(v interface{}) {
+	b, err := json.Marshal(v)
+	ensure.Nil(sc.t, err)
+	half := len(b) / 2
+	sc.frame(0x01, b[:half])
+	sc.frame(0x89, []byte("ping"))
+	sc.frame(0x80, b[half:])
+}
+
+func (sc *serverConn) frame(op byte, b []byte) {
+	header := []byte{op, byte(len(b))}
+	if len(b) >= 126 {
+		header = []byte{op, 126, 0, 0}
+		binary.BigEndian.PutUint16(header[2:], uint16(len(b)))
+	}
+	_, err := sc.c.Write(append(header, b...))
+	ensure.Nil(sc.t, err)
+}
+
+// readPong skips the pong answering the ping sent by write.
+func (sc *serverConn) readPong() {
+	var h [6]byte
+	_, err := io.ReadFull(sc.br, h[:])
+	ensure.Nil(sc.t, err)
+	ensure.DeepEqual(sc.t, h[0], byte(0x8a))
+	_, err = io.ReadFull(sc.br, make([]byte, h[1]&0x7f))
+	ensure.Nil(sc.t, err)
+}
+
+type yak struct {
+	ID   string `json:"objectId"`
+	Name string `json:"name"`
+}
+
+func event(op string, requestID int, name string) map[string]interface{} {
+	return map[string]interface{}{
+		"op":        op,
+		"clientId":  1,
+		"requestId": requestID,
+		"object":    map[string]string{"className": "Yak", "objectId": "y-" + name, "name": name},
+	}
+}
+
+func TestSubscribe(t *testing.T) {
+	t.Parallel()
+	s := newFakeServer(t)
+	defer s.Close()
+	c := &livequery.Client{
+		URL: s.url(),
+		Credentials: parse.SessionToken{
+			ApplicationID: "app",
+			RestAPIKey:    "rest",
+			SessionToken:  "r:token",
+		},
+	}
+	created := make(chan *yak)
+	updated := make(chan yak)
+	other := make(chan map[string]interface{})
+	_, err := c.Subscribe(
+		livequery.Query{ClassName: "Yak", Where: parse.Where{}.EqualTo("tame", true), Fields: []string{"name"}},
+		livequery.Events{Create: created, Update: updated, Enter: other, Leave: other, Delete: other},
+	)
+	ensure.Nil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- c.Run(ctx) }()
+	sc, connect := s.accept()
+	ensure.DeepEqual(t, connect, map[string]interface{}{
+		"op":            "connect",
+		"applicationId": "app",
+		"restAPIKey":    "rest",
+		"sessionToken":  "r:token",
+	})
+	ensure.DeepEqual(t, sc.read(), map[string]interface{}{
+		"op":        "subscribe",
+		"requestId": float64(1),
+		"query": map[string]interface{}{
+			"className": "Yak",
+			"where":     map[string]interface{}{"tame": true},
+			"fields":    []interface{}{"name"},
+		},
+		"sessionToken": "r:token",
+	})
+
+	sc.write(event("create", 1, "al"))
+	sc.readPong()
+	ensure.DeepEqual(t, <-created, &yak{ID: "y-al", Name: "al"})
+	sc.write(event("update", 1, "bo"))
+	sc.readPong()
+	ensure.DeepEqual(t, <-updated, yak{ID: "y-bo", Name: "bo"})
+	for _, op := range []string{"enter", "leave", "delete"} {
+		sc.write(event(op, 1, op))
+		sc.readPong()
+		ensure.DeepEqual(t, (<-other)["name"], op)
+	}
+
+	cancel()
+	ensure.DeepEqual(t, <-done, context.Canceled)
+}
+
+func TestReconnect(t *testing.T) {
+	t.Parallel()
+	s := newFakeServer(t)
+	defer s.Close()
+	clock := parsetest.NewClock(time.Date(2015, 6, 16, 1, 2, 3, 0, time.UTC))
+	var mu sync.Mutex
+	var errs []error
+	c := &livequery.Client{
+		URL:        s.url(),
+		Clock:      clock,
+		MinBackoff: time.Second,
+		Error: func(err error) {
+			mu.Lock()
+			errs = append(errs, err)
+			mu.Unlock()
+		},
+	}
+	created := make(chan *yak)
+	sub, err := c.Subscribe(livequery.Query{ClassName: "Yak"}, livequery.Events{Create: created})
+	ensure.Nil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- c.Run(ctx) }()
+	sc, _ := s.accept()
+	ensure.DeepEqual(t, sc.read()["op"], "subscribe")
+	sc.c.Close()
+
+	// The Client waits for the backoff on the Clock, then subscribes again.
+	for clock.Waiters() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	clock.Advance(time.Second)
+	sc, _ = s.accept()
+	m := sc.read()
+	ensure.DeepEqual(t, m["op"], "subscribe")
+	ensure.DeepEqual(t, m["requestId"], float64(1))
+
+	// Events for a subscription added while connected are delivered.
+	herds := make(chan map[string]interface{})
+	_, err = c.Subscribe(livequery.Query{ClassName: "Herd"}, livequery.Events{Create: herds})
+	ensure.Nil(t, err)
+	m = sc.read()
+	ensure.DeepEqual(t, m["requestId"], float64(2))
+	sc.write(event("create", 2, "herd"))
+	sc.readPong()
+	ensure.DeepEqual(t, (<-herds)["name"], "herd")
+
+	sc.write(event("create", 1, "al"))
+	sc.readPong()
+	ensure.DeepEqual(t, (<-created).Name, "al")
+
+	ensure.Nil(t, sub.Unsubscribe())
+	ensure.DeepEqual(t, sc.read(), map[string]interface{}{"op": "unsubscribe", "requestId": float64(1)})
+
+	// Errors the server reports without asking to reconnect are reported.
+	sc.write(map[string]interface{}{"op": "error", "code": 141, "error": "bad query", "reconnect": false})
+	sc.readPong()
+	sc.write(event("create", 2, "herd2"))
+	sc.readPong()
+	ensure.DeepEqual(t, (<-herds)["name"], "herd2")
+
+	cancel()
+	ensure.DeepEqual(t, <-done, context.Canceled)
+	mu.Lock()
+	defer mu.Unlock()
+	ensure.DeepEqual(t, len(errs), 2)
+	ensure.DeepEqual(t, errs[1], &parse.Error{Code: 141, Message: "bad query"})
+}
+
+func TestBackoff(t *testing.T) {
+	t.Parallel()
+	clock := parsetest.NewClock(time.Date(2015, 6, 16, 1, 2, 3, 0, time.UTC))
+	// A closed listener refuses the connections.
+	l, err := net.Listen("tcp", "127.0.0.1:0")
+	ensure.Nil(t, err)
+	l.Close()
+	c := &livequery.Client{
+		URL:        &url.URL{Scheme: "ws", Host: l.Addr().String()},
+		Clock:      clock,
+		MinBackoff: time.Second,
+		MaxBackoff: 3 * time.Second,
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- c.Run(ctx) }()
+
+	// The waits double from MinBackoff up to MaxBackoff.
+	for _, d := range []time.Duration{time.Second, 2 * time.Second, 3 * time.Second, 3 * time.Second} {
+		for clock.Waiters() == 0 {
+			time.Sleep(time.Millisecond)
+		}
+		clock.Advance(d - time.Nanosecond)
+		ensure.DeepEqual(t, clock.Waiters(), 1)
+		clock.Advance(time.Nanosecond)
+	}
+	cancel()
+	ensure.DeepEqual(t, <-done, context.Canceled)
+}
+
+func TestSubscribeInvalid(t *testing.T) {
+	t.Parallel()
+	c := &livequery.Client{}
+	_, err := c.Subscribe(livequery.Query{}, livequery.Events{})
+	ensure.Err(t, err, regexp.MustCompile("needs a ClassName"))
+	_, err = c.Subscribe(livequery.Query{ClassName: "Yak"}, livequery.Events{Create: yak{}})
+	ensure.Err(t, err, regexp.MustCompile("create events need a channel, not livequery_test.yak"))
+	_, err = c.Subscribe(livequery.Query{ClassName: "Yak"}, livequery.Events{Create: make(<-chan yak)})
+	ensure.Err(t, err, regexp.MustCompile("need a channel"))
+	ensure.Err(t, c.Run(context.Background()), regexp.MustCompile("needs a URL"))
+}
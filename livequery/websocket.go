@@ -0,0 +1,284 @@
+package livequery
+
+import (
+	"bufio"
+	"context"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/tls"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"net"
+	"net/http"
+	"net/url"
+	"strings"
+	"sync"
+)
+
+// The frame opcodes of RFC 6455.
+const (
+	opContinuation = 0x0
+	opText         = 0x1
+	opBinary       = 0x2
+	opClose        = 0x8
+	opPing         = 0x9
+	opPong         = 0xa
+)
+
+// maxMessageSize limits the size of a message read from the server.
+const maxMessageSize = 16 << 20
+
+// websocketGUID is appended to the key of the handshake to compute the
+// Sec-WebSocket-Accept header.
+const websocketGUID = "258EAFA5-E914-47DA-95CA-C5AB0DC85B11"
+
+var (
+	errMessageTooLarge = errors.New("livequery: message too large")
+	errBadFrame        = errors.New("livequery: invalid websocket frame")
+)
+
+// conn is the client side of a WebSocket connection, as much of it as
+// LiveQuery needs: text messages, pings and closing.
+type conn struct {
+	c  net.Conn
+	br *bufio.Reader
+
+	mu sync.Mutex // serializes writes
+}
+
+// websocketAccept returns the Sec-WebSocket-Accept for the key of the
+// handshake.
+func websocketAccept(key string) string {
+	h := sha1.New()
+	h.Write([]byte(key + websocketGUID))
+	return base64.StdEncoding.EncodeToString(h.Sum(nil))
+}
+
+// dial opens a WebSocket connection to the ws or wss URL. The http and https
+// schemes are accepted too.
+func dial(ctx context.Context, u *url.URL, d *net.Dialer, tlsConfig *tls.Config) (*conn, error) {
+	secure := false
+	switch u.Scheme {
+	case "ws", "http":
+	case "wss", "https":
+		secure = true
+	default:
+		return nil, fmt.Errorf("livequery: unsupported scheme %q", u.Scheme)
+	}
+	host := u.Host
+	if u.Port() == "" {
+		if secure {
+			host = net.JoinHostPort(u.Hostname(), "443")
+		} else {
+			host = net.JoinHostPort(u.Hostname(), "80")
+		}
+	}
+	if d == nil {
+		d = &net.Dialer{}
+	}
+	nc, err := d.DialContext(ctx, "tcp", host)
+	if err != nil {
+		return nil, err
+	}
+
+	// Abort the handshakes when the context is done.
+	done := make(chan struct{})
+	defer close(done)
+	go func() {
+		select {
+		case <-ctx.Done():
+			nc.Close()
+		case <-done:
+		}
+	}()
+
+	c, err := handshake(nc, u, secure, tlsConfig)
+	if err != nil {
+		nc.Close()
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		return nil, err
+	}
+	return c, nil
+}
+
+// handshake upgrades the connection to a WebSocket, after a TLS handshake
+// when secure.
+func handshake(nc net.Conn, u *url.URL, secure bool, tlsConfig *tls.Config) (*conn, error) {
+	if secure {
+		cfg := &tls.Config{}
+		if tlsConfig != nil {
+			cfg = tlsConfig.Clone()
+		}
+		if cfg.ServerName == "" {
+			cfg.ServerName = u.Hostname()
+		}
+		tc := tls.Client(nc, cfg)
+		if err := tc.Handshake(); err != nil {
+			return nil, err
+		}
+		nc = tc
+	}
+	var b [16]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		return nil, err
+	}
+	key := base64.StdEncoding.EncodeToString(b[:])
+	req := &http.Request{
+		Method: "GET",
+		URL:    &url.URL{Path: u.Path, RawPath: u.RawPath, RawQuery: u.RawQuery},
+		Host:   u.Host,
+		Header: http.Header{
+			"Upgrade":               {"websocket"},
+			"Connection":            {"Upgrade"},
+			"Sec-WebSocket-Key":     {key},
+			"Sec-WebSocket-Version": {"13"},
+		},
+	}
+	if req.URL.Path == "" {
+		req.URL.Path = "/"
+	}
+	if err := req.Write(nc); err != nil {
+		return nil, err
+	}
+	br := bufio.NewReader(nc)
+	res, err := http.ReadResponse(br, req)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusSwitchingProtocols {
+		res.Body.Close()
+		return nil, fmt.Errorf("livequery: websocket handshake failed with status %d", res.StatusCode)
+	}
+	if !strings.EqualFold(res.Header.Get("Upgrade"), "websocket") ||
+		res.Header.Get("Sec-WebSocket-Accept") != websocketAccept(key) {
+		return nil, errors.New("livequery: invalid websocket handshake response")
+	}
+	return &conn{c: nc, br: br}, nil
+}
+
+// writeFrame writes a single masked frame, as clients must.
+func (c *conn) writeFrame(op byte, payload []byte) error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	header := make([]byte, 2, 14)
+	header[0] = 0x80 | op
+	switch n := len(payload); {
+	case n < 126:
+		header[1] = 0x80 | byte(n)
+	case n <= 0xffff:
+		header[1] = 0x80 | 126
+		header = append(header, 0, 0)
+		binary.BigEndian.PutUint16(header[2:], uint16(n))
+	default:
+		header[1] = 0x80 | 127
+		header = append(header, 0, 0, 0, 0, 0, 0, 0, 0)
+		binary.BigEndian.PutUint64(header[2:], uint64(n))
+	}
+	var mask [4]byte
+	if _, err := rand.Read(mask[:]); err != nil {
+		return err
+	}
+	header = append(header, mask[:]...)
+	masked := make([]byte, len(payload))
+	for i, b := range payload {
+		masked[i] = b ^ mask[i%4]
+	}
+	if _, err := c.c.Write(append(header, masked...)); err != nil {
+		return err
+	}
+	return nil
+}
+
+// writeMessage sends a text message.
+func (c *conn) writeMessage(b []byte) error {
+	return c.writeFrame(opText, b)
+}
+
+// readMessage returns the next text or binary message, answering pings on
+// the way. A close frame from the server is answered and returns io.EOF.
+func (c *conn) readMessage() ([]byte, error) {
+	var message []byte
+	for {
+		fin, op, payload, err := c.readFrame()
+		if err != nil {
+			return nil, err
+		}
+		switch op {
+		case opPing:
+			if err := c.writeFrame(opPong, payload); err != nil {
+				return nil, err
+			}
+			continue
+		case opPong:
+			continue
+		case opClose:
+			c.writeFrame(opClose, nil)
+			return nil, io.EOF
+		case opText, opBinary, opContinuation:
+		default:
+			return nil, errBadFrame
+		}
+		if len(message)+len(payload) > maxMessageSize {
+			return nil, errMessageTooLarge
+		}
+		message = append(message, payload...)
+		if fin {
+			return message, nil
+		}
+	}
+}
+
+func (c *conn) readFrame() (fin bool, op byte, payload []byte, err error) {
+	var h [2]byte
+	if _, err = io.ReadFull(c.br, h[:]); err != nil {
+		return
+	}
+	fin, op = h[0]&0x80 != 0, h[0]&0x0f
+	masked := h[1]&0x80 != 0
+	n := uint64(h[1] & 0x7f)
+	switch n {
+	case 126:
+		var b [2]byte
+		if _, err = io.ReadFull(c.br, b[:]); err != nil {
+			return
+		}
+		n = uint64(binary.BigEndian.Uint16(b[:]))
+	case 127:
+		var b [8]byte
+		if _, err = io.ReadFull(c.br, b[:]); err != nil {
+			return
+		}
+		n = binary.BigEndian.Uint64(b[:])
+	}
+	if n > maxMessageSize {
+		err = errMessageTooLarge
+		return
+	}
+	var mask [4]byte
+	if masked {
+		if _, err = io.ReadFull(c.br, mask[:]); err != nil {
+			return
+		}
+	}
+	payload = make([]byte, n)
+	if _, err = io.ReadFull(c.br, payload); err != nil {
+		return
+	}
+	if masked {
+		for i := range payload {
+			payload[i] ^= mask[i%4]
+		}
+	}
+	return
+}
+
+// close sends a close frame and closes the connection.
+func (c *conn) close() error {
+	c.writeFrame(opClose, nil)
+	return c.c.Close()
+}
@@ -0,0 +1,88 @@
+package parse
+
+import (
+	"net/http"
+	"time"
+)
+
+// RequestLog describes an attempt to perform a request.
+type RequestLog struct {
+	Method string
+
+	// URL is the requested URL, with passwords, session tokens and keys
+	// redacted.
+	URL string
+
+	// StatusCode of the response, or 0 if the attempt failed with Err.
+	StatusCode int
+	Err        error
+
+	// Duration is the time until the response headers arrived.
+	Duration time.Duration
+
+	// Attempt is the number of the attempt, starting at 1, which is greater
+	// than 1 for retries.
+	Attempt int
+
+	// DryRun is set for requests not sent because of Client.DryRun.
+	DryRun bool
+}
+
+// Logger receives a RequestLog for every attempt of the requests of a Client.
+// It is called concurrently when the Client is.
+type Logger interface {
+	LogRequest(RequestLog)
+}
+
+// LoggerFunc is a Logger implemented by a function.
+type LoggerFunc func(RequestLog)
+
+// LogRequest calls f(l).
+func (f LoggerFunc) LogRequest(l RequestLog) {
+	f(l)
+}
+
+// roundTrip performs an attempt of the request, and logs and measures it.
+func (c *Client) roundTrip(t http.RoundTripper, req *http.Request, attempt int) (*http.Response, error) {
+	stats := statsFrom(req.Context())
+	if c.Logger == nil && c.Debug == nil && c.Metrics == nil && stats == nil {
+		return t.RoundTrip(req)
+	}
+	if c.Debug != nil {
+		c.dumpRequest(req, attempt)
+	}
+	start := time.Now()
+	res, err := t.RoundTrip(req)
+	d := time.Since(start)
+	if c.Debug != nil {
+		c.dumpResponse(res, err)
+	}
+	var status int
+	if res != nil {
+		status = res.StatusCode
+	}
+	if stats != nil {
+		stats.Attempts = attempt
+		if res != nil {
+			stats.StatusCode = res.StatusCode
+			stats.Header = res.Header
+			stats.ContentLength = res.ContentLength
+			stats.RateLimit = rateLimitHeader(res.Header)
+		}
+	}
+	if c.Metrics != nil {
+		c.Metrics.ObserveRequest(c.endpoint(req.URL), requestMethod(req), status, d)
+	}
+	if c.Logger != nil {
+		c.Logger.LogRequest(RequestLog{
+			Method:     requestMethod(req),
+			URL:        c.redactURL(req.URL),
+			StatusCode: status,
+			Err:        err,
+			Duration:   d,
+			Attempt:    attempt,
+			DryRun:     c.DryRun && isWrite(requestMethod(req)),
+		})
+	}
+	return res, err
+}
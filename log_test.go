@@ -0,0 +1,44 @@
+package parse_test
+
+import (
+	"errors"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestLogger(t *testing.T) {
+	t.Parallel()
+	var logs []parse.RequestLog
+	calls := 0
+	c := &parse.Client{
+		BaseURL: &url.URL{Scheme: "https", Host: "example.com", Path: "/parse/"},
+		Logger:  parse.LoggerFunc(func(l parse.RequestLog) { logs = append(logs, l) }),
+		Retry:   &parse.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond},
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			if calls == 1 {
+				return nil, errors.New("connection reset by peer")
+			}
+			return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+		}),
+	}
+	u := url.URL{Path: "login", RawQuery: url.Values{"username": {"al"}, "password": {"secret"}}.Encode()}
+	_, err := c.Get(&u, nil)
+	ensure.Nil(t, err)
+
+	ensure.DeepEqual(t, len(logs), 2)
+	for n, l := range logs {
+		ensure.DeepEqual(t, l.Method, "GET")
+		ensure.DeepEqual(t, l.URL, "https://example.com/parse/login?password=REDACTED&username=al")
+		ensure.DeepEqual(t, l.Attempt, n+1)
+	}
+	ensure.NotNil(t, logs[0].Err)
+	ensure.DeepEqual(t, logs[0].StatusCode, 0)
+	ensure.Nil(t, logs[1].Err)
+	ensure.DeepEqual(t, logs[1].StatusCode, http.StatusOK)
+}
@@ -0,0 +1,81 @@
+package parse
+
+import (
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Metrics receives measurements of the requests of a Client. It is called
+// concurrently when the Client is. The endpoint identifies the API without
+// object IDs, such as "classes/Yak", "users" or "functions/hello", to keep
+// the number of distinct values small. For example, with Prometheus:
+//
+//	type promMetrics struct {
+//		latency *prometheus.HistogramVec // labels endpoint, method, status
+//		retries *prometheus.CounterVec   // labels endpoint, method
+//	}
+//
+//	func (m promMetrics) ObserveRequest(endpoint, method string, status int, d time.Duration) {
+//		m.latency.WithLabelValues(endpoint, method, strconv.Itoa(status)).Observe(d.Seconds())
+//	}
+//
+//	func (m promMetrics) IncRetry(endpoint, method string) {
+//		m.retries.WithLabelValues(endpoint, method).Inc()
+//	}
+type Metrics interface {
+	// ObserveRequest is called after every attempt of a request with the
+	// response status code, or 0 if no response was received, and the time
+	// until the response headers arrived.
+	ObserveRequest(endpoint, method string, statusCode int, d time.Duration)
+
+	// IncRetry is called before a request is retried.
+	IncRetry(endpoint, method string)
+}
+
+// The endpoints whose second path segment is an object ID or a file name,
+// rather than part of the endpoint.
+var objectEndpoints = map[string]bool{
+	"users":          true,
+	"roles":          true,
+	"installations":  true,
+	"sessions":       true,
+	"files":          true,
+	"push_audiences": true,
+}
+
+// endpoint returns the endpoint of the URL of an API request.
+func (c *Client) endpoint(u *url.URL) string {
+	e, _ := c.splitPath(u)
+	return e
+}
+
+// splitPath returns the endpoint of the URL of an API request, and the object
+// ID or file name that follows it, if any.
+func (c *Client) splitPath(u *url.URL) (endpoint, id string) {
+	base := c.baseURL()
+	p := u.Path
+	if (u.Host == "" || u.Host == base.Host) && strings.HasPrefix(p, base.Path) {
+		p = p[len(base.Path):]
+	}
+	parts := strings.Split(strings.Trim(p, "/"), "/")
+	switch {
+	case len(parts) == 1:
+		return parts[0], ""
+	case objectEndpoints[parts[0]]:
+		return parts[0], parts[1]
+	case parts[0] == "classes" && len(parts) > 2:
+		return "classes/" + parts[1], parts[2]
+	case parts[0] == "cloud_code" && parts[1] == "jobs" && len(parts) > 2:
+		return "cloud_code/jobs", parts[len(parts)-1]
+	}
+	return strings.Join(parts, "/"), ""
+}
+
+func requestMethod(req *http.Request) string {
+	if req.Method == "" {
+		return "GET"
+	}
+	return req.Method
+}
@@ -0,0 +1,72 @@
+package parse_test
+
+import (
+	"fmt"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+type recordMetrics []string
+
+func (m *recordMetrics) ObserveRequest(endpoint, method string, status int, d time.Duration) {
+	*m = append(*m, fmt.Sprintf("%s %s %d", method, endpoint, status))
+}
+
+func (m *recordMetrics) IncRetry(endpoint, method string) {
+	*m = append(*m, fmt.Sprintf("retry %s %s", method, endpoint))
+}
+
+func TestMetrics(t *testing.T) {
+	t.Parallel()
+	var m recordMetrics
+	calls := 0
+	c := &parse.Client{
+		BaseURL: &url.URL{Scheme: "https", Host: "example.com", Path: "/parse/"},
+		Metrics: &m,
+		Retry:   &parse.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond},
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			if calls == 1 {
+				return jsonResponse(t, http.StatusServiceUnavailable, map[string]string{}), nil
+			}
+			return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+		}),
+	}
+	paths := []string{
+		"classes/Yak/a1",
+		"classes/Yak",
+		"users/u1",
+		"users/me",
+		"login",
+		"functions/hello",
+		"cloud_code/jobs",
+		"cloud_code/jobs/s1",
+		"https://example.com/parse/files/a.txt",
+		"https://other.example.com/1/batch",
+	}
+	for _, p := range paths {
+		u, err := url.Parse(p)
+		ensure.Nil(t, err)
+		_, err = c.Get(u, nil)
+		ensure.Nil(t, err)
+	}
+	ensure.DeepEqual(t, []string(m), []string{
+		"GET classes/Yak 503",
+		"retry GET classes/Yak",
+		"GET classes/Yak 200",
+		"GET classes/Yak 200",
+		"GET users 200",
+		"GET users 200",
+		"GET login 200",
+		"GET functions/hello 200",
+		"GET cloud_code/jobs 200",
+		"GET cloud_code/jobs 200",
+		"GET files 200",
+		"GET 1/batch 200",
+	})
+}
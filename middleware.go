@@ -0,0 +1,22 @@
+package parse
+
+import "net/http"
+
+// Middleware wraps the RoundTripper performing the requests of a Client, to
+// add behavior such as extra headers, metrics or logging to every request:
+//
+//	func tracing(next http.RoundTripper) http.RoundTripper {
+//		return parse.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+//			r.Header.Set("X-Trace-Id", traceID(r.Context()))
+//			return next.RoundTrip(r)
+//		})
+//	}
+type Middleware func(next http.RoundTripper) http.RoundTripper
+
+// RoundTripperFunc is an http.RoundTripper implemented by a function.
+type RoundTripperFunc func(*http.Request) (*http.Response, error)
+
+// RoundTrip calls f(r).
+func (f RoundTripperFunc) RoundTrip(r *http.Request) (*http.Response, error) {
+	return f(r)
+}
@@ -0,0 +1,42 @@
+package parse_test
+
+import (
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestMiddleware(t *testing.T) {
+	t.Parallel()
+	var calls []string
+	named := func(name string) parse.Middleware {
+		return func(next http.RoundTripper) http.RoundTripper {
+			return parse.RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+				calls = append(calls, name)
+				r.Header.Add("X-Middleware", name)
+				return next.RoundTrip(r)
+			})
+		}
+	}
+	c := &parse.Client{
+		Credentials: defaultRestAPIKey,
+		Middleware:  []parse.Middleware{named("outer"), named("inner")},
+		Retry:       &parse.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond},
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			calls = append(calls, "transport")
+			ensure.DeepEqual(t, r.Header.Get("X-Parse-REST-API-Key"), defaultRestAPIKey.RestAPIKey)
+			ensure.DeepEqual(t, r.Header["X-Middleware"][:2], []string{"outer", "inner"})
+			if len(calls) == 3 {
+				return jsonResponse(t, http.StatusServiceUnavailable, map[string]string{}), nil
+			}
+			return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+		}),
+	}
+	_, err := c.Get(&url.URL{Path: "classes/Yak/a"}, nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, calls, []string{"outer", "inner", "transport", "outer", "inner", "transport"})
+}
@@ -0,0 +1,407 @@
+package parse
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+)
+
+var errEmptyMigrationClients = errors.New("parse: migration needs a Source and a Destination")
+
+// Migrator copies classes from one application to another, for example when
+// moving between servers. Both clients usually need the Master Key.
+//
+// Objects keep their objectId when the destination allows custom object IDs.
+// Otherwise they get a new one, and Pointer fields of the copied classes that
+// refer to copied objects are remapped to the new objectIds once all classes
+// are copied. Pointers inside Arrays and Objects are not remapped. Files
+// referenced by File fields are downloaded from the source and uploaded to the
+// destination.
+type Migrator struct {
+	Source      *Client
+	Destination *Client
+
+	// Classes to copy.
+	Classes []string
+
+	// SkipFiles leaves File fields referring to the files of the source
+	// instead of copying the files.
+	SkipFiles bool
+
+	// Progress is called after every page of objects copied, and once more
+	// when a class is done.
+	Progress func(MigrationProgress)
+
+	// Concurrency is the number of batch requests in flight while copying a
+	// page of objects. Defaults to 1.
+	Concurrency int
+
+	// Reporter optionally receives progress reports like Progress, with the
+	// rate and the time left. The objects of each class are counted first,
+	// so that the reports have a Total.
+	Reporter ProgressReporter
+}
+
+// MigrationProgress reports how far the copy of a class is.
+type MigrationProgress struct {
+	ClassName string
+	Copied    int
+	Failed    int
+	Done      bool
+}
+
+// ClassMigration describes the copy of a class.
+type ClassMigration struct {
+	ClassName string
+
+	// Copied is the number of objects created in the destination.
+	Copied int
+
+	// IDs maps the source objectId to the destination objectId for objects
+	// that could not keep their objectId.
+	IDs map[string]string
+
+	// Errors has the objects that could not be copied or remapped. The Line
+	// is the position of the object in the source class starting at 1, or 0
+	// when remapping its pointers failed.
+	Errors []*ImportError
+}
+
+// MigrationResult describes a completed or interrupted migration.
+type MigrationResult struct {
+	Classes []*ClassMigration
+}
+
+type migration struct {
+	*Migrator
+
+	// mu guards keepIDs and class while batches are in flight.
+	mu      sync.Mutex
+	keepIDs bool
+	files   map[string]json.RawMessage
+	class   *ClassMigration
+	report  *progress
+	page    []json.RawMessage
+	line    int
+}
+
+// Migrate copies the classes. Failures of individual objects are reported in
+// the result, while the returned error reports failures that stopped the
+// migration, in which case the result describes the progress until then.
+func (m *Migrator) Migrate() (*MigrationResult, error) {
+	if m.Source == nil || m.Destination == nil {
+		return nil, errEmptyMigrationClients
+	}
+	mg := &migration{
+		Migrator: m,
+		keepIDs:  true,
+		files:    make(map[string]json.RawMessage),
+	}
+	var res MigrationResult
+	for _, className := range m.Classes {
+		mg.class = &ClassMigration{ClassName: className}
+		mg.line = 0
+		res.Classes = append(res.Classes, mg.class)
+		if err := mg.copyClass(); err != nil {
+			return &res, err
+		}
+	}
+
+	ids := make(map[string]map[string]string)
+	for _, c := range res.Classes {
+		if len(c.IDs) != 0 {
+			ids[c.ClassName] = c.IDs
+		}
+	}
+	if len(ids) == 0 {
+		return &res, nil
+	}
+	for _, c := range res.Classes {
+		if err := mg.remap(c, ids); err != nil {
+			return &res, err
+		}
+	}
+	return &res, nil
+}
+
+func (mg *migration) progress(done bool) {
+	failed := len(mg.class.Errors)
+	mg.report.report(mg.class.Copied+failed, failed, done)
+	if mg.Progress != nil {
+		mg.Progress(MigrationProgress{
+			ClassName: mg.class.ClassName,
+			Copied:    mg.class.Copied,
+			Failed:    len(mg.class.Errors),
+			Done:      done,
+		})
+	}
+}
+
+func (mg *migration) fail(line int, id string, err error) {
+	mg.class.Errors = append(mg.class.Errors, &ImportError{Line: line, ObjectID: id, Err: err})
+}
+
+func (mg *migration) copyClass() error {
+	mg.report = nil
+	if mg.Reporter != nil {
+		total, err := (&ObjectClient{Client: mg.Source, Path: "classes/" + mg.class.ClassName}).Count(nil)
+		if err != nil {
+			return err
+		}
+		mg.report = newProgress(mg.Reporter, mg.Source, mg.class.ClassName, total)
+	}
+	e := Exporter{Client: mg.Source, ClassName: mg.class.ClassName}
+	_, err := e.each("", mg.copyPage, func(raw json.RawMessage) error {
+		mg.page = append(mg.page, raw)
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+	mg.progress(true)
+	return nil
+}
+
+func (mg *migration) copyPage() error {
+	if len(mg.page) == 0 {
+		return nil
+	}
+	recs := make([]importRecord, 0, len(mg.page))
+	for _, raw := range mg.page {
+		mg.line++
+		rec := importRecord{line: mg.line}
+		if err := json.Unmarshal(raw, &rec.body); err != nil {
+			return err
+		}
+		if err := rec.init(); err != nil {
+			mg.class.Errors = append(mg.class.Errors, err.(*ImportError))
+			continue
+		}
+		if err := mg.copyFiles(rec.body); err != nil {
+			mg.fail(rec.line, rec.id, err)
+			continue
+		}
+		recs = append(recs, rec)
+	}
+	mg.page = nil
+
+	pool := Pool{Size: mg.Concurrency}
+	for len(recs) != 0 {
+		n := len(recs)
+		if n > MaxBatchSize {
+			n = MaxBatchSize
+		}
+		batch := recs[:n]
+		pool.Go(func() error { return mg.create(batch) })
+		recs = recs[n:]
+	}
+	if err := pool.Wait(); err != nil {
+		return err
+	}
+	mg.progress(false)
+	return nil
+}
+
+func (mg *migration) create(recs []importRecord) error {
+	mg.mu.Lock()
+	keepIDs := mg.keepIDs
+	mg.mu.Unlock()
+	path := "classes/" + mg.class.ClassName
+	reqs := make([]BatchRequest, len(recs))
+	for n, rec := range recs {
+		body := make(map[string]json.RawMessage, len(rec.body))
+		for k, v := range rec.body {
+			if k != "objectId" || keepIDs {
+				body[k] = v
+			}
+		}
+		reqs[n] = BatchRequest{Method: "POST", Path: path, Body: body}
+	}
+	res, err := mg.Destination.Batch(reqs)
+	if err != nil {
+		return err
+	}
+
+	retry, err := mg.record(recs, res, keepIDs)
+	if err != nil || len(retry) == 0 {
+		return err
+	}
+	return mg.create(retry)
+}
+
+// record adds the outcome of a batch to the class, and returns the objects to
+// send again without their objectId.
+func (mg *migration) record(recs []importRecord, res []BatchResponse, keepIDs bool) ([]importRecord, error) {
+	mg.mu.Lock()
+	defer mg.mu.Unlock()
+	var retry []importRecord
+	for n, r := range res {
+		rec := recs[n]
+		if r.Error != nil {
+			// The first rejected objectId means the destination wants to
+			// pick them, so the remaining objects are sent without one.
+			if keepIDs && r.Error.Code == CodeInvalidKeyName {
+				retry = append(retry, rec)
+				continue
+			}
+			mg.fail(rec.line, rec.id, r.Error)
+			continue
+		}
+		var created CreateResponse
+		if err := json.Unmarshal(r.Success, &created); err != nil {
+			return nil, err
+		}
+		mg.class.Copied++
+		if created.ID != "" && created.ID != rec.id {
+			if mg.class.IDs == nil {
+				mg.class.IDs = make(map[string]string)
+			}
+			mg.class.IDs[rec.id] = created.ID
+		}
+	}
+	if len(retry) != 0 {
+		mg.keepIDs = false
+	}
+	return retry, nil
+}
+
+func (mg *migration) copyFiles(body map[string]json.RawMessage) error {
+	if mg.SkipFiles {
+		return nil
+	}
+	for k, raw := range body {
+		var f fileJSON
+		if len(raw) == 0 || raw[0] != '{' || json.Unmarshal(raw, &f) != nil || f.Type != "File" {
+			continue
+		}
+		if copied, ok := mg.files[f.Name]; ok {
+			body[k] = copied
+			continue
+		}
+		copied, err := mg.copyFile(f)
+		if err != nil {
+			return err
+		}
+		b, err := json.Marshal(copied)
+		if err != nil {
+			return err
+		}
+		mg.files[f.Name] = b
+		body[k] = b
+	}
+	return nil
+}
+
+func (mg *migration) copyFile(f fileJSON) (*File, error) {
+	hc := http.Client{Transport: mg.Source.transport()}
+	res, err := hc.Get(f.URL)
+	if err != nil {
+		return nil, err
+	}
+	defer res.Body.Close()
+	data, err := ioutil.ReadAll(res.Body)
+	if err != nil {
+		return nil, err
+	}
+	if res.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("parse: cannot download file %s: %s", f.Name, res.Status)
+	}
+
+	req := http.Request{
+		Method:        "POST",
+		URL:           &url.URL{Path: "files/" + f.Name},
+		Header:        make(http.Header),
+		Body:          ioutil.NopCloser(bytes.NewReader(data)),
+		ContentLength: int64(len(data)),
+	}
+	if ct := res.Header.Get("Content-Type"); ct != "" {
+		req.Header.Set("Content-Type", ct)
+	}
+	up, err := mg.Destination.RoundTrip(&req)
+	if err != nil {
+		return nil, err
+	}
+	defer up.Body.Close()
+	var uploaded fileJSON
+	if err := json.NewDecoder(up.Body).Decode(&uploaded); err != nil {
+		return nil, err
+	}
+	return &File{Name: uploaded.Name, URL: uploaded.URL}, nil
+}
+
+// remap updates the Pointer fields of a copied class that refer to objects
+// which got a new objectId.
+func (mg *migration) remap(c *ClassMigration, ids map[string]map[string]string) error {
+	s, err := (&SchemaClient{Client: mg.Destination}).Get(c.ClassName)
+	if err != nil {
+		return err
+	}
+	fields := make(map[string]map[string]string)
+	var keys []string
+	for name, f := range s.Fields {
+		if f.Type == FieldPointer && ids[f.TargetClass] != nil {
+			fields[name] = ids[f.TargetClass]
+			keys = append(keys, name)
+		}
+	}
+	if len(keys) == 0 {
+		return nil
+	}
+
+	var reqs []BatchRequest
+	var reqIDs []string
+	update := func() error {
+		if len(reqs) == 0 {
+			return nil
+		}
+		res, err := mg.Destination.Batch(reqs)
+		if err != nil {
+			return err
+		}
+		for n, r := range res {
+			if r.Error != nil {
+				c.Errors = append(c.Errors, &ImportError{ObjectID: reqIDs[n], Err: r.Error})
+			}
+		}
+		reqs, reqIDs = reqs[:0], reqIDs[:0]
+		return nil
+	}
+	e := Exporter{
+		Client:    mg.Destination,
+		ClassName: c.ClassName,
+		Keys:      keys,
+		BatchSize: MaxBatchSize,
+	}
+	_, err = e.each("", update, func(raw json.RawMessage) error {
+		var o map[string]json.RawMessage
+		if err := json.Unmarshal(raw, &o); err != nil {
+			return err
+		}
+		var id string
+		if err := json.Unmarshal(o["objectId"], &id); err != nil {
+			return err
+		}
+		body := make(map[string]Pointer)
+		for name, m := range fields {
+			var p Pointer
+			if raw, ok := o[name]; !ok || json.Unmarshal(raw, &p) != nil {
+				continue
+			}
+			if newID, ok := m[p.ID]; ok {
+				p.ID = newID
+				body[name] = p
+			}
+		}
+		if len(body) != 0 {
+			reqs = append(reqs, BatchRequest{Method: "PUT", Path: "classes/" + c.ClassName + "/" + id, Body: body})
+			reqIDs = append(reqIDs, id)
+		}
+		return nil
+	})
+	return err
+}
@@ -0,0 +1,241 @@
+package parse_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+	"github.com/facebookgo/parse/parsetest"
+)
+
+func TestMigrateKeepsIDs(t *testing.T) {
+	t.Parallel()
+	source := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			switch r.URL.String() {
+			case "https://files.example.com/tfss-al.jpg":
+				return &http.Response{
+					StatusCode: http.StatusOK,
+					Header:     http.Header{"Content-Type": {"image/jpeg"}},
+					Body:       ioutil.NopCloser(bytes.NewReader([]byte("jpeg"))),
+				}, nil
+			}
+			ensure.DeepEqual(t, r.URL.Path, "/1/classes/Yak")
+			if res := endOfPages(t, r); res != nil {
+				return res, nil
+			}
+			return jsonResponse(t, http.StatusOK, map[string]interface{}{
+				"results": []map[string]interface{}{
+					{
+						"objectId":  "a",
+						"createdAt": "2015-03-07T21:52:12.000Z",
+						"name":      "Al",
+						"photo":     map[string]string{"__type": "File", "name": "tfss-al.jpg", "url": "https://files.example.com/tfss-al.jpg"},
+					},
+					{
+						"objectId": "b",
+						"photo":    map[string]string{"__type": "File", "name": "tfss-al.jpg", "url": "https://files.example.com/tfss-al.jpg"},
+					},
+				},
+			}), nil
+		}),
+	}
+	uploads := 0
+	destination := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			switch r.URL.Path {
+			case "/1/files/tfss-al.jpg":
+				uploads++
+				ensure.DeepEqual(t, r.Header.Get("Content-Type"), "image/jpeg")
+				b, err := ioutil.ReadAll(r.Body)
+				ensure.Nil(t, err)
+				ensure.DeepEqual(t, string(b), "jpeg")
+				return jsonResponse(t, http.StatusCreated, map[string]string{
+					"name": "tfss-new-al.jpg",
+					"url":  "https://dest.example.com/tfss-new-al.jpg",
+				}), nil
+			case "/1/batch":
+				var body batchBody
+				decodeBody(t, r, &body)
+				photo := map[string]interface{}{
+					"__type": "File",
+					"name":   "tfss-new-al.jpg",
+					"url":    "https://dest.example.com/tfss-new-al.jpg",
+				}
+				ensure.DeepEqual(t, len(body.Requests), 2)
+				ensure.DeepEqual(t, body.Requests[0].Method, "POST")
+				ensure.DeepEqual(t, body.Requests[0].Path, "/1/classes/Yak")
+				ensure.DeepEqual(t, body.Requests[0].Body, map[string]interface{}{
+					"objectId": "a",
+					"name":     "Al",
+					"photo":    photo,
+				})
+				ensure.DeepEqual(t, body.Requests[1].Body, map[string]interface{}{
+					"objectId": "b",
+					"photo":    photo,
+				})
+				return jsonResponse(t, http.StatusOK, []interface{}{
+					map[string]interface{}{"success": map[string]string{"objectId": "a"}},
+					map[string]interface{}{"error": map[string]interface{}{"code": 137, "error": "duplicate value"}},
+				}), nil
+			}
+			panic("unexpected request " + r.URL.Path)
+		}),
+	}
+	var progress []parse.MigrationProgress
+	m := parse.Migrator{
+		Source:      source,
+		Destination: destination,
+		Classes:     []string{"Yak"},
+		Progress:    func(p parse.MigrationProgress) { progress = append(progress, p) },
+	}
+	res, err := m.Migrate()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, uploads, 1)
+	ensure.DeepEqual(t, len(res.Classes), 1)
+	ensure.DeepEqual(t, res.Classes[0].ClassName, "Yak")
+	ensure.DeepEqual(t, res.Classes[0].Copied, 1)
+	ensure.True(t, res.Classes[0].IDs == nil)
+	ensure.DeepEqual(t, len(res.Classes[0].Errors), 1)
+	ensure.DeepEqual(t, res.Classes[0].Errors[0].Line, 2)
+	ensure.DeepEqual(t, res.Classes[0].Errors[0].ObjectID, "b")
+	ensure.DeepEqual(t, progress, []parse.MigrationProgress{
+		{ClassName: "Yak", Copied: 1, Failed: 1},
+		{ClassName: "Yak", Copied: 1, Failed: 1, Done: true},
+	})
+}
+
+func TestMigrateRemapsPointers(t *testing.T) {
+	t.Parallel()
+	source := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			if res := endOfPages(t, r); res != nil {
+				return res, nil
+			}
+			switch r.URL.Path {
+			case "/1/classes/Farmer":
+				return jsonResponse(t, http.StatusOK, map[string]interface{}{
+					"results": []map[string]interface{}{{"objectId": "f"}},
+				}), nil
+			case "/1/classes/Yak":
+				return jsonResponse(t, http.StatusOK, map[string]interface{}{
+					"results": []map[string]interface{}{{
+						"objectId": "y",
+						"owner":    map[string]string{"__type": "Pointer", "className": "Farmer", "objectId": "f"},
+					}},
+				}), nil
+			}
+			panic("unexpected request " + r.URL.Path)
+		}),
+	}
+	batches := 0
+	destination := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			switch r.URL.Path {
+			case "/1/batch":
+				var body batchBody
+				decodeBody(t, r, &body)
+				batches++
+				switch batches {
+				case 1:
+					ensure.DeepEqual(t, body.Requests[0].Body, map[string]interface{}{"objectId": "f"})
+					return jsonResponse(t, http.StatusOK, []interface{}{
+						map[string]interface{}{"error": map[string]interface{}{"code": 105, "error": "objectId is an invalid field name"}},
+					}), nil
+				case 2:
+					ensure.DeepEqual(t, body.Requests[0].Body, map[string]interface{}{})
+					return jsonResponse(t, http.StatusOK, []interface{}{
+						map[string]interface{}{"success": map[string]string{"objectId": "f2"}},
+					}), nil
+				case 3:
+					ensure.DeepEqual(t, body.Requests[0].Body, map[string]interface{}{
+						"owner": map[string]interface{}{"__type": "Pointer", "className": "Farmer", "objectId": "f"},
+					})
+					return jsonResponse(t, http.StatusOK, []interface{}{
+						map[string]interface{}{"success": map[string]string{"objectId": "y2"}},
+					}), nil
+				case 4:
+					ensure.DeepEqual(t, body.Requests[0].Method, "PUT")
+					ensure.DeepEqual(t, body.Requests[0].Path, "/1/classes/Yak/y2")
+					ensure.DeepEqual(t, body.Requests[0].Body, map[string]interface{}{
+						"owner": map[string]interface{}{"__type": "Pointer", "className": "Farmer", "objectId": "f2"},
+					})
+					return jsonResponse(t, http.StatusOK, []interface{}{
+						map[string]interface{}{"success": map[string]string{}},
+					}), nil
+				}
+			case "/1/schemas/Farmer":
+				return jsonResponse(t, http.StatusOK, map[string]interface{}{"className": "Farmer"}), nil
+			case "/1/schemas/Yak":
+				return jsonResponse(t, http.StatusOK, map[string]interface{}{
+					"className": "Yak",
+					"fields": map[string]interface{}{
+						"owner": map[string]string{"type": "Pointer", "targetClass": "Farmer"},
+					},
+				}), nil
+			case "/1/classes/Yak":
+				ensure.DeepEqual(t, r.URL.Query().Get("keys"), "objectId,owner")
+				if res := endOfPages(t, r); res != nil {
+					return res, nil
+				}
+				return jsonResponse(t, http.StatusOK, map[string]interface{}{
+					"results": []map[string]interface{}{{
+						"objectId": "y2",
+						"owner":    map[string]string{"__type": "Pointer", "className": "Farmer", "objectId": "f"},
+					}},
+				}), nil
+			}
+			panic("unexpected request " + r.URL.Path)
+		}),
+	}
+	m := parse.Migrator{
+		Source:      source,
+		Destination: destination,
+		Classes:     []string{"Farmer", "Yak"},
+	}
+	res, err := m.Migrate()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, batches, 4)
+	ensure.DeepEqual(t, res.Classes[0].IDs, map[string]string{"f": "f2"})
+	ensure.DeepEqual(t, res.Classes[1].IDs, map[string]string{"y": "y2"})
+	ensure.DeepEqual(t, len(res.Classes[1].Errors), 0)
+}
+
+func TestMigrateClients(t *testing.T) {
+	t.Parallel()
+	_, err := (&parse.Migrator{}).Migrate()
+	ensure.Err(t, err, regexp.MustCompile("needs a Source and a Destination"))
+}
+
+func TestMigrateConcurrency(t *testing.T) {
+	t.Parallel()
+	source := parsetest.NewServer()
+	defer source.Close()
+	destination := parsetest.NewServer()
+	defer destination.Close()
+	yaks := make([]map[string]int, 3*parse.MaxBatchSize+1)
+	for n := range yaks {
+		yaks[n] = map[string]int{"n": n}
+	}
+	_, err := (&parse.ObjectClient{Client: source.Client(), Path: "classes/Yak"}).PostAll(yaks)
+	ensure.Nil(t, err)
+
+	m := parse.Migrator{
+		Source:      source.Client(),
+		Destination: destination.Client(),
+		Classes:     []string{"Yak"},
+		Concurrency: 3,
+	}
+	res, err := m.Migrate()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res.Classes[0].Copied, len(yaks))
+	ensure.DeepEqual(t, len(res.Classes[0].Errors), 0)
+	copied := destination.Objects("Yak")
+	for id, o := range source.Objects("Yak") {
+		ensure.DeepEqual(t, copied[id]["n"], o["n"])
+	}
+}
@@ -0,0 +1,120 @@
+package parse
+
+import (
+	"bytes"
+	"encoding/json"
+)
+
+var null = []byte("null")
+
+// NullString is a string that can be null.
+//
+// The Null types tell an explicit null or zero value apart from a missing
+// one, which omitempty cannot do. Used as pointer fields with omitempty, a nil
+// pointer leaves the field out, a pointer to an invalid value sends null, and
+// a pointer to a valid value sends the value, even when it is the zero value:
+//
+//	type YakUpdate struct {
+//	  Name *parse.NullString `json:"name,omitempty"`
+//	  Tame *parse.NullBool   `json:"tame,omitempty"`
+//	}
+//
+//	// Clears the name and sets tame to false.
+//	u := YakUpdate{Name: &parse.NullString{}, Tame: &parse.NullBool{Valid: true}}
+//
+// Decoding null into a pointer field leaves it nil, as for a missing field,
+// while decoding it into a value gives an invalid value.
+type NullString struct {
+	Value string
+	Valid bool
+}
+
+// MarshalJSON encodes the string, or null when it isn't valid.
+func (n NullString) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return null, nil
+	}
+	return json.Marshal(n.Value)
+}
+
+// UnmarshalJSON decodes the string or null.
+func (n *NullString) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(bytes.TrimSpace(b), null) {
+		*n = NullString{}
+		return nil
+	}
+	*n = NullString{Valid: true}
+	return json.Unmarshal(b, &n.Value)
+}
+
+// NullBool is a bool that can be null.
+type NullBool struct {
+	Value bool
+	Valid bool
+}
+
+// MarshalJSON encodes the bool, or null when it isn't valid.
+func (n NullBool) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return null, nil
+	}
+	return json.Marshal(n.Value)
+}
+
+// UnmarshalJSON decodes the bool or null.
+func (n *NullBool) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(bytes.TrimSpace(b), null) {
+		*n = NullBool{}
+		return nil
+	}
+	*n = NullBool{Valid: true}
+	return json.Unmarshal(b, &n.Value)
+}
+
+// NullInt is an integer that can be null.
+type NullInt struct {
+	Value int64
+	Valid bool
+}
+
+// MarshalJSON encodes the integer, or null when it isn't valid.
+func (n NullInt) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return null, nil
+	}
+	return json.Marshal(n.Value)
+}
+
+// UnmarshalJSON decodes the integer or null.
+func (n *NullInt) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(bytes.TrimSpace(b), null) {
+		*n = NullInt{}
+		return nil
+	}
+	*n = NullInt{Valid: true}
+	return json.Unmarshal(b, &n.Value)
+}
+
+// NullFloat is a number that can be null.
+type NullFloat struct {
+	Value float64
+	Valid bool
+}
+
+// MarshalJSON encodes the number, or null when it isn't valid.
+func (n NullFloat) MarshalJSON() ([]byte, error) {
+	if !n.Valid {
+		return null, nil
+	}
+	return json.Marshal(n.Value)
+}
+
+// UnmarshalJSON decodes the number or null.
+func (n *NullFloat) UnmarshalJSON(b []byte) error {
+	if bytes.Equal(bytes.TrimSpace(b), null) {
+		*n = NullFloat{}
+		return nil
+	}
+	*n = NullFloat{Valid: true}
+	return json.Unmarshal(b, &n.Value)
+}
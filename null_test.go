@@ -0,0 +1,63 @@
+package parse_test
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+type nullYak struct {
+	Name  *parse.NullString `json:"name,omitempty"`
+	Tame  *parse.NullBool   `json:"tame,omitempty"`
+	Age   *parse.NullInt    `json:"age,omitempty"`
+	Score *parse.NullFloat  `json:"score,omitempty"`
+}
+
+func TestNullMarshal(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		v    nullYak
+		json string
+	}{
+		{nullYak{}, `{}`},
+		{nullYak{Name: &parse.NullString{}, Tame: &parse.NullBool{}}, `{"name":null,"tame":null}`},
+		{
+			nullYak{
+				Name:  &parse.NullString{Valid: true},
+				Tame:  &parse.NullBool{Valid: true},
+				Age:   &parse.NullInt{Valid: true},
+				Score: &parse.NullFloat{Valid: true},
+			},
+			`{"name":"","tame":false,"age":0,"score":0}`,
+		},
+		{
+			nullYak{Name: &parse.NullString{Value: "Al", Valid: true}, Age: &parse.NullInt{Value: 3, Valid: true}},
+			`{"name":"Al","age":3}`,
+		},
+	}
+	for _, c := range cases {
+		b, err := json.Marshal(c.v)
+		ensure.Nil(t, err)
+		ensure.DeepEqual(t, string(b), c.json)
+	}
+}
+
+func TestNullUnmarshal(t *testing.T) {
+	t.Parallel()
+	var y nullYak
+	ensure.Nil(t, json.Unmarshal([]byte(`{"name":null,"tame":false,"age":3,"score":1.5}`), &y))
+	ensure.DeepEqual(t, y, nullYak{
+		Tame:  &parse.NullBool{Valid: true},
+		Age:   &parse.NullInt{Value: 3, Valid: true},
+		Score: &parse.NullFloat{Value: 1.5, Valid: true},
+	})
+
+	// encoding/json leaves pointers nil for null, decode null into a value
+	// to get an invalid one.
+	n := parse.NullString{Value: "x", Valid: true}
+	ensure.Nil(t, json.Unmarshal([]byte(`null`), &n))
+	ensure.DeepEqual(t, n, parse.NullString{})
+	ensure.NotNil(t, json.Unmarshal([]byte(`1`), &n))
+}
@@ -0,0 +1,236 @@
+package parse
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// CreateResponse is returned by the API when an object is created.
+type CreateResponse struct {
+	ID        string    `json:"objectId"`
+	CreatedAt time.Time `json:"createdAt"`
+}
+
+// UpdateResponse is returned by the API when an object is updated.
+type UpdateResponse struct {
+	UpdatedAt time.Time `json:"updatedAt"`
+}
+
+// ObjectClient provides access to the objects rooted at a path such as
+// "classes/GameScore/" or "installations/".
+type ObjectClient struct {
+	Client *Client
+
+	// Path to the objects, relative to the Client BaseURL. A trailing slash
+	// will be added if necessary.
+	Path string
+
+	// Cache optionally keeps the objects and query results fetched, such as
+	// a MemoryCache. Writes through the ObjectClient drop the cached objects
+	// and queries of its Path, but writes made elsewhere are only seen once
+	// the cached responses expire. Responses served from the Cache are nil.
+	Cache Cache
+
+	// CacheTTL is how long responses are cached. Defaults to a minute.
+	CacheTTL time.Duration
+
+	// Concurrency is the number of batch requests PostAll, PutAll and
+	// DeleteAll have in flight. Defaults to 1.
+	Concurrency int
+
+	// SoftDelete makes Delete and DeleteAll set the DeletedField to the
+	// current time instead of deleting the objects, and makes Query, Count
+	// and Each leave out the objects that have it, unless their where clause
+	// constrains the field itself. Get still returns deleted objects. Use
+	// Restore to undo a soft delete, and HardDelete to delete an object.
+	SoftDelete bool
+
+	// DeletedField is the Date field marking soft deleted objects. Defaults
+	// to "deletedAt".
+	DeletedField string
+}
+
+// Class returns an ObjectClient for the objects of the class. The special
+// classes, such as _User and _Installation, use their own endpoints.
+func (c *Client) Class(className string) *ObjectClient {
+	for endpoint, class := range endpointClasses {
+		if class == className {
+			return &ObjectClient{Client: c, Path: endpoint + "/"}
+		}
+	}
+	return &ObjectClient{Client: c, Path: "classes/" + className + "/"}
+}
+
+// Users returns an ObjectClient for the users endpoint.
+func (c *Client) Users() *ObjectClient {
+	return &ObjectClient{Client: c, Path: "users/"}
+}
+
+// Roles returns an ObjectClient for the roles endpoint.
+func (c *Client) Roles() *ObjectClient {
+	return &ObjectClient{Client: c, Path: "roles/"}
+}
+
+// Installations returns an ObjectClient for the installations endpoint. See
+// also InstallationsClient.
+func (c *Client) Installations() *ObjectClient {
+	return &ObjectClient{Client: c, Path: installationsPath}
+}
+
+// Sessions returns an ObjectClient for the sessions endpoint, which usually
+// needs the Master Key or the Session Token of the user.
+func (c *Client) Sessions() *ObjectClient {
+	return &ObjectClient{Client: c, Path: "sessions/"}
+}
+
+func (o *ObjectClient) objectURL(id string) *url.URL {
+	p := o.Path
+	if !strings.HasSuffix(p, "/") {
+		p += "/"
+	}
+	return &url.URL{Path: p + id}
+}
+
+// Get fetches the object with the given ID and unmarshals it into result.
+func (o *ObjectClient) Get(id string, result interface{}) (*http.Response, error) {
+	return o.GetCtx(context.Background(), id, result)
+}
+
+// GetCtx is Get with a context used for the request.
+func (o *ObjectClient) GetCtx(ctx context.Context, id string, result interface{}) (*http.Response, error) {
+	return o.get(ctx, o.objectURL(id), result)
+}
+
+// GetOptions are the query parameters of fetching a single object.
+type GetOptions struct {
+	// Keys restricts the returned fields.
+	Keys []string
+
+	// ExcludeKeys leaves out fields. It needs Parse Server 4.5 or later.
+	ExcludeKeys []string
+
+	// Include has the Pointer fields to return the objects of.
+	Include []string
+}
+
+// GetWithOptions is Get with the parameters of the options. Nil options are
+// the same as Get.
+func (o *ObjectClient) GetWithOptions(id string, opt *GetOptions, result interface{}) (*http.Response, error) {
+	return o.GetWithOptionsCtx(context.Background(), id, opt, result)
+}
+
+// GetWithOptionsCtx is GetWithOptions with a context used for the request.
+func (o *ObjectClient) GetWithOptionsCtx(ctx context.Context, id string, opt *GetOptions, result interface{}) (*http.Response, error) {
+	u := o.objectURL(id)
+	if opt != nil {
+		p := Params{Keys: opt.Keys, ExcludeKeys: opt.ExcludeKeys, Include: opt.Include}
+		v, err := p.Values()
+		if err != nil {
+			return nil, err
+		}
+		u.RawQuery = v.Encode()
+	}
+	return o.get(ctx, u, result)
+}
+
+// Post creates a new object from v.
+func (o *ObjectClient) Post(v interface{}) (*CreateResponse, error) {
+	return o.PostCtx(context.Background(), v)
+}
+
+// PostCtx is Post with a context used for the request.
+func (o *ObjectClient) PostCtx(ctx context.Context, v interface{}) (*CreateResponse, error) {
+	if err := validateBody(v); err != nil {
+		return nil, err
+	}
+	var res CreateResponse
+	defer o.invalidate()
+	if _, err := o.Client.PostCtx(ctx, o.objectURL(""), v, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Put updates the object with the given ID using the fields in v.
+func (o *ObjectClient) Put(id string, v interface{}) (*UpdateResponse, error) {
+	return o.PutCtx(context.Background(), id, v)
+}
+
+// PutCtx is Put with a context used for the request.
+func (o *ObjectClient) PutCtx(ctx context.Context, id string, v interface{}) (*UpdateResponse, error) {
+	if err := validateBody(v); err != nil {
+		return nil, err
+	}
+	var res UpdateResponse
+	defer o.invalidate()
+	if _, err := o.Client.PutCtx(ctx, o.objectURL(id), v, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Delete deletes the object with the given ID. With SoftDelete the object is
+// marked deleted instead.
+func (o *ObjectClient) Delete(id string) (*http.Response, error) {
+	return o.DeleteCtx(context.Background(), id)
+}
+
+// DeleteCtx is Delete with a context used for the request.
+func (o *ObjectClient) DeleteCtx(ctx context.Context, id string) (*http.Response, error) {
+	if o.SoftDelete {
+		return o.softDelete(ctx, id)
+	}
+	defer o.invalidate()
+	return o.Client.DeleteCtx(ctx, o.objectURL(id), nil)
+}
+
+// Count returns the number of objects matching the where clause. A nil where
+// counts all objects.
+func (o *ObjectClient) Count(where interface{}) (int, error) {
+	return o.CountCtx(context.Background(), where)
+}
+
+// CountCtx is Count with a context used for the request.
+func (o *ObjectClient) CountCtx(ctx context.Context, where interface{}) (int, error) {
+	p := Params{Where: o.notDeleted(where), Count: true}
+	v, err := p.Values()
+	if err != nil {
+		return 0, err
+	}
+	v.Set("limit", "0")
+	u := o.objectURL("")
+	u.RawQuery = v.Encode()
+	var res struct {
+		Count int `json:"count"`
+	}
+	if _, err := o.get(ctx, u, &res); err != nil {
+		return 0, err
+	}
+	return res.Count, nil
+}
+
+// Query fetches the objects matching the parameters and unmarshals the
+// response into result, usually a struct with a Results slice. A nil p
+// fetches the first objects.
+func (o *ObjectClient) Query(p *Params, result interface{}) (*http.Response, error) {
+	return o.QueryCtx(context.Background(), p, result)
+}
+
+// QueryCtx is Query with a context used for the request.
+func (o *ObjectClient) QueryCtx(ctx context.Context, p *Params, result interface{}) (*http.Response, error) {
+	q := Params{}
+	if p != nil {
+		q = *p
+	}
+	q.Where = o.notDeleted(q.Where)
+	v, err := q.Values()
+	if err != nil {
+		return nil, err
+	}
+	u := o.objectURL("")
+	u.RawQuery = v.Encode()
+	return o.get(ctx, u, result)
+}
@@ -0,0 +1,170 @@
+package parse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+	"time"
+)
+
+var errNotSlice = errors.New("parse: objects must be a slice")
+
+// ObjectResult is the outcome of one object of PostAll, PutAll or DeleteAll.
+type ObjectResult struct {
+	// ID is the objectId of the object, as created or as given.
+	ID        string
+	CreatedAt time.Time
+	UpdatedAt time.Time
+
+	// Err is the reason the object failed, usually an *Error.
+	Err error
+}
+
+// PostAll creates the objects, which must be a slice, using as many batch
+// requests as needed. The results correspond to the objects by index. Each
+// object succeeds or fails on its own, so the returned error only reports
+// failure of a batch request as a whole, in which case the results of the
+// objects that weren't sent are nil.
+func (o *ObjectClient) PostAll(objects interface{}) ([]*ObjectResult, error) {
+	return o.PostAllCtx(context.Background(), objects)
+}
+
+// PostAllCtx is PostAll with a context used for the requests.
+func (o *ObjectClient) PostAllCtx(ctx context.Context, objects interface{}) ([]*ObjectResult, error) {
+	v := reflect.ValueOf(objects)
+	if v.Kind() != reflect.Slice {
+		return nil, errNotSlice
+	}
+	path := o.objectURL("").Path
+	reqs := make([]BatchRequest, v.Len())
+	for n := range reqs {
+		body := v.Index(n).Interface()
+		if err := validateBody(body); err != nil {
+			return nil, err
+		}
+		reqs[n] = BatchRequest{Method: "POST", Path: path, Body: body}
+	}
+	return o.batch(ctx, reqs, make([]*ObjectResult, len(reqs)))
+}
+
+// PutAll updates the objects, which must be a slice of values that encode to
+// JSON objects with an objectId, such as structs with an ID field. It uses as
+// many batch requests as needed and reports results like PostAll.
+func (o *ObjectClient) PutAll(objects interface{}) ([]*ObjectResult, error) {
+	return o.PutAllCtx(context.Background(), objects)
+}
+
+// PutAllCtx is PutAll with a context used for the requests.
+func (o *ObjectClient) PutAllCtx(ctx context.Context, objects interface{}) ([]*ObjectResult, error) {
+	v := reflect.ValueOf(objects)
+	if v.Kind() != reflect.Slice {
+		return nil, errNotSlice
+	}
+	reqs := make([]BatchRequest, v.Len())
+	results := make([]*ObjectResult, v.Len())
+	for n := range reqs {
+		b, err := json.Marshal(v.Index(n).Interface())
+		if err != nil {
+			return nil, err
+		}
+		var body map[string]json.RawMessage
+		if err := json.Unmarshal(b, &body); err != nil {
+			return nil, fmt.Errorf("parse: object %d is not a json object", n)
+		}
+		var id string
+		if raw, ok := body["objectId"]; ok {
+			json.Unmarshal(raw, &id)
+		}
+		if id == "" {
+			return nil, fmt.Errorf("parse: object %d has no objectId", n)
+		}
+		delete(body, "objectId")
+		if err := validateBody(body); err != nil {
+			return nil, err
+		}
+		reqs[n] = BatchRequest{Method: "PUT", Path: o.objectURL(id).Path, Body: body}
+		results[n] = &ObjectResult{ID: id}
+	}
+	return o.batch(ctx, reqs, results)
+}
+
+// DeleteAll deletes the objects with the IDs, using as many batch requests as
+// needed. It reports results like PostAll. With SoftDelete the objects are
+// marked deleted instead.
+func (o *ObjectClient) DeleteAll(ids []string) ([]*ObjectResult, error) {
+	return o.DeleteAllCtx(context.Background(), ids)
+}
+
+// DeleteAllCtx is DeleteAll with a context used for the requests.
+func (o *ObjectClient) DeleteAllCtx(ctx context.Context, ids []string) ([]*ObjectResult, error) {
+	reqs := make([]BatchRequest, len(ids))
+	results := make([]*ObjectResult, len(ids))
+	for n, id := range ids {
+		reqs[n] = BatchRequest{Method: "DELETE", Path: o.objectURL(id).Path}
+		if o.SoftDelete {
+			reqs[n].Method = "PUT"
+			reqs[n].Body = o.deletedBody()
+		}
+		results[n] = &ObjectResult{ID: id}
+	}
+	return o.batch(ctx, reqs, results)
+}
+
+// batch sends the requests in batches of up to MaxBatchSize and fills in the
+// results, which are created for requests without one.
+func (o *ObjectClient) batch(ctx context.Context, reqs []BatchRequest, results []*ObjectResult) ([]*ObjectResult, error) {
+	if len(reqs) != 0 {
+		defer o.invalidate()
+	}
+	sent := make([]*ObjectResult, len(results))
+	pool := Pool{Size: o.Concurrency}
+	for start := 0; start < len(reqs); start += MaxBatchSize {
+		end := start + MaxBatchSize
+		if end > len(reqs) {
+			end = len(reqs)
+		}
+		start := start
+		c := o.Client.WithContext(batchIdempotencyKey(ctx, start/MaxBatchSize))
+		pool.Go(func() error {
+			res, err := c.Batch(reqs[start:end])
+			if err != nil {
+				return err
+			}
+			return fillResults(res, results[start:end], sent[start:end])
+		})
+	}
+	return sent, pool.Wait()
+}
+
+// fillResults sets the results of a batch, creating those that are nil, and
+// marks them sent.
+func fillResults(res []BatchResponse, results, sent []*ObjectResult) error {
+	for i, r := range res {
+		if results[i] == nil {
+			results[i] = &ObjectResult{}
+		}
+		sent[i] = results[i]
+		if r.Error != nil {
+			results[i].Err = r.Error
+			continue
+		}
+		var success struct {
+			ID        string    `json:"objectId"`
+			CreatedAt time.Time `json:"createdAt"`
+			UpdatedAt time.Time `json:"updatedAt"`
+		}
+		if len(r.Success) != 0 {
+			if err := json.Unmarshal(r.Success, &success); err != nil {
+				return err
+			}
+		}
+		if success.ID != "" {
+			results[i].ID = success.ID
+		}
+		results[i].CreatedAt = success.CreatedAt
+		results[i].UpdatedAt = success.UpdatedAt
+	}
+	return nil
+}
@@ -0,0 +1,107 @@
+package parse_test
+
+import (
+	"fmt"
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+	"github.com/facebookgo/parse/parsetest"
+)
+
+type batchYak struct {
+	ID   string `json:"objectId,omitempty"`
+	Name string `json:"name"`
+}
+
+func TestObjectBatch(t *testing.T) {
+	t.Parallel()
+	s := parsetest.NewServer()
+	defer s.Close()
+	o := parse.ObjectClient{Client: s.Client(), Path: "classes/Yak"}
+
+	yaks := make([]batchYak, parse.MaxBatchSize+2)
+	for n := range yaks {
+		yaks[n].Name = fmt.Sprint("yak", n)
+	}
+	res, err := o.PostAll(yaks)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(res), len(yaks))
+	for n, r := range res {
+		ensure.Nil(t, r.Err)
+		ensure.True(t, r.ID != "")
+		ensure.False(t, r.CreatedAt.IsZero())
+		yaks[n].ID = r.ID
+		yaks[n].Name += " updated"
+	}
+	ensure.DeepEqual(t, len(s.Objects("Yak")), len(yaks))
+
+	updates := []batchYak{yaks[0], {ID: "missing", Name: "ghost"}}
+	res, err = o.PutAll(updates)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res[0].ID, yaks[0].ID)
+	ensure.False(t, res[0].UpdatedAt.IsZero())
+	ensure.True(t, parse.IsObjectNotFound(res[1].Err))
+	ensure.DeepEqual(t, s.Objects("Yak")[yaks[0].ID]["name"], "yak0 updated")
+
+	res, err = o.DeleteAll([]string{yaks[0].ID, yaks[1].ID})
+	ensure.Nil(t, err)
+	ensure.Nil(t, res[0].Err)
+	ensure.DeepEqual(t, res[1].ID, yaks[1].ID)
+	ensure.DeepEqual(t, len(s.Objects("Yak")), len(yaks)-2)
+}
+
+func TestObjectBatchInvalid(t *testing.T) {
+	t.Parallel()
+	o := parse.ObjectClient{Client: &parse.Client{}, Path: "classes/Yak"}
+	_, err := o.PostAll(batchYak{})
+	ensure.Err(t, err, regexp.MustCompile("must be a slice"))
+	_, err = o.PutAll([]batchYak{{Name: "a"}})
+	ensure.Err(t, err, regexp.MustCompile("object 0 has no objectId"))
+	_, err = o.PutAll([]int{1})
+	ensure.Err(t, err, regexp.MustCompile("object 0 is not a json object"))
+}
+
+func TestObjectBatchFailure(t *testing.T) {
+	t.Parallel()
+	calls := 0
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			if calls == 2 {
+				return jsonResponse(t, http.StatusInternalServerError, map[string]interface{}{"code": 1, "error": "down"}), nil
+			}
+			res := make([]interface{}, parse.MaxBatchSize)
+			for n := range res {
+				res[n] = map[string]interface{}{"success": map[string]string{}}
+			}
+			return jsonResponse(t, http.StatusOK, res), nil
+		}),
+	}
+	o := parse.ObjectClient{Client: c, Path: "classes/Yak"}
+	ids := make([]string, parse.MaxBatchSize+1)
+	for n := range ids {
+		ids[n] = fmt.Sprint(n)
+	}
+	res, err := o.DeleteAll(ids)
+	ensure.NotNil(t, err)
+	ensure.DeepEqual(t, len(res), len(ids))
+	ensure.DeepEqual(t, res[0].ID, "0")
+	ensure.True(t, res[parse.MaxBatchSize] == nil)
+}
+
+func TestObjectBatchConcurrency(t *testing.T) {
+	t.Parallel()
+	s := parsetest.NewServer()
+	defer s.Close()
+	o := parse.ObjectClient{Client: s.Client(), Path: "classes/Yak", Concurrency: 4}
+	yaks := make([]batchYak, 4*parse.MaxBatchSize)
+	res, err := o.PostAll(yaks)
+	ensure.Nil(t, err)
+	for _, r := range res {
+		ensure.Nil(t, r.Err)
+	}
+	ensure.DeepEqual(t, len(s.Objects("Yak")), len(yaks))
+}
@@ -0,0 +1,230 @@
+package parse_test
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func jsonResponse(t testing.TB, code int, v interface{}) *http.Response {
+	return &http.Response{
+		StatusCode: code,
+		Header:     make(http.Header),
+		Body:       ioutil.NopCloser(bytes.NewReader(jsonB(t, v))),
+	}
+}
+
+func decodeBody(t testing.TB, r *http.Request, v interface{}) {
+	ensure.Nil(t, json.NewDecoder(r.Body).Decode(v))
+}
+
+func TestObjectClientGet(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Method, "GET")
+			ensure.DeepEqual(t, r.URL.String(), "https://api.parse.com/1/classes/Yak/xyz")
+			return jsonResponse(t, http.StatusOK, map[string]int{"answer": 42}), nil
+		}),
+	}
+	o := parse.ObjectClient{Client: c, Path: "classes/Yak"}
+	var m map[string]int
+	_, err := o.Get("xyz", &m)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, m, map[string]int{"answer": 42})
+}
+
+func TestObjectClientGetWithOptions(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.URL.String(), "https://api.parse.com/1/classes/Yak/xyz?include=owner%2Cowner.team&keys=name%2Cowner")
+			return jsonResponse(t, http.StatusOK, map[string]string{"name": "Al"}), nil
+		}),
+	}
+	o := parse.ObjectClient{Client: c, Path: "classes/Yak"}
+	var m map[string]string
+	_, err := o.GetWithOptions("xyz", &parse.GetOptions{
+		Keys:    []string{"name", "owner"},
+		Include: []string{"owner", "owner.team"},
+	}, &m)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, m, map[string]string{"name": "Al"})
+}
+
+func TestObjectClientPost(t *testing.T) {
+	t.Parallel()
+	createdAt := time.Date(2015, 6, 16, 1, 2, 3, 0, time.UTC)
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Method, "POST")
+			ensure.DeepEqual(t, r.URL.Path, "/1/classes/Yak/")
+			var m map[string]int
+			decodeBody(t, r, &m)
+			ensure.DeepEqual(t, m, map[string]int{"answer": 42})
+			return jsonResponse(t, http.StatusCreated, map[string]interface{}{
+				"objectId":  "xyz",
+				"createdAt": createdAt,
+			}), nil
+		}),
+	}
+	o := parse.ObjectClient{Client: c, Path: "classes/Yak/"}
+	res, err := o.Post(map[string]int{"answer": 42})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res, &parse.CreateResponse{ID: "xyz", CreatedAt: createdAt})
+}
+
+func TestObjectClientPut(t *testing.T) {
+	t.Parallel()
+	updatedAt := time.Date(2015, 6, 16, 1, 2, 3, 0, time.UTC)
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Method, "PUT")
+			ensure.DeepEqual(t, r.URL.Path, "/1/classes/Yak/xyz")
+			return jsonResponse(t, http.StatusOK, map[string]interface{}{
+				"updatedAt": updatedAt,
+			}), nil
+		}),
+	}
+	o := parse.ObjectClient{Client: c, Path: "classes/Yak/"}
+	res, err := o.Put("xyz", map[string]int{"answer": 43})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res, &parse.UpdateResponse{UpdatedAt: updatedAt})
+}
+
+func TestObjectClientPutError(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			return jsonResponse(t, http.StatusNotFound, parse.Error{
+				Code:    101,
+				Message: "object not found for update",
+			}), nil
+		}),
+	}
+	o := parse.ObjectClient{Client: c, Path: "classes/Yak/"}
+	res, err := o.Put("xyz", map[string]int{"answer": 43})
+	ensure.True(t, res == nil)
+	ensure.DeepEqual(t, err, &parse.Error{
+		Code:       101,
+		Message:    "object not found for update",
+		StatusCode: http.StatusNotFound,
+		URL:        "https://api.parse.com/1/classes/Yak/xyz",
+	})
+}
+
+func TestObjectClientDelete(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Method, "DELETE")
+			ensure.DeepEqual(t, r.URL.Path, "/1/classes/Yak/xyz")
+			return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+		}),
+	}
+	o := parse.ObjectClient{Client: c, Path: "classes/Yak/"}
+	_, err := o.Delete("xyz")
+	ensure.Nil(t, err)
+}
+
+func TestObjectClientCount(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Method, "GET")
+			ensure.DeepEqual(t, r.URL.Path, "/1/classes/Yak/")
+			ensure.DeepEqual(t, r.URL.Query(), url.Values{
+				"count": {"1"},
+				"limit": {"0"},
+				"where": {`{"answer":42}`},
+			})
+			return jsonResponse(t, http.StatusOK, map[string]interface{}{
+				"results": []interface{}{},
+				"count":   7,
+			}), nil
+		}),
+	}
+	o := parse.ObjectClient{Client: c, Path: "classes/Yak/"}
+	count, err := o.Count(parse.Where{"answer": 42})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, count, 7)
+}
+
+func TestObjectClientCountAll(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.URL.Query().Get("where"), "")
+			return jsonResponse(t, http.StatusOK, map[string]int{"count": 3}), nil
+		}),
+	}
+	o := parse.ObjectClient{Client: c, Path: "classes/Yak/"}
+	count, err := o.Count(nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, count, 3)
+}
+
+func TestObjectClientCtx(t *testing.T) {
+	t.Parallel()
+	ctx := context.WithValue(context.Background(), ctxKey("yak"), "al")
+	var methods []string
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			methods = append(methods, r.Method)
+			ensure.DeepEqual(t, r.Context().Value(ctxKey("yak")), "al")
+			return jsonResponse(t, http.StatusOK, map[string]int{"count": 1}), nil
+		}),
+	}
+	o := parse.ObjectClient{Client: c, Path: "classes/Yak/"}
+	_, err := o.GetCtx(ctx, "a", nil)
+	ensure.Nil(t, err)
+	_, err = o.PostCtx(ctx, map[string]string{})
+	ensure.Nil(t, err)
+	_, err = o.PutCtx(ctx, "a", map[string]string{})
+	ensure.Nil(t, err)
+	_, err = o.DeleteCtx(ctx, "a")
+	ensure.Nil(t, err)
+	_, err = o.CountCtx(ctx, nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, methods, []string{"GET", "POST", "PUT", "DELETE", "GET"})
+}
+
+func TestClientEndpoints(t *testing.T) {
+	t.Parallel()
+	var paths []string
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			paths = append(paths, r.URL.Path)
+			return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+		}),
+	}
+	for _, o := range []*parse.ObjectClient{
+		c.Users(),
+		c.Roles(),
+		c.Installations(),
+		c.Sessions(),
+		c.Class("Yak"),
+		c.Class("_User"),
+		c.Class("_Session"),
+	} {
+		_, err := o.Get("a", nil)
+		ensure.Nil(t, err)
+	}
+	ensure.DeepEqual(t, paths, []string{
+		"/1/users/a",
+		"/1/roles/a",
+		"/1/installations/a",
+		"/1/sessions/a",
+		"/1/classes/Yak/a",
+		"/1/users/a",
+		"/1/sessions/a",
+	})
+}
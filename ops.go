@@ -0,0 +1,54 @@
+package parse
+
+import "encoding/json"
+
+type arrayOp struct {
+	Op      string        `json:"__op"`
+	Objects []interface{} `json:"objects"`
+}
+
+// Add is an operation that appends the objects to an array field.
+type Add []interface{}
+
+// MarshalJSON encodes the operation.
+func (a Add) MarshalJSON() ([]byte, error) {
+	return json.Marshal(arrayOp{Op: "Add", Objects: a})
+}
+
+// AddUnique is an operation that appends the objects to an array field if
+// they are not already present.
+type AddUnique []interface{}
+
+// MarshalJSON encodes the operation.
+func (a AddUnique) MarshalJSON() ([]byte, error) {
+	return json.Marshal(arrayOp{Op: "AddUnique", Objects: a})
+}
+
+// Remove is an operation that removes all instances of the objects from an
+// array field.
+type Remove []interface{}
+
+// MarshalJSON encodes the operation.
+func (r Remove) MarshalJSON() ([]byte, error) {
+	return json.Marshal(arrayOp{Op: "Remove", Objects: r})
+}
+
+// Increment is an operation that atomically increments a number field by the
+// given amount. Use a negative amount to decrement.
+type Increment int64
+
+// MarshalJSON encodes the operation.
+func (i Increment) MarshalJSON() ([]byte, error) {
+	return json.Marshal(struct {
+		Op     string `json:"__op"`
+		Amount int64  `json:"amount"`
+	}{Op: "Increment", Amount: int64(i)})
+}
+
+func stringsToObjects(s []string) []interface{} {
+	o := make([]interface{}, len(s))
+	for i, v := range s {
+		o[i] = v
+	}
+	return o
+}
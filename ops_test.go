@@ -0,0 +1,24 @@
+package parse_test
+
+import (
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestOps(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Op       interface{}
+		Expected string
+	}{
+		{parse.Add{1, "a"}, `{"__op":"Add","objects":[1,"a"]}`},
+		{parse.AddUnique{"a"}, `{"__op":"AddUnique","objects":["a"]}`},
+		{parse.Remove{"a", "b"}, `{"__op":"Remove","objects":["a","b"]}`},
+		{parse.Increment(-2), `{"__op":"Increment","amount":-2}`},
+	}
+	for _, c := range cases {
+		ensure.DeepEqual(t, string(jsonB(t, c.Op)), c.Expected)
+	}
+}
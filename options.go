@@ -0,0 +1,98 @@
+package parse
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+)
+
+var errMasterKeyApplicationID = errors.New("parse: WithMasterKey needs MasterKey, RestAPIKey, JavaScriptKey or SessionToken credentials for the ApplicationID")
+
+// An Option configures the Client made by NewClient.
+type Option func(*Client) error
+
+// NewClient returns a Client using the credentials, configured by the options
+// in order:
+//
+//	c, err := parse.NewClient(
+//		parse.RestAPIKey{ApplicationID: id, RestAPIKey: key},
+//		parse.WithBaseURL("https://example.com/parse"),
+//		parse.WithRetry(&parse.RetryPolicy{MaxAttempts: 3}),
+//	)
+func NewClient(cr Credentials, opts ...Option) (*Client, error) {
+	c := &Client{Credentials: cr}
+	for _, o := range opts {
+		if err := o(c); err != nil {
+			return nil, err
+		}
+	}
+	return c, nil
+}
+
+// WithBaseURL sets the BaseURL, as parsed by ParseBaseURL.
+func WithBaseURL(raw string) Option {
+	return func(c *Client) error {
+		u, err := ParseBaseURL(raw)
+		if err != nil {
+			return err
+		}
+		c.BaseURL = u
+		return nil
+	}
+}
+
+// WithHTTPClient uses the Transport and Timeout of the http.Client. Its
+// redirect policy and cookies are not used.
+func WithHTTPClient(hc *http.Client) Option {
+	return func(c *Client) error {
+		c.Transport = hc.Transport
+		c.Timeout = hc.Timeout
+		return nil
+	}
+}
+
+// WithRetry sets the Retry policy.
+func WithRetry(p *RetryPolicy) Option {
+	return func(c *Client) error {
+		c.Retry = p
+		return nil
+	}
+}
+
+// WithLogger sets the Logger.
+func WithLogger(l Logger) Option {
+	return func(c *Client) error {
+		c.Logger = l
+		return nil
+	}
+}
+
+// WithRedact adds patterns to Redact.
+func WithRedact(patterns ...*regexp.Regexp) Option {
+	return func(c *Client) error {
+		c.Redact = append(c.Redact, patterns...)
+		return nil
+	}
+}
+
+// WithMasterKey replaces the credentials with the Master Key for the same
+// application.
+func WithMasterKey(key string) Option {
+	return func(c *Client) error {
+		var id string
+		switch cr := c.Credentials.(type) {
+		case MasterKey:
+			id = cr.ApplicationID
+		case RestAPIKey:
+			id = cr.ApplicationID
+		case SessionToken:
+			id = cr.ApplicationID
+		case JavaScriptKey:
+			id = cr.ApplicationID
+		default:
+			return errMasterKeyApplicationID
+		}
+		c.Credentials = MasterKey{ApplicationID: id, MasterKey: key}
+		return nil
+	}
+}
@@ -0,0 +1,55 @@
+package parse_test
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestNewClient(t *testing.T) {
+	t.Parallel()
+	var logs []parse.RequestLog
+	tr := transportFunc(func(r *http.Request) (*http.Response, error) {
+		ensure.DeepEqual(t, r.URL.String(), "https://example.com/parse/classes/Yak")
+		ensure.DeepEqual(t, r.Header.Get("X-Parse-Master-Key"), "master")
+		ensure.DeepEqual(t, r.Header.Get("X-Parse-REST-API-Key"), "")
+		return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+	})
+	policy := &parse.RetryPolicy{MaxAttempts: 3}
+	secret := regexp.MustCompile("s3cret")
+	c, err := parse.NewClient(
+		defaultRestAPIKey,
+		parse.WithBaseURL("https://example.com/parse"),
+		parse.WithHTTPClient(&http.Client{Transport: tr, Timeout: time.Minute}),
+		parse.WithRetry(policy),
+		parse.WithLogger(parse.LoggerFunc(func(l parse.RequestLog) { logs = append(logs, l) })),
+		parse.WithRedact(secret),
+		parse.WithMasterKey("master"),
+	)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, c.Credentials, parse.MasterKey{ApplicationID: defaultApplicationID, MasterKey: "master"})
+	ensure.DeepEqual(t, c.Timeout, time.Minute)
+	ensure.True(t, c.Retry == policy)
+	ensure.DeepEqual(t, c.Redact, []*regexp.Regexp{secret})
+
+	_, err = c.Get(&url.URL{Path: "classes/Yak"}, nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(logs), 1)
+}
+
+func TestNewClientInvalidBaseURL(t *testing.T) {
+	t.Parallel()
+	_, err := parse.NewClient(defaultRestAPIKey, parse.WithBaseURL("example.com"))
+	ensure.Err(t, err, regexp.MustCompile("absolute http or https URL"))
+}
+
+func TestNewClientMasterKeyWithoutCredentials(t *testing.T) {
+	t.Parallel()
+	_, err := parse.NewClient(nil, parse.WithMasterKey("master"))
+	ensure.Err(t, err, regexp.MustCompile("WithMasterKey needs"))
+}
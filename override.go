@@ -0,0 +1,46 @@
+package parse
+
+import (
+	"bytes"
+	"encoding/json"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+)
+
+// overrideMethod sends GET requests with URLs longer than MaxURLLength as a
+// POST with the query parameters in the body and a _method of GET, which the
+// API treats the same as the GET.
+func (c *Client) overrideMethod(next http.RoundTripper) http.RoundTripper {
+	return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if requestMethod(r) != "GET" || r.URL.RawQuery == "" || len(r.URL.String()) <= c.MaxURLLength {
+			return next.RoundTrip(r)
+		}
+		v, err := url.ParseQuery(r.URL.RawQuery)
+		if err != nil {
+			return nil, err
+		}
+		params := map[string]string{"_method": "GET"}
+		for k := range v {
+			params[k] = v.Get(k)
+		}
+		body, err := json.Marshal(params)
+		if err != nil {
+			return nil, err
+		}
+
+		r = r.Clone(r.Context())
+		u := *r.URL
+		u.RawQuery = ""
+		r.URL = &u
+		r.Method = "POST"
+		r.Header.Set("Content-Type", "application/json")
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		r.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(body)), nil
+		}
+		r.ContentLength = int64(len(body))
+		return next.RoundTrip(r)
+	})
+}
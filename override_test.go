@@ -0,0 +1,81 @@
+package parse_test
+
+import (
+	"encoding/json"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestOverrideMethod(t *testing.T) {
+	t.Parallel()
+	where := `{"name":{"$in":["` + strings.Repeat("yak", 100) + `"]}}`
+	var req *http.Request
+	var body map[string]string
+	c := &parse.Client{
+		MaxURLLength: 100,
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			req = r
+			b, err := ioutil.ReadAll(r.Body)
+			ensure.Nil(t, err)
+			ensure.Nil(t, json.Unmarshal(b, &body))
+			return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+		}),
+	}
+	q := url.Values{"where": {where}, "limit": {"10"}}
+	u := &url.URL{Path: "classes/Yak", RawQuery: q.Encode()}
+	_, err := c.Get(u, nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, req.Method, "POST")
+	ensure.DeepEqual(t, req.URL.String(), "https://api.parse.com/1/classes/Yak")
+	ensure.DeepEqual(t, req.Header.Get("Content-Type"), "application/json")
+	ensure.DeepEqual(t, body, map[string]string{
+		"_method": "GET",
+		"where":   where,
+		"limit":   "10",
+	})
+	ensure.DeepEqual(t, u.RawQuery, q.Encode())
+}
+
+func TestOverrideMethodShortURL(t *testing.T) {
+	t.Parallel()
+	var req *http.Request
+	c := &parse.Client{
+		MaxURLLength: 100,
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			req = r
+			return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+		}),
+	}
+	_, err := c.Get(&url.URL{Path: "classes/Yak", RawQuery: "limit=10"}, nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, req.Method, "GET")
+	ensure.DeepEqual(t, req.URL.RawQuery, "limit=10")
+}
+
+func TestOverrideMethodRetried(t *testing.T) {
+	t.Parallel()
+	var attempts int
+	c := &parse.Client{
+		MaxURLLength: 10,
+		Retry:        &parse.RetryPolicy{MaxAttempts: 2},
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			attempts++
+			b, err := ioutil.ReadAll(r.Body)
+			ensure.Nil(t, err)
+			ensure.StringContains(t, string(b), `"_method":"GET"`)
+			if attempts == 1 {
+				return jsonResponse(t, http.StatusServiceUnavailable, map[string]string{}), nil
+			}
+			return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+		}),
+	}
+	_, err := c.Get(&url.URL{Path: "classes/Yak", RawQuery: "limit=10"}, nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, attempts, 2)
+}
@@ -0,0 +1,109 @@
+package parse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+)
+
+// Params are the query parameters of class queries.
+type Params struct {
+	// Where is marshalled as the where clause, usually a Where. The keys of
+	// a Where are checked with ValidateKey, other values are sent as they
+	// are, for example to query keys of Object fields using dots.
+	Where interface{}
+
+	// Order has the keys to order by, prefixed with - for descending order.
+	Order []string
+
+	// Limit is the maximum number of results. Zero uses the server default.
+	Limit int
+
+	// Skip is the number of results to skip.
+	Skip int
+
+	// Keys restricts the returned fields.
+	Keys []string
+
+	// ExcludeKeys leaves out fields. It needs Parse Server 4.5 or later.
+	ExcludeKeys []string
+
+	// Include has the Pointer fields to return the objects of.
+	Include []string
+
+	// Count includes the number of matching objects.
+	Count bool
+}
+
+// Values encodes the parameters.
+func (p *Params) Values() (url.Values, error) {
+	v := make(url.Values)
+	if w, ok := p.Where.(Where); ok {
+		if err := w.validate(); err != nil {
+			return nil, err
+		}
+	}
+	if p.Where != nil {
+		b, err := json.Marshal(p.Where)
+		if err != nil {
+			return nil, err
+		}
+		v.Set("where", string(b))
+	}
+	if len(p.Order) != 0 {
+		v.Set("order", strings.Join(p.Order, ","))
+	}
+	if p.Limit != 0 {
+		v.Set("limit", strconv.Itoa(p.Limit))
+	}
+	if p.Skip != 0 {
+		v.Set("skip", strconv.Itoa(p.Skip))
+	}
+	if len(p.Keys) != 0 {
+		v.Set("keys", strings.Join(p.Keys, ","))
+	}
+	if len(p.ExcludeKeys) != 0 {
+		v.Set("excludeKeys", strings.Join(p.ExcludeKeys, ","))
+	}
+	if len(p.Include) != 0 {
+		v.Set("include", strings.Join(p.Include, ","))
+	}
+	if p.Count {
+		v.Set("count", "1")
+	}
+	return v, nil
+}
+
+// URL returns the URL of the path with the parameters as its query.
+func (p *Params) URL(path string) (*url.URL, error) {
+	v, err := p.Values()
+	if err != nil {
+		return nil, err
+	}
+	return &url.URL{Path: path, RawQuery: v.Encode()}, nil
+}
+
+// Query fetches the objects of the class matching the parameters and
+// unmarshals the response into result, usually a struct with a Results
+// slice. A nil p fetches the first objects.
+func (c *Client) Query(className string, p *Params, result interface{}) (*http.Response, error) {
+	return c.QueryCtx(context.Background(), className, p, result)
+}
+
+// QueryCtx is Query with a context used for the request.
+func (c *Client) QueryCtx(ctx context.Context, className string, p *Params, result interface{}) (*http.Response, error) {
+	if className == "" {
+		return nil, errEmptyClassName
+	}
+	if p == nil {
+		p = &Params{}
+	}
+	u, err := p.URL("classes/" + className)
+	if err != nil {
+		return nil, err
+	}
+	return c.GetCtx(ctx, u, result)
+}
@@ -0,0 +1,78 @@
+package parse_test
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestParamsValues(t *testing.T) {
+	t.Parallel()
+	p := parse.Params{
+		Where:       parse.Where{}.EqualTo("name", "yak"),
+		Order:       []string{"-score", "name"},
+		Limit:       10,
+		Skip:        20,
+		Keys:        []string{"name", "score"},
+		ExcludeKeys: []string{"secret"},
+		Include:     []string{"owner"},
+		Count:       true,
+	}
+	v, err := p.Values()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, v, url.Values{
+		"where":       {`{"name":"yak"}`},
+		"order":       {"-score,name"},
+		"limit":       {"10"},
+		"skip":        {"20"},
+		"keys":        {"name,score"},
+		"excludeKeys": {"secret"},
+		"include":     {"owner"},
+		"count":       {"1"},
+	})
+}
+
+func TestParamsEmpty(t *testing.T) {
+	t.Parallel()
+	var p parse.Params
+	u, err := p.URL("classes/Yak")
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, u.String(), "classes/Yak")
+}
+
+func TestParamsInvalidWhere(t *testing.T) {
+	t.Parallel()
+	p := parse.Params{Where: map[string]interface{}{"a": func() {}}}
+	_, err := p.Values()
+	ensure.NotNil(t, err)
+}
+
+func TestQuery(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.URL.Path, "/1/classes/Yak")
+			ensure.DeepEqual(t, r.URL.Query(), url.Values{"limit": {"2"}, "order": {"name"}})
+			return jsonResponse(t, http.StatusOK, map[string]interface{}{
+				"results": []map[string]string{{"name": "a"}, {"name": "b"}},
+			}), nil
+		}),
+	}
+	var res struct {
+		Results []struct{ Name string }
+	}
+	_, err := c.Query("Yak", &parse.Params{Limit: 2, Order: []string{"name"}}, &res)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(res.Results), 2)
+	ensure.DeepEqual(t, res.Results[1].Name, "b")
+}
+
+func TestQueryEmptyClassName(t *testing.T) {
+	t.Parallel()
+	_, err := (&parse.Client{}).Query("", nil, nil)
+	ensure.Err(t, err, regexp.MustCompile("empty class name"))
+}
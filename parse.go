@@ -1,22 +1,38 @@
 // Package parse provides a client for the Parse API.
+//
+// This package is kept dependency-light and only relies on the standard
+// library, so services that only need to make API calls don't pull in more
+// than that. Optional heavy subsystems, such as command line tools, fake
+// servers for tests and streaming clients, live in their own sub-packages
+// with their own dependencies. They build on the exported Client and
+// Credentials types of this package, so they interoperate with each other
+// and with any Client configured by the application.
 package parse
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/url"
+	"regexp"
+	"time"
 )
 
 const (
+	defaultMaxErrorBody = 64 << 10
+	truncatedMarker     = "... (truncated)"
+
 	userAgentHeader     = "User-Agent"
-	defaultUserAgent    = "go-parse-1"
+	defaultUserAgent    = "go-parse/" + Version
 	masterKeyHeader     = "X-Parse-Master-Key"
 	restAPIKeyHeader    = "X-Parse-REST-API-Key"
 	sessionTokenHeader  = "X-Parse-Session-Token"
+	javaScriptKeyHeader = "X-Parse-Javascript-Key"
 	applicationIDHeader = "X-Parse-Application-ID"
 )
 
@@ -25,6 +41,7 @@ var (
 	errEmptyMasterKey     = errors.New("parse: cannot use empty MasterKey")
 	errEmptyRestAPIKey    = errors.New("parse: cannot use empty RestAPIKey")
 	errEmptySessionToken  = errors.New("parse: cannot use empty SessionToken")
+	errEmptyJavaScriptKey = errors.New("parse: cannot use empty JavaScriptKey")
 
 	// The default base URL for the API.
 	defaultBaseURL = url.URL{
@@ -83,10 +100,35 @@ func (k RestAPIKey) Modify(r *http.Request) error {
 	return nil
 }
 
+// JavaScriptKey adds the JavaScript Key to the request, for servers that
+// don't accept the Rest API Key.
+type JavaScriptKey struct {
+	ApplicationID string
+	JavaScriptKey string
+}
+
+// Modify adds the JavaScript Key header.
+func (k JavaScriptKey) Modify(r *http.Request) error {
+	if k.ApplicationID == "" {
+		return errEmptyApplicationID
+	}
+	if k.JavaScriptKey == "" {
+		return errEmptyJavaScriptKey
+	}
+	if r.Header == nil {
+		r.Header = make(http.Header)
+	}
+	r.Header.Set(applicationIDHeader, string(k.ApplicationID))
+	r.Header.Set(javaScriptKeyHeader, string(k.JavaScriptKey))
+	return nil
+}
+
 // SessionToken adds the Rest API Key and the Session Token to the request.
+// The JavaScript Key is used instead of the Rest API Key when it is set.
 type SessionToken struct {
 	ApplicationID string
 	RestAPIKey    string
+	JavaScriptKey string
 	SessionToken  string
 }
 
@@ -95,7 +137,7 @@ func (t SessionToken) Modify(r *http.Request) error {
 	if t.ApplicationID == "" {
 		return errEmptyApplicationID
 	}
-	if t.RestAPIKey == "" {
+	if t.RestAPIKey == "" && t.JavaScriptKey == "" {
 		return errEmptyRestAPIKey
 	}
 	if t.SessionToken == "" {
@@ -105,7 +147,11 @@ func (t SessionToken) Modify(r *http.Request) error {
 		r.Header = make(http.Header)
 	}
 	r.Header.Set(applicationIDHeader, string(t.ApplicationID))
-	r.Header.Set(restAPIKeyHeader, string(t.RestAPIKey))
+	if t.JavaScriptKey != "" {
+		r.Header.Set(javaScriptKeyHeader, string(t.JavaScriptKey))
+	} else {
+		r.Header.Set(restAPIKeyHeader, string(t.RestAPIKey))
+	}
 	r.Header.Set(sessionTokenHeader, string(t.SessionToken))
 	return nil
 }
@@ -115,6 +161,17 @@ func (t SessionToken) Modify(r *http.Request) error {
 type Error struct {
 	Message string `json:"error"`
 	Code    int    `json:"code"`
+
+	// StatusCode is the HTTP status of the response.
+	StatusCode int `json:"-"`
+
+	// URL of the failed request, with passwords, session tokens and keys
+	// redacted.
+	URL string `json:"-"`
+
+	// RequestID is the correlation ID of the failed request, if it was made
+	// with a context from WithCorrelationID.
+	RequestID string `json:"-"`
 }
 
 func (e *Error) Error() string {
@@ -129,17 +186,42 @@ func (e *Error) Error() string {
 	if e.Message != "" {
 		fmt.Fprintf(&buf, "message=%q", e.Message)
 	}
+	if e.RequestID != "" {
+		fmt.Fprintf(&buf, " for request %s", e.RequestID)
+	}
 	return buf.String()
 }
 
+// Is reports whether target is an *Error with the same Code, so that
+// errors.Is(err, &parse.Error{Code: parse.CodeObjectNotFound}) finds a
+// missing object whatever the message.
+func (e *Error) Is(target error) bool {
+	t, ok := target.(*Error)
+	return ok && t != nil && t.Code == e.Code
+}
+
 // A RawError with the HTTP StatusCode and Body. When a valid Parse JSON error
 // is not found, the returned error will be of this type.
 type RawError struct {
 	StatusCode int
-	Body       []byte
+
+	// Body of the response, with the credentials of the request, session
+	// tokens and passwords redacted.
+	Body []byte
+
+	// URL of the failed request, with passwords, session tokens and keys
+	// redacted.
+	URL string
+
+	// RequestID is the correlation ID of the failed request, if it was made
+	// with a context from WithCorrelationID.
+	RequestID string
 }
 
 func (e *RawError) Error() string {
+	if e.RequestID != "" {
+		return fmt.Sprintf("parse: error with status=%d and body=%q for request %s", e.StatusCode, e.Body, e.RequestID)
+	}
 	return fmt.Sprintf("parse: error with status=%d and body=%q", e.StatusCode, e.Body)
 }
 
@@ -149,24 +231,166 @@ type Client struct {
 	// nil http.DefaultTransport will be used.
 	Transport http.RoundTripper
 
-	// The base URL to parse relative URLs off. If you pass absolute URLs to
-	// Client functions they are used as-is. When nil, the production Parse URL
-	// will be used.
+	// Middleware wraps the Transport, the first one being the outermost. It
+	// sees the requests after the Client added its headers, and sees every
+	// attempt of a retried request.
+	Middleware []Middleware
+
+	// The base URL to parse relative URLs off, such as the URL of a Parse
+	// Server including its mount path, like https://example.com/parse/. A
+	// missing trailing slash is assumed. If you pass absolute URLs to Client
+	// functions they are used as-is. When nil, the production Parse URL will be
+	// used. See ParseBaseURL.
 	BaseURL *url.URL
 
 	// Credentials if set, will be included on every request.
 	Credentials Credentials
 
+	// Header has extra headers added to every request, such as tracing or
+	// installation headers, unless the request sets them itself. Use
+	// WithHeader to add headers to some calls only.
+	Header http.Header
+
 	// UserAgent to use in the User-Agent header.  When nil defaultUserAgent
 	// will be used.
 	UserAgent string
+
+	// AppVersion optionally identifies the version of the application using
+	// the Client in the X-Parse-App-Build-Version header, so that server logs
+	// can tell deployments apart.
+	AppVersion string
+
+	// CorrelationIDHeader is the header set to the ID given to
+	// WithCorrelationID. Defaults to X-Request-Id.
+	CorrelationIDHeader string
+
+	// Timeout limits the time a request may take, including retries and
+	// reading the response. Zero means no limit.
+	Timeout time.Duration
+
+	// IdempotencyKeys adds a generated X-Parse-Request-Id header to POST and
+	// PUT requests, so Parse Server with its idempotency option enabled
+	// ignores retries of requests it already processed. Use
+	// WithIdempotencyKey to pick the key instead.
+	IdempotencyKeys bool
+
+	// Logger optionally receives the method, URL, status and duration of
+	// every attempt of the requests.
+	Logger Logger
+
+	// DryRun validates and logs POST, PUT and DELETE requests instead of
+	// sending them, and returns synthetic results such as random object IDs.
+	// The requests are logged to the Logger, or else the standard logger.
+	// Other requests are sent as usual.
+	DryRun bool
+
+	// Metrics optionally receives the latency, status and retries of the
+	// requests.
+	Metrics Metrics
+
+	// OnRequest is optionally called before a request is sent, and
+	// OnResponse after it completed, once per request whatever the number of
+	// attempts. They are meant for auditing the calls made, and are called
+	// concurrently when the Client is. With OnResponse set successful
+	// responses are buffered, to find the objectId in them.
+	OnRequest  func(RequestInfo)
+	OnResponse func(ResponseInfo)
+
+	// Audit optionally records the POST, PUT and DELETE requests.
+	Audit AuditSink
+
+	// Debug optionally receives a dump of every request and response,
+	// including the headers and bodies. Credentials, session tokens and
+	// passwords are redacted. Each dump is a single Write.
+	Debug io.Writer
+
+	// Redact has extra patterns, such as private tokens or personal data,
+	// whose matches are replaced in error messages, logs, debug dumps and
+	// audit hooks, in addition to the credentials, session tokens and
+	// passwords that are always redacted.
+	Redact []*regexp.Regexp
+
+	// Codec encodes request bodies and decodes results. Defaults to
+	// JSONCodec.
+	Codec Codec
+
+	// DisallowUnknownFields makes decoding a result into a struct fail when
+	// the response has a field the struct doesn't, to catch drift between
+	// the schema and the structs in staging rather than silently losing
+	// data. Use a copy of the Client to enable it for some calls only.
+	DisallowUnknownFields bool
+
+	// UseNumber decodes numbers in results of type interface{}, such as the
+	// values of a map[string]interface{}, as json.Number instead of float64,
+	// so that integers above 2^53 keep their exact value. Struct fields of
+	// type int64 are exact either way.
+	UseNumber bool
+
+	// Lenient coerces values of results that don't match the type of the
+	// struct fields they are decoded into, for data written inconsistently
+	// over time: numbers stored as strings become numbers, 0, 1, "true" and
+	// "false" become booleans, and numbers become strings. An empty string
+	// leaves a number or boolean field unset. Fields can opt in on their own
+	// with the parse:"lenient" tag. Types with their own UnmarshalJSON method,
+	// such as NullInt, are not coerced.
+	Lenient bool
+
+	// MaxErrorBody is the number of bytes of an error response kept in the
+	// returned error. Longer bodies, such as HTML error pages, are cut and end
+	// with "... (truncated)". Defaults to 64KiB.
+	MaxErrorBody int
+
+	// Gzip asks for gzip compressed responses and decompresses them. The
+	// http.Transport does this by itself, so it is only needed with a
+	// Transport that doesn't.
+	Gzip bool
+
+	// GzipRequests optionally compresses request bodies of at least as many
+	// bytes, such as large batches and pushes. Zero disables it.
+	GzipRequests int
+
+	// MaxURLLength optionally limits the length of GET request URLs. Longer
+	// queries, usually with large where clauses, are sent as a POST with the
+	// parameters in the body, since some proxies reject long URLs. They are
+	// still treated as a GET, for example when retrying. Zero means no limit.
+	MaxURLLength int
+
+	// Capabilities optionally caches the features of the server. When set,
+	// requests for features the server is too old for fail with an
+	// UnsupportedError instead of being sent. It needs the Master Key.
+	Capabilities *Capabilities
+
+	// Singleflight optionally collapses concurrent identical GET requests
+	// into one.
+	Singleflight *Singleflight
+
+	// Partition is added to the keys of the Singleflight and of the Cache of
+	// ObjectClients. Clients sharing them must use different Partitions when
+	// their Middleware makes the responses differ, as a Tenant does. See
+	// WithTenant.
+	Partition string
+
+	// Retry optionally retries requests that fail because of transient
+	// errors. When nil requests are not retried.
+	Retry *RetryPolicy
+
+	// Clock optionally replaces the real clock for retry delays and times
+	// derived from the current time, in tests.
+	Clock Clock
+
+	// ctx is attached to requests that don't have a context of their own.
+	ctx context.Context
 }
 
 func (c *Client) transport() http.RoundTripper {
-	if c.Transport == nil {
-		return http.DefaultTransport
+	t := c.Transport
+	if t == nil {
+		t = http.DefaultTransport
+	}
+	for i := len(c.Middleware) - 1; i >= 0; i-- {
+		t = c.Middleware[i](t)
 	}
-	return c.Transport
+	return t
 }
 
 // Get performs a GET method call on the given url and unmarshal response into
@@ -193,37 +417,66 @@ func (c *Client) Delete(u *url.URL, result interface{}) (*http.Response, error)
 	return c.Do(&http.Request{Method: "DELETE", URL: u}, nil, result)
 }
 
+// GetCtx is Get with a context used for the request.
+func (c *Client) GetCtx(ctx context.Context, u *url.URL, result interface{}) (*http.Response, error) {
+	return c.DoCtx(ctx, &http.Request{Method: "GET", URL: u}, nil, result)
+}
+
+// PostCtx is Post with a context used for the request.
+func (c *Client) PostCtx(ctx context.Context, u *url.URL, body, result interface{}) (*http.Response, error) {
+	return c.DoCtx(ctx, &http.Request{Method: "POST", URL: u}, body, result)
+}
+
+// PutCtx is Put with a context used for the request.
+func (c *Client) PutCtx(ctx context.Context, u *url.URL, body, result interface{}) (*http.Response, error) {
+	return c.DoCtx(ctx, &http.Request{Method: "PUT", URL: u}, body, result)
+}
+
+// DeleteCtx is Delete with a context used for the request.
+func (c *Client) DeleteCtx(ctx context.Context, u *url.URL, result interface{}) (*http.Response, error) {
+	return c.DoCtx(ctx, &http.Request{Method: "DELETE", URL: u}, nil, result)
+}
+
 // RoundTrip performs a RoundTrip ignoring the request and response bodies. It
-// is up to the caller to close them. This method modifies the request.
+// is up to the caller to close them. The request is not modified, so the same
+// request, or its URL, can be reused and shared between goroutines. Its body
+// can only be sent once though, unless it has a GetBody function.
 func (c *Client) RoundTrip(req *http.Request) (*http.Response, error) {
+	return c.perform(req.Clone(req.Context()))
+}
+
+// perform is RoundTrip on a request owned by the Client, which it modifies.
+func (c *Client) perform(req *http.Request) (*http.Response, error) {
+	if c.ctx != nil && req.Context() == context.Background() {
+		req = req.WithContext(c.ctx)
+	}
 	req.Proto = "HTTP/1.1"
 	req.ProtoMajor = 1
 	req.ProtoMinor = 1
 
 	if req.URL == nil {
-		if c.BaseURL == nil {
-			req.URL = &defaultBaseURL
-		} else {
-			req.URL = c.BaseURL
-		}
-	} else {
-		if !req.URL.IsAbs() {
-			if c.BaseURL == nil {
-				req.URL = defaultBaseURL.ResolveReference(req.URL)
-			} else {
-				req.URL = c.BaseURL.ResolveReference(req.URL)
-			}
-		}
+		req.URL = c.baseURL()
+	} else if !req.URL.IsAbs() {
+		req.URL = c.baseURL().ResolveReference(req.URL)
 	}
 
 	if req.Host == "" {
 		req.Host = req.URL.Host
 	}
+	if err := c.requireFor(req); err != nil {
+		return nil, err
+	}
 
 	if req.Header == nil {
 		req.Header = make(http.Header)
 	}
 
+	for k, v := range c.Header {
+		if _, ok := req.Header[k]; !ok {
+			req.Header[k] = v
+		}
+	}
+
 	var userAgent string
 	if c.UserAgent == "" {
 		userAgent = defaultUserAgent
@@ -232,46 +485,191 @@ func (c *Client) RoundTrip(req *http.Request) (*http.Response, error) {
 	}
 
 	req.Header.Add(userAgentHeader, userAgent)
+	c.setVersionHeaders(req)
 	if c.Credentials != nil {
 		if err := c.Credentials.Modify(req); err != nil {
 			return nil, err
 		}
 	}
+	if err := c.setRequestID(req); err != nil {
+		return nil, err
+	}
+	requestID := c.setCorrelationID(req)
+	cl, err := c.startCall(req)
+	if err != nil {
+		return nil, err
+	}
 
-	res, err := c.transport().RoundTrip(req)
+	stats := statsFrom(req.Context())
+	if stats != nil {
+		*stats = RequestStats{}
+	}
+	start := time.Now()
+	cancel := func() {}
+	if c.Timeout > 0 {
+		var ctx context.Context
+		ctx, cancel = context.WithTimeout(req.Context(), c.Timeout)
+		req = req.WithContext(ctx)
+	}
+	res, err := c.sendShared(req)
+	if stats != nil {
+		stats.Duration = time.Since(start)
+	}
 	if err != nil {
+		cancel()
+		cl.finish(c, res, nil, err)
 		return res, err
 	}
 
 	if res.StatusCode > 399 || res.StatusCode < 200 {
-		body, err := ioutil.ReadAll(res.Body)
+		body, err := c.readErrorBody(res.Body)
+		cancel()
+		if stats != nil {
+			stats.Duration = time.Since(start)
+			stats.ResponseSize = int64(len(body))
+		}
 		if err != nil {
+			cl.finish(c, res, nil, err)
 			return res, err
 		}
+		// Error messages end up in logs, so they must not carry secrets.
+		body = c.redactSecrets(body, req.Header)
 
+		var resErr error = &RawError{
+			StatusCode: res.StatusCode,
+			Body:       body,
+			URL:        c.redactURL(req.URL),
+			RequestID:  requestID,
+		}
 		if len(body) > 0 {
 			var apiErr Error
-			if json.Unmarshal(body, &apiErr) == nil {
-				return res, &apiErr
+			if json.Unmarshal(body, &apiErr) == nil && (apiErr.Code != 0 || apiErr.Message != "") {
+				apiErr.StatusCode = res.StatusCode
+				apiErr.URL = c.redactURL(req.URL)
+				apiErr.RequestID = requestID
+				resErr = &apiErr
 			}
 		}
-		return res, &RawError{
-			StatusCode: res.StatusCode,
-			Body:       body,
+		if res.StatusCode == http.StatusTooManyRequests {
+			resErr = &RateLimitError{
+				RetryAfter: retryAfter(res.Header, c.clock().Now()),
+				Err:        resErr,
+			}
 		}
+		cl.finish(c, res, nil, resErr)
+		return res, resErr
 	}
 
+	if c.buffered() {
+		body, err := ioutil.ReadAll(res.Body)
+		res.Body.Close()
+		if err != nil {
+			cancel()
+			cl.finish(c, res, nil, err)
+			return res, err
+		}
+		res.Body = ioutil.NopCloser(bytes.NewReader(body))
+		cl.finish(c, res, body, nil)
+	}
+	res.Body = &responseBody{ReadCloser: res.Body, cancel: cancel, stats: stats, start: start}
 	return res, nil
 }
 
-// Do performs a Parse API call. This method modifies the request and adds the
-// Authentication headers. The body is JSON encoded and for responses in the
-// 2xx or 3xx range the response will be JSON decoded into result, for others
-// an error of type Error will be returned.
+// readErrorBody reads up to MaxErrorBody bytes of an error response, and
+// closes it.
+func (c *Client) readErrorBody(r io.ReadCloser) ([]byte, error) {
+	defer r.Close()
+	max := c.MaxErrorBody
+	if max <= 0 {
+		max = defaultMaxErrorBody
+	}
+	buf := getBuffer()
+	defer putBuffer(buf)
+	_, err := buf.ReadFrom(io.LimitReader(r, int64(max)+1))
+	if buf.Len() > max {
+		buf.Truncate(max)
+		buf.WriteString(truncatedMarker)
+	}
+	return append([]byte(nil), buf.Bytes()...), err
+}
+
+// responseBody releases the timeout context of a request once its response
+// body is closed, and completes its stats.
+type responseBody struct {
+	io.ReadCloser
+	cancel func()
+	stats  *RequestStats
+	start  time.Time
+	n      int64
+}
+
+func (b *responseBody) Read(p []byte) (int, error) {
+	n, err := b.ReadCloser.Read(p)
+	b.n += int64(n)
+	return n, err
+}
+
+func (b *responseBody) Close() error {
+	err := b.ReadCloser.Close()
+	b.cancel()
+	if b.stats != nil {
+		b.stats.Duration = time.Since(b.start)
+		b.stats.ResponseSize = b.n
+	}
+	return err
+}
+
+// Do performs a Parse API call. The Authentication headers are added to a copy
+// of the request, leaving the request itself unmodified and safe for reuse.
+// The body is JSON encoded and for responses in the 2xx or 3xx range the
+// response will be JSON decoded into result, for others an error of type
+// Error will be returned.
 func (c *Client) Do(req *http.Request, body, result interface{}) (*http.Response, error) {
+	req, err := c.prepare(req, body)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.perform(req)
+	if err != nil {
+		return res, err
+	}
+	defer res.Body.Close()
+
+	if result != nil {
+		// Decoders copy what they keep, so the buffer can be reused.
+		buf := getBuffer()
+		defer putBuffer(buf)
+		if _, err := buf.ReadFrom(res.Body); err != nil {
+			return res, err
+		}
+		if err := c.decode(buf.Bytes(), result); err != nil {
+			return res, err
+		}
+	}
+	return res, nil
+}
+
+// DoRaw performs a Parse API call like Do, but returns the body of successful
+// responses instead of decoding it, so large results can be streamed to
+// disk or proxied. The caller must close the body.
+func (c *Client) DoRaw(req *http.Request, body interface{}) (io.ReadCloser, error) {
+	req, err := c.prepare(req, body)
+	if err != nil {
+		return nil, err
+	}
+	res, err := c.perform(req)
+	if err != nil {
+		return nil, err
+	}
+	return res.Body, nil
+}
+
+// prepare returns a copy of the request with the JSON encoded body.
+func (c *Client) prepare(req *http.Request, body interface{}) (*http.Request, error) {
+	req = req.Clone(req.Context())
 	// we need to buffer as Parse requires a Content-Length
 	if body != nil {
-		bd, err := json.Marshal(body)
+		bd, err := c.codec().Marshal(body)
 		if err != nil {
 			return nil, err
 		}
@@ -280,21 +678,53 @@ func (c *Client) Do(req *http.Request, body, result interface{}) (*http.Response
 		}
 		req.Header.Set("Content-Type", "application/json")
 		req.Body = ioutil.NopCloser(bytes.NewReader(bd))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(bd)), nil
+		}
 		req.ContentLength = int64(len(bd))
 	}
+	return req, nil
+}
 
-	res, err := c.RoundTrip(req)
-	if err != nil {
-		return res, err
-	}
-	defer res.Body.Close()
+// DoCtx is Do with a context used for the request. Cancelling the context
+// aborts the request in flight.
+func (c *Client) DoCtx(ctx context.Context, req *http.Request, body, result interface{}) (*http.Response, error) {
+	return c.Do(req.WithContext(ctx), body, result)
+}
 
-	if result != nil {
-		if err := json.NewDecoder(res.Body).Decode(result); err != nil {
-			return res, err
-		}
+// WithContext returns a new instance of the Client that uses the given context
+// for all requests that don't have one already. This makes it possible to
+// cancel, or set deadlines on, the calls of clients built on top of the
+// Client, such as ObjectClient and SchemaClient:
+//
+//	sc := parse.SchemaClient{Client: c.WithContext(ctx)}
+func (c *Client) WithContext(ctx context.Context) *Client {
+	var c2 Client
+	c2 = *c
+	c2.ctx = ctx
+	return &c2
+}
+
+// WithTimeout returns a new instance of the Client using the given Timeout,
+// for calls that need a different limit than the other calls of the Client.
+func (c *Client) WithTimeout(d time.Duration) *Client {
+	var c2 Client
+	c2 = *c
+	c2.Timeout = d
+	return &c2
+}
+
+// WithHeader returns a new instance of the Client that adds the header to its
+// requests, in addition to the Header of the Client.
+func (c *Client) WithHeader(key, value string) *Client {
+	var c2 Client
+	c2 = *c
+	c2.Header = c.Header.Clone()
+	if c2.Header == nil {
+		c2.Header = make(http.Header)
 	}
-	return res, nil
+	c2.Header.Set(key, value)
+	return &c2
 }
 
 // WithCredentials returns a new instance of the Client using the given
@@ -2,15 +2,19 @@ package parse_test
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
+	"io"
 	"io/ioutil"
 	"net/http"
 	"net/http/httptest"
 	"net/url"
 	"regexp"
 	"strings"
+	"sync"
 	"testing"
+	"time"
 
 	"github.com/facebookgo/ensure"
 	"github.com/facebookgo/parse"
@@ -76,8 +80,8 @@ func TestErrorCases(t *testing.T) {
 					Path:   "/1/classes/Foo/Bar",
 				},
 			},
-			Body:  map[int]int{},
-			Error: "unsupported type: map[int]int",
+			Body:  make(chan int),
+			Error: "unsupported type: chan int",
 			Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
 				panic("not reached")
 			}),
@@ -253,6 +257,18 @@ func TestEmptyRestAPIKey(t *testing.T) {
 	ensure.Err(t, mk.Modify(nil), regexp.MustCompile("empty RestAPIKey"))
 }
 
+func TestJavaScriptKeyEmptyApplicationID(t *testing.T) {
+	t.Parallel()
+	var k parse.JavaScriptKey
+	ensure.Err(t, k.Modify(nil), regexp.MustCompile("empty ApplicationID"))
+}
+
+func TestEmptyJavaScriptKey(t *testing.T) {
+	t.Parallel()
+	k := parse.JavaScriptKey{ApplicationID: defaultApplicationID}
+	ensure.Err(t, k.Modify(nil), regexp.MustCompile("empty JavaScriptKey"))
+}
+
 func TestEmptySessionToken(t *testing.T) {
 	t.Parallel()
 	var st parse.SessionToken
@@ -388,3 +404,287 @@ func TestSessionTokenModify(t *testing.T) {
 	ensure.DeepEqual(t, req.Header.Get("X-Parse-REST-API-Key"), st.RestAPIKey)
 	ensure.DeepEqual(t, req.Header.Get("X-Parse-Session-Token"), st.SessionToken)
 }
+
+func TestJavaScriptKeyModify(t *testing.T) {
+	t.Parallel()
+	k := parse.JavaScriptKey{ApplicationID: defaultApplicationID, JavaScriptKey: "42"}
+	var req http.Request
+	ensure.Nil(t, k.Modify(&req))
+	ensure.DeepEqual(t, req.Header.Get("X-Parse-Application-ID"), k.ApplicationID)
+	ensure.DeepEqual(t, req.Header.Get("X-Parse-Javascript-Key"), k.JavaScriptKey)
+	ensure.DeepEqual(t, req.Header.Get("X-Parse-REST-API-Key"), "")
+}
+
+func TestSessionTokenJavaScriptKeyModify(t *testing.T) {
+	t.Parallel()
+	st := parse.SessionToken{
+		ApplicationID: defaultApplicationID,
+		JavaScriptKey: "42",
+		SessionToken:  "43",
+	}
+	var req http.Request
+	ensure.Nil(t, st.Modify(&req))
+	ensure.DeepEqual(t, req.Header.Get("X-Parse-Javascript-Key"), st.JavaScriptKey)
+	ensure.DeepEqual(t, req.Header.Get("X-Parse-REST-API-Key"), "")
+	ensure.DeepEqual(t, req.Header.Get("X-Parse-Session-Token"), st.SessionToken)
+}
+
+type ctxKey string
+
+func TestDoCtx(t *testing.T) {
+	t.Parallel()
+	ctx := context.WithValue(context.Background(), ctxKey("yak"), "al")
+	var calls int
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			ensure.DeepEqual(t, r.Context().Value(ctxKey("yak")), "al")
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader("{}")),
+			}, nil
+		}),
+	}
+	_, err := c.GetCtx(ctx, &url.URL{Path: "a"}, nil)
+	ensure.Nil(t, err)
+	_, err = c.PostCtx(ctx, &url.URL{Path: "a"}, map[string]int{}, nil)
+	ensure.Nil(t, err)
+	_, err = c.PutCtx(ctx, &url.URL{Path: "a"}, map[string]int{}, nil)
+	ensure.Nil(t, err)
+	_, err = c.DeleteCtx(ctx, &url.URL{Path: "a"}, nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, calls, 4)
+}
+
+func TestDoCtxCancel(t *testing.T) {
+	t.Parallel()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		<-r.Context().Done()
+	}))
+	defer server.Close()
+	u, err := url.Parse(server.URL)
+	ensure.Nil(t, err)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &parse.Client{BaseURL: u}
+	done := make(chan error)
+	go func() {
+		_, err := c.DoCtx(ctx, &http.Request{Method: "GET"}, nil, nil)
+		done <- err
+	}()
+	cancel()
+	ensure.Err(t, <-done, regexp.MustCompile("context canceled"))
+}
+
+func TestWithContext(t *testing.T) {
+	t.Parallel()
+	ctx := context.WithValue(context.Background(), ctxKey("yak"), "al")
+	other := context.WithValue(context.Background(), ctxKey("yak"), "bo")
+	var want string
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Context().Value(ctxKey("yak")), want)
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader("{}")),
+			}, nil
+		}),
+	}
+	c2 := c.WithContext(ctx)
+	ensure.True(t, c != c2)
+
+	want = "al"
+	_, err := c2.Get(&url.URL{Path: "a"}, nil)
+	ensure.Nil(t, err)
+
+	want = "bo"
+	_, err = c2.GetCtx(other, &url.URL{Path: "a"}, nil)
+	ensure.Nil(t, err)
+}
+
+func TestTimeout(t *testing.T) {
+	t.Parallel()
+	release := make(chan struct{})
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path == "/slow" {
+			select {
+			case <-release:
+			case <-r.Context().Done():
+			}
+		}
+		w.Write([]byte(`{"answer":42}`))
+	}))
+	defer server.Close()
+	defer close(release)
+	u, err := url.Parse(server.URL)
+	ensure.Nil(t, err)
+
+	c := &parse.Client{BaseURL: u, Timeout: 10 * time.Millisecond}
+	_, err = c.Get(&url.URL{Path: "slow"}, nil)
+	ensure.Err(t, err, regexp.MustCompile("deadline exceeded"))
+
+	var res map[string]int
+	_, err = c.WithTimeout(time.Minute).Get(&url.URL{Path: "fast"}, &res)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res, map[string]int{"answer": 42})
+	ensure.DeepEqual(t, c.Timeout, 10*time.Millisecond)
+}
+
+func TestRequestNotModified(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Credentials: defaultRestAPIKey,
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.URL.String(), "https://api.parse.com/1/classes/Yak?limit=1")
+			ensure.DeepEqual(t, r.Header.Get("X-Parse-REST-API-Key"), defaultRestAPIKey.RestAPIKey)
+			return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+		}),
+	}
+	u := &url.URL{Path: "classes/Yak", RawQuery: "limit=1"}
+	req := &http.Request{Method: "GET", URL: u, Header: http.Header{"X-Yak": {"1"}}}
+
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(2)
+		go func() {
+			defer wg.Done()
+			_, err := c.Do(req, nil, nil)
+			ensure.Nil(t, err)
+		}()
+		go func() {
+			defer wg.Done()
+			res, err := c.RoundTrip(req)
+			ensure.Nil(t, err)
+			res.Body.Close()
+		}()
+	}
+	wg.Wait()
+	ensure.DeepEqual(t, u, &url.URL{Path: "classes/Yak", RawQuery: "limit=1"})
+	ensure.DeepEqual(t, req.Header, http.Header{"X-Yak": {"1"}})
+	ensure.DeepEqual(t, req.Proto, "")
+}
+
+func TestMaxErrorBody(t *testing.T) {
+	t.Parallel()
+	page := strings.Repeat("x", 100)
+	c := &parse.Client{
+		MaxErrorBody: 10,
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			return &http.Response{
+				StatusCode: http.StatusBadGateway,
+				Body:       ioutil.NopCloser(strings.NewReader(page)),
+			}, nil
+		}),
+	}
+	_, err := c.Get(nil, nil)
+	ensure.DeepEqual(t, string(err.(*parse.RawError).Body), "xxxxxxxxxx... (truncated)")
+
+	c.MaxErrorBody = 100
+	_, err = c.Get(nil, nil)
+	ensure.DeepEqual(t, string(err.(*parse.RawError).Body), page)
+}
+
+func benchmarkClient(b *testing.B, body []byte) *parse.Client {
+	return &parse.Client{
+		Credentials: defaultRestAPIKey,
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			if r.Body != nil {
+				io.Copy(ioutil.Discard, r.Body)
+			}
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(bytes.NewReader(body)),
+			}, nil
+		}),
+	}
+}
+
+func BenchmarkGet(b *testing.B) {
+	var results []map[string]interface{}
+	for i := 0; i < 100; i++ {
+		results = append(results, map[string]interface{}{
+			"objectId": "abcdefghij",
+			"name":     "Al",
+			"answer":   42,
+		})
+	}
+	c := benchmarkClient(b, jsonB(b, map[string]interface{}{"results": results}))
+	u := &url.URL{Path: "classes/Yak"}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var res struct {
+			Results []json.RawMessage `json:"results"`
+		}
+		if _, err := c.Get(u, &res); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func BenchmarkPost(b *testing.B) {
+	c := benchmarkClient(b, []byte(`{"objectId":"abcdefghij","createdAt":"2015-03-07T21:52:12.000Z"}`))
+	u := &url.URL{Path: "classes/Yak"}
+	body := map[string]interface{}{"name": "Al", "answer": 42}
+	b.ReportAllocs()
+	b.ResetTimer()
+	for i := 0; i < b.N; i++ {
+		var res parse.CreateResponse
+		if _, err := c.Post(u, body, &res); err != nil {
+			b.Fatal(err)
+		}
+	}
+}
+
+func TestDoRaw(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			if r.URL.Path == "/1/missing" {
+				return jsonResponse(t, http.StatusNotFound, map[string]interface{}{"code": 101, "error": "not found"}), nil
+			}
+			var body map[string]string
+			decodeBody(t, r, &body)
+			ensure.DeepEqual(t, body, map[string]string{"name": "Al"})
+			return &http.Response{
+				StatusCode: http.StatusOK,
+				Body:       ioutil.NopCloser(strings.NewReader("not json")),
+			}, nil
+		}),
+	}
+	body, err := c.DoRaw(&http.Request{Method: "POST", URL: &url.URL{Path: "export"}}, map[string]string{"name": "Al"})
+	ensure.Nil(t, err)
+	b, err := ioutil.ReadAll(body)
+	ensure.Nil(t, err)
+	ensure.Nil(t, body.Close())
+	ensure.DeepEqual(t, string(b), "not json")
+
+	_, err = c.DoRaw(&http.Request{Method: "GET", URL: &url.URL{Path: "missing"}}, nil)
+	ensure.True(t, parse.IsObjectNotFound(err))
+}
+
+func TestHeader(t *testing.T) {
+	t.Parallel()
+	var headers []http.Header
+	c := &parse.Client{
+		Header: http.Header{"X-Trace": {"t1"}, "X-Parse-Installation-Id": {"i1"}},
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			headers = append(headers, r.Header)
+			return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+		}),
+	}
+	_, err := c.Get(nil, nil)
+	ensure.Nil(t, err)
+	_, err = c.WithHeader("X-Parse-Revocable-Session", "1").Get(nil, nil)
+	ensure.Nil(t, err)
+	_, err = c.Do(&http.Request{Header: http.Header{"X-Trace": {"mine"}}}, nil, nil)
+	ensure.Nil(t, err)
+
+	ensure.DeepEqual(t, headers[0].Get("X-Trace"), "t1")
+	ensure.DeepEqual(t, headers[0].Get("X-Parse-Installation-Id"), "i1")
+	ensure.DeepEqual(t, headers[0].Get("X-Parse-Revocable-Session"), "")
+	ensure.DeepEqual(t, headers[1].Get("X-Parse-Revocable-Session"), "1")
+	ensure.DeepEqual(t, headers[1].Get("X-Trace"), "t1")
+	ensure.DeepEqual(t, headers[2].Get("X-Trace"), "mine")
+	ensure.DeepEqual(t, len(c.Header), 2)
+}
@@ -0,0 +1,247 @@
+package parsetest
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"reflect"
+	"strings"
+	"sync"
+	"testing"
+)
+
+// Request is a request captured by Capture.
+type Request struct {
+	Method string
+
+	// Path is the path of the URL, including the mount path of the API such
+	// as "/1/classes/Yak".
+	Path   string
+	Query  url.Values
+	Header http.Header
+
+	// Body is the JSON decoded body, or nil.
+	Body interface{}
+}
+
+// Capture is a RoundTripper that records the requests of a Client, for tests
+// to check with the Expect methods:
+//
+//	var cp parsetest.Capture
+//	c := &parse.Client{Transport: &cp}
+//	...
+//	cp.ExpectPost(t, "classes/Yak", parsetest.HasFields(map[string]interface{}{"name": "Al"}))
+//
+// Requests are passed on to Transport, or answered by Response when Transport
+// is nil.
+type Capture struct {
+	Transport http.RoundTripper
+
+	// Response optionally returns the status and the value encoded as the
+	// JSON body of the answer. Defaults to 200 with an empty object.
+	Response func(r *Request) (int, interface{})
+
+	mu       sync.Mutex
+	requests []*Request
+}
+
+// RoundTrip records the request and answers it.
+func (c *Capture) RoundTrip(req *http.Request) (*http.Response, error) {
+	r := &Request{
+		Method: req.Method,
+		Path:   req.URL.Path,
+		Query:  req.URL.Query(),
+		Header: req.Header.Clone(),
+	}
+	if req.Body != nil {
+		b, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(b))
+		if len(b) != 0 {
+			if err := json.Unmarshal(b, &r.Body); err != nil {
+				return nil, fmt.Errorf("parsetest: request body is not json: %s", err)
+			}
+		}
+	}
+	c.mu.Lock()
+	c.requests = append(c.requests, r)
+	c.mu.Unlock()
+
+	if c.Transport != nil {
+		return c.Transport.RoundTrip(req)
+	}
+	status, v := http.StatusOK, interface{}(map[string]interface{}{})
+	if c.Response != nil {
+		status, v = c.Response(r)
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	return &http.Response{
+		Status:        fmt.Sprintf("%d %s", status, http.StatusText(status)),
+		StatusCode:    status,
+		Proto:         "HTTP/1.1",
+		ProtoMajor:    1,
+		ProtoMinor:    1,
+		Header:        http.Header{"Content-Type": {"application/json"}},
+		Body:          ioutil.NopCloser(bytes.NewReader(b)),
+		ContentLength: int64(len(b)),
+		Request:       req,
+	}, nil
+}
+
+// Requests returns the requests captured so far.
+func (c *Capture) Requests() []*Request {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return append([]*Request(nil), c.requests...)
+}
+
+// Reset forgets the captured requests.
+func (c *Capture) Reset() {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.requests = nil
+}
+
+// Expect fails the test unless a request with the method and path was captured,
+// and returns the first one whose body matches. The path matches with or
+// without the mount path and trailing slash, so "classes/Yak" matches
+// "/1/classes/Yak/". A nil Matcher matches any body.
+func (c *Capture) Expect(t testing.TB, method, path string, m Matcher) *Request {
+	t.Helper()
+	var mismatches []string
+	for _, r := range c.Requests() {
+		if r.Method != method || !matchPath(r.Path, path) {
+			continue
+		}
+		if m == nil {
+			return r
+		}
+		err := m(r.Body)
+		if err == nil {
+			return r
+		}
+		mismatches = append(mismatches, err.Error())
+	}
+	if len(mismatches) == 0 {
+		t.Errorf("parsetest: no %s %s request, got %s", method, path, c.summary())
+	} else {
+		t.Errorf("parsetest: no matching %s %s request: %s", method, path, strings.Join(mismatches, "; "))
+	}
+	return nil
+}
+
+// ExpectGet is Expect for a GET request.
+func (c *Capture) ExpectGet(t testing.TB, path string) *Request {
+	t.Helper()
+	return c.Expect(t, "GET", path, nil)
+}
+
+// ExpectPost is Expect for a POST request.
+func (c *Capture) ExpectPost(t testing.TB, path string, m Matcher) *Request {
+	t.Helper()
+	return c.Expect(t, "POST", path, m)
+}
+
+// ExpectPut is Expect for a PUT request.
+func (c *Capture) ExpectPut(t testing.TB, path string, m Matcher) *Request {
+	t.Helper()
+	return c.Expect(t, "PUT", path, m)
+}
+
+// ExpectDelete is Expect for a DELETE request.
+func (c *Capture) ExpectDelete(t testing.TB, path string) *Request {
+	t.Helper()
+	return c.Expect(t, "DELETE", path, nil)
+}
+
+// ExpectNone fails the test if any request was captured.
+func (c *Capture) ExpectNone(t testing.TB) {
+	t.Helper()
+	if len(c.Requests()) != 0 {
+		t.Errorf("parsetest: expected no requests, got %s", c.summary())
+	}
+}
+
+func (c *Capture) summary() string {
+	reqs := c.Requests()
+	if len(reqs) == 0 {
+		return "none"
+	}
+	s := make([]string, len(reqs))
+	for n, r := range reqs {
+		s[n] = r.Method + " " + r.Path
+	}
+	return strings.Join(s, ", ")
+}
+
+func matchPath(got, want string) bool {
+	got, want = strings.Trim(got, "/"), strings.Trim(want, "/")
+	return got == want || strings.HasSuffix(got, "/"+want)
+}
+
+// Matcher checks a JSON decoded request body, returning why it doesn't match.
+type Matcher func(body interface{}) error
+
+// Equals matches a body equal to the JSON encoding of v.
+func Equals(v interface{}) Matcher {
+	return func(body interface{}) error {
+		want, err := normalize(v)
+		if err != nil {
+			return err
+		}
+		if !reflect.DeepEqual(body, want) {
+			return fmt.Errorf("body %s is not %s", encode(body), encode(want))
+		}
+		return nil
+	}
+}
+
+// HasFields matches an object body that has the fields with values equal to
+// their JSON encoding. Other fields are ignored.
+func HasFields(fields map[string]interface{}) Matcher {
+	return func(body interface{}) error {
+		o, ok := body.(map[string]interface{})
+		if !ok {
+			return fmt.Errorf("body %s is not an object", encode(body))
+		}
+		for k, v := range fields {
+			want, err := normalize(v)
+			if err != nil {
+				return err
+			}
+			got, ok := o[k]
+			if !ok {
+				return fmt.Errorf("body %s has no field %s", encode(body), k)
+			}
+			if !reflect.DeepEqual(got, want) {
+				return fmt.Errorf("field %s is %s, not %s", k, encode(got), encode(want))
+			}
+		}
+		return nil
+	}
+}
+
+// normalize returns v as decoded from its JSON encoding.
+func normalize(v interface{}) (interface{}, error) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	var n interface{}
+	err = json.Unmarshal(b, &n)
+	return n, err
+}
+
+func encode(v interface{}) string {
+	b, _ := json.Marshal(v)
+	return string(b)
+}
@@ -0,0 +1,70 @@
+package parsetest
+
+import (
+	"sync"
+	"time"
+)
+
+// Clock is a fake parse.Clock whose time only moves when advanced. The zero
+// value starts at the zero time, use NewClock to start at a given time.
+type Clock struct {
+	mu      sync.Mutex
+	now     time.Time
+	waiters []clockWaiter
+}
+
+type clockWaiter struct {
+	deadline time.Time
+	c        chan time.Time
+}
+
+// NewClock returns a Clock set to the time.
+func NewClock(now time.Time) *Clock {
+	return &Clock{now: now}
+}
+
+// Now returns the time of the Clock.
+func (c *Clock) Now() time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return c.now
+}
+
+// After returns a channel that receives the time once the Clock has been
+// advanced by d.
+func (c *Clock) After(d time.Duration) <-chan time.Time {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	ch := make(chan time.Time, 1)
+	if d <= 0 {
+		ch <- c.now
+		return ch
+	}
+	c.waiters = append(c.waiters, clockWaiter{deadline: c.now.Add(d), c: ch})
+	return ch
+}
+
+// Advance moves the time forward by d, firing the channels of After that are
+// due.
+func (c *Clock) Advance(d time.Duration) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.now = c.now.Add(d)
+	waiters := c.waiters[:0]
+	for _, w := range c.waiters {
+		if w.deadline.After(c.now) {
+			waiters = append(waiters, w)
+			continue
+		}
+		w.c <- c.now
+	}
+	c.waiters = waiters
+}
+
+// Waiters returns the number of pending After channels, so a test can wait
+// for code under test to start waiting before advancing the Clock.
+func (c *Clock) Waiters() int {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	return len(c.waiters)
+}
@@ -0,0 +1,240 @@
+package parsetest
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"sync"
+
+	"github.com/facebookgo/parse"
+)
+
+var errNotStubbed = errors.New("parsetest: call not stubbed")
+
+// Call is a call made to a Mock.
+type Call struct {
+	Method string
+	URL    *url.URL
+
+	// Body is the JSON encoded body, or nil.
+	Body json.RawMessage
+}
+
+// Mock implements parse.API without a server. Every call is recorded and
+// answered by Handler, whose value is JSON encoded and decoded into the
+// result, so the result looks like one decoded from a real response. Return a
+// *parse.Error to fail the call.
+type Mock struct {
+	Handler func(c Call) (interface{}, error)
+
+	mu    sync.Mutex
+	calls []Call
+}
+
+// Calls returns the calls made so far.
+func (m *Mock) Calls() []Call {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return append([]Call(nil), m.calls...)
+}
+
+// Do records and answers the call.
+func (m *Mock) Do(req *http.Request, body, result interface{}) (*http.Response, error) {
+	c := Call{Method: req.Method, URL: req.URL}
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return nil, err
+		}
+		c.Body = b
+	}
+	m.mu.Lock()
+	m.calls = append(m.calls, c)
+	m.mu.Unlock()
+
+	if m.Handler == nil {
+		return nil, fmt.Errorf("%s: %s %s", errNotStubbed, c.Method, c.URL)
+	}
+	v, err := m.Handler(c)
+	if err != nil {
+		return nil, err
+	}
+	b, err := json.Marshal(v)
+	if err != nil {
+		return nil, err
+	}
+	if result != nil {
+		if err := json.Unmarshal(b, result); err != nil {
+			return nil, err
+		}
+	}
+	return &http.Response{
+		Status:     "200 OK",
+		StatusCode: http.StatusOK,
+		Header:     http.Header{"Content-Type": {"application/json"}},
+		Body:       ioutil.NopCloser(bytes.NewReader(b)),
+		Request:    req,
+	}, nil
+}
+
+// DoCtx is Do with a context.
+func (m *Mock) DoCtx(ctx context.Context, req *http.Request, body, result interface{}) (*http.Response, error) {
+	return m.Do(req.WithContext(ctx), body, result)
+}
+
+// Get is Do with a GET request.
+func (m *Mock) Get(u *url.URL, result interface{}) (*http.Response, error) {
+	return m.Do(&http.Request{Method: "GET", URL: u}, nil, result)
+}
+
+// Post is Do with a POST request.
+func (m *Mock) Post(u *url.URL, body, result interface{}) (*http.Response, error) {
+	return m.Do(&http.Request{Method: "POST", URL: u}, body, result)
+}
+
+// Put is Do with a PUT request.
+func (m *Mock) Put(u *url.URL, body, result interface{}) (*http.Response, error) {
+	return m.Do(&http.Request{Method: "PUT", URL: u}, body, result)
+}
+
+// Delete is Do with a DELETE request.
+func (m *Mock) Delete(u *url.URL, result interface{}) (*http.Response, error) {
+	return m.Do(&http.Request{Method: "DELETE", URL: u}, nil, result)
+}
+
+// GetCtx is Get with a context.
+func (m *Mock) GetCtx(ctx context.Context, u *url.URL, result interface{}) (*http.Response, error) {
+	return m.DoCtx(ctx, &http.Request{Method: "GET", URL: u}, nil, result)
+}
+
+// PostCtx is Post with a context.
+func (m *Mock) PostCtx(ctx context.Context, u *url.URL, body, result interface{}) (*http.Response, error) {
+	return m.DoCtx(ctx, &http.Request{Method: "POST", URL: u}, body, result)
+}
+
+// PutCtx is Put with a context.
+func (m *Mock) PutCtx(ctx context.Context, u *url.URL, body, result interface{}) (*http.Response, error) {
+	return m.DoCtx(ctx, &http.Request{Method: "PUT", URL: u}, body, result)
+}
+
+// DeleteCtx is Delete with a context.
+func (m *Mock) DeleteCtx(ctx context.Context, u *url.URL, result interface{}) (*http.Response, error) {
+	return m.DoCtx(ctx, &http.Request{Method: "DELETE", URL: u}, nil, result)
+}
+
+// MockObjects implements parse.Objects with a function per operation. Calls
+// to operations without a function fail. The context of the Ctx methods is
+// not used.
+type MockObjects struct {
+	GetFunc    func(id string, result interface{}) error
+	PostFunc   func(v interface{}) (*parse.CreateResponse, error)
+	PutFunc    func(id string, v interface{}) (*parse.UpdateResponse, error)
+	DeleteFunc func(id string) error
+	CountFunc  func(where interface{}) (int, error)
+	QueryFunc  func(p *parse.Params, result interface{}) error
+
+	// GetWithOptionsFunc is used by GetWithOptions, which falls back to
+	// GetFunc when it is nil.
+	GetWithOptionsFunc func(id string, opt *parse.GetOptions, result interface{}) error
+}
+
+// Get calls GetFunc.
+func (m *MockObjects) Get(id string, result interface{}) (*http.Response, error) {
+	if m.GetFunc == nil {
+		return nil, errNotStubbed
+	}
+	return nil, m.GetFunc(id, result)
+}
+
+// GetCtx calls GetFunc.
+func (m *MockObjects) GetCtx(ctx context.Context, id string, result interface{}) (*http.Response, error) {
+	return m.Get(id, result)
+}
+
+// GetWithOptions calls GetWithOptionsFunc, or GetFunc.
+func (m *MockObjects) GetWithOptions(id string, opt *parse.GetOptions, result interface{}) (*http.Response, error) {
+	if m.GetWithOptionsFunc == nil {
+		return m.Get(id, result)
+	}
+	return nil, m.GetWithOptionsFunc(id, opt, result)
+}
+
+// GetWithOptionsCtx calls GetWithOptionsFunc, or GetFunc.
+func (m *MockObjects) GetWithOptionsCtx(ctx context.Context, id string, opt *parse.GetOptions, result interface{}) (*http.Response, error) {
+	return m.GetWithOptions(id, opt, result)
+}
+
+// Post calls PostFunc.
+func (m *MockObjects) Post(v interface{}) (*parse.CreateResponse, error) {
+	if m.PostFunc == nil {
+		return nil, errNotStubbed
+	}
+	return m.PostFunc(v)
+}
+
+// PostCtx calls PostFunc.
+func (m *MockObjects) PostCtx(ctx context.Context, v interface{}) (*parse.CreateResponse, error) {
+	return m.Post(v)
+}
+
+// Put calls PutFunc.
+func (m *MockObjects) Put(id string, v interface{}) (*parse.UpdateResponse, error) {
+	if m.PutFunc == nil {
+		return nil, errNotStubbed
+	}
+	return m.PutFunc(id, v)
+}
+
+// PutCtx calls PutFunc.
+func (m *MockObjects) PutCtx(ctx context.Context, id string, v interface{}) (*parse.UpdateResponse, error) {
+	return m.Put(id, v)
+}
+
+// Delete calls DeleteFunc.
+func (m *MockObjects) Delete(id string) (*http.Response, error) {
+	if m.DeleteFunc == nil {
+		return nil, errNotStubbed
+	}
+	return nil, m.DeleteFunc(id)
+}
+
+// DeleteCtx calls DeleteFunc.
+func (m *MockObjects) DeleteCtx(ctx context.Context, id string) (*http.Response, error) {
+	return m.Delete(id)
+}
+
+// Count calls CountFunc.
+func (m *MockObjects) Count(where interface{}) (int, error) {
+	if m.CountFunc == nil {
+		return 0, errNotStubbed
+	}
+	return m.CountFunc(where)
+}
+
+// CountCtx calls CountFunc.
+func (m *MockObjects) CountCtx(ctx context.Context, where interface{}) (int, error) {
+	return m.Count(where)
+}
+
+// Query calls QueryFunc.
+func (m *MockObjects) Query(p *parse.Params, result interface{}) (*http.Response, error) {
+	if m.QueryFunc == nil {
+		return nil, errNotStubbed
+	}
+	return nil, m.QueryFunc(p, result)
+}
+
+// QueryCtx calls QueryFunc.
+func (m *MockObjects) QueryCtx(ctx context.Context, p *parse.Params, result interface{}) (*http.Response, error) {
+	return m.Query(p, result)
+}
+
+var (
+	_ parse.API     = (*Mock)(nil)
+	_ parse.Objects = (*MockObjects)(nil)
+)
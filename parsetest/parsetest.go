@@ -0,0 +1,460 @@
+// Package parsetest provides an in-memory fake of the Parse API for tests.
+// It answers real HTTP requests, so tests exercise the Client the same way
+// production code does:
+//
+//	s := parsetest.NewServer()
+//	defer s.Close()
+//	c := s.Client()
+//
+// The Server supports creating, fetching, updating, deleting and querying
+// objects, the Add, AddUnique, Remove, Increment and Delete operations, user
+// sign up, login, logout and users/me, and batch requests. ACLs, class level
+// permissions, schemas and Cloud Code are not implemented.
+//
+// Recorder records interactions with a live server to a fixture file and
+// replays them, for integration tests that run without network access. Mock
+// and MockObjects implement the parse.API and parse.Objects interfaces, for
+// stubbing calls without a server. Capture records the requests of a Client
+// for assertions, and Clock is a fake parse.Clock.
+package parsetest
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/facebookgo/parse"
+)
+
+// The credentials accepted by a Server made by NewServer.
+const (
+	ApplicationID = "parsetest-app"
+	RestAPIKey    = "parsetest-rest-key"
+	MasterKey     = "parsetest-master-key"
+)
+
+// MountPath is the path the API is served under.
+const MountPath = "/1/"
+
+const dateLayout = "2006-01-02T15:04:05.000Z"
+
+// Server is a fake Parse server.
+type Server struct {
+	*httptest.Server
+
+	mu       sync.Mutex
+	classes  map[string]map[string]map[string]interface{}
+	sessions map[string]string
+}
+
+// apiError is an error answered to a request.
+type apiError struct {
+	status int
+	code   int
+	msg    string
+}
+
+func (e *apiError) Error() string {
+	return e.msg
+}
+
+func errorf(status, code int, format string, args ...interface{}) *apiError {
+	return &apiError{status: status, code: code, msg: fmt.Sprintf(format, args...)}
+}
+
+// NewServer starts a Server. Close it when done.
+func NewServer() *Server {
+	s := &Server{
+		classes:  make(map[string]map[string]map[string]interface{}),
+		sessions: make(map[string]string),
+	}
+	s.Server = httptest.NewServer(http.HandlerFunc(s.serveHTTP))
+	return s
+}
+
+// Client returns a Client for the Server using the Rest API Key.
+func (s *Server) Client() *parse.Client {
+	return &parse.Client{
+		BaseURL: s.BaseURL(),
+		Credentials: parse.RestAPIKey{
+			ApplicationID: ApplicationID,
+			RestAPIKey:    RestAPIKey,
+		},
+	}
+}
+
+// BaseURL returns the URL of the API, for Client.BaseURL.
+func (s *Server) BaseURL() *url.URL {
+	u, err := url.Parse(s.URL + MountPath)
+	if err != nil {
+		panic(err)
+	}
+	return u
+}
+
+// Objects returns a copy of the objects of the class, by objectId.
+func (s *Server) Objects(className string) map[string]map[string]interface{} {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	objects := make(map[string]map[string]interface{})
+	for id, o := range s.classes[className] {
+		objects[id] = copyObject(o)
+	}
+	return objects
+}
+
+func (s *Server) serveHTTP(w http.ResponseWriter, r *http.Request) {
+	res, err := s.serve(r)
+	if err != nil {
+		e, ok := err.(*apiError)
+		if !ok {
+			e = errorf(http.StatusInternalServerError, parse.CodeInternalServerError, "%s", err)
+		}
+		writeJSON(w, e.status, map[string]interface{}{"code": e.code, "error": e.msg})
+		return
+	}
+	status := http.StatusOK
+	if r.Method == "POST" && res.created {
+		status = http.StatusCreated
+	}
+	writeJSON(w, status, res.body)
+}
+
+func writeJSON(w http.ResponseWriter, status int, v interface{}) {
+	b, err := json.Marshal(v)
+	if err != nil {
+		http.Error(w, err.Error(), http.StatusInternalServerError)
+		return
+	}
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	w.Write(b)
+}
+
+type result struct {
+	body    interface{}
+	created bool
+}
+
+func (s *Server) serve(r *http.Request) (*result, error) {
+	if !strings.HasPrefix(r.URL.Path, MountPath) {
+		return nil, errorf(http.StatusNotFound, parse.CodeInvalidJSON, "not found")
+	}
+	if r.Header.Get("X-Parse-Application-Id") != ApplicationID ||
+		(r.Header.Get("X-Parse-REST-API-Key") != RestAPIKey && r.Header.Get("X-Parse-Master-Key") != MasterKey) {
+		return nil, &apiError{status: http.StatusUnauthorized, msg: "unauthorized"}
+	}
+	var body map[string]interface{}
+	if r.Body != nil {
+		b, err := ioutil.ReadAll(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		if len(b) != 0 {
+			if err := json.Unmarshal(b, &body); err != nil {
+				return nil, errorf(http.StatusBadRequest, parse.CodeInvalidJSON, "invalid JSON")
+			}
+		}
+	}
+	method := r.Method
+	query := r.URL.Query()
+	if method == "POST" && body["_method"] == "GET" {
+		method = "GET"
+		query = make(url.Values)
+		for k, v := range body {
+			if s, ok := v.(string); ok && k != "_method" {
+				query.Set(k, s)
+			}
+		}
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	path := strings.TrimPrefix(r.URL.Path, MountPath)
+	if path == "batch" && method == "POST" {
+		return s.batch(r.Header, body)
+	}
+	return s.route(method, path, query, r.Header, body)
+}
+
+func (s *Server) route(method, path string, query url.Values, h http.Header, body map[string]interface{}) (*result, error) {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	switch {
+	case parts[0] == "classes" && len(parts) == 2:
+		switch method {
+		case "GET":
+			return s.query(parts[1], query)
+		case "POST":
+			return s.create(parts[1], body)
+		}
+	case parts[0] == "classes" && len(parts) == 3:
+		return s.object(method, parts[1], parts[2], query, body)
+	case path == "users" && method == "POST":
+		return s.signUp(body)
+	case path == "users" && method == "GET":
+		return s.query("_User", query)
+	case path == "users/me" && method == "GET":
+		return s.me(h)
+	case parts[0] == "users" && len(parts) == 2:
+		return s.object(method, "_User", parts[1], query, body)
+	case path == "login" && method == "GET":
+		return s.login(query.Get("username"), query.Get("password"))
+	case path == "logout" && method == "POST":
+		delete(s.sessions, h.Get("X-Parse-Session-Token"))
+		return &result{body: map[string]interface{}{}}, nil
+	}
+	return nil, errorf(http.StatusNotFound, parse.CodeInvalidJSON, "unsupported request %s %s", method, path)
+}
+
+func newID() string {
+	var b [5]byte
+	if _, err := rand.Read(b[:]); err != nil {
+		panic(err)
+	}
+	return hex.EncodeToString(b[:])
+}
+
+func now() string {
+	return time.Now().UTC().Format(dateLayout)
+}
+
+func copyObject(o map[string]interface{}) map[string]interface{} {
+	c := make(map[string]interface{}, len(o))
+	for k, v := range o {
+		c[k] = v
+	}
+	return c
+}
+
+func (s *Server) class(className string) map[string]map[string]interface{} {
+	c, ok := s.classes[className]
+	if !ok {
+		c = make(map[string]map[string]interface{})
+		s.classes[className] = c
+	}
+	return c
+}
+
+func (s *Server) create(className string, body map[string]interface{}) (*result, error) {
+	o := make(map[string]interface{})
+	if err := applyFields(o, body); err != nil {
+		return nil, err
+	}
+	id, ok := body["objectId"].(string)
+	if !ok || id == "" {
+		id = newID()
+	} else if _, exists := s.class(className)[id]; exists {
+		return nil, errorf(http.StatusBadRequest, parse.CodeDuplicateValue, "A duplicate value for a field with unique values was provided")
+	}
+	t := now()
+	o["objectId"] = id
+	o["createdAt"] = t
+	o["updatedAt"] = t
+	s.class(className)[id] = o
+	return &result{
+		body:    map[string]interface{}{"objectId": id, "createdAt": t},
+		created: true,
+	}, nil
+}
+
+func (s *Server) object(method, className, id string, query url.Values, body map[string]interface{}) (*result, error) {
+	o, ok := s.classes[className][id]
+	if !ok {
+		return nil, errorf(http.StatusNotFound, parse.CodeObjectNotFound, "Object not found.")
+	}
+	switch method {
+	case "GET":
+		var keys []string
+		if k := query.Get("keys"); k != "" {
+			keys = strings.Split(k, ",")
+		}
+		return &result{body: project(public(o), keys)}, nil
+	case "PUT":
+		updated := copyObject(o)
+		if err := applyFields(updated, body); err != nil {
+			return nil, err
+		}
+		t := now()
+		updated["updatedAt"] = t
+		s.classes[className][id] = updated
+		return &result{body: map[string]interface{}{"updatedAt": t}}, nil
+	case "DELETE":
+		delete(s.classes[className], id)
+		return &result{body: map[string]interface{}{}}, nil
+	}
+	return nil, errorf(http.StatusMethodNotAllowed, parse.CodeInvalidJSON, "unsupported method %s", method)
+}
+
+// public leaves out the fields that are never returned.
+func public(o map[string]interface{}) map[string]interface{} {
+	c := copyObject(o)
+	delete(c, "password")
+	return c
+}
+
+// applyFields sets the fields of the body on the object, applying the
+// operations.
+func applyFields(o, body map[string]interface{}) error {
+	for k, v := range body {
+		switch k {
+		case "objectId", "createdAt", "updatedAt":
+			continue
+		}
+		if strings.HasPrefix(k, "_") || strings.ContainsAny(k, ".$") {
+			return errorf(http.StatusBadRequest, parse.CodeInvalidKeyName, "Invalid field name: %s.", k)
+		}
+		op, ok := v.(map[string]interface{})
+		if !ok {
+			o[k] = v
+			continue
+		}
+		name, ok := op["__op"].(string)
+		if !ok {
+			o[k] = v
+			continue
+		}
+		if err := applyOp(o, k, name, op); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func applyOp(o map[string]interface{}, k, name string, op map[string]interface{}) error {
+	switch name {
+	case "Delete":
+		delete(o, k)
+		return nil
+	case "Increment":
+		n, ok := op["amount"].(float64)
+		if !ok {
+			return errorf(http.StatusBadRequest, parse.CodeInvalidJSON, "Increment amount must be a number")
+		}
+		current, _ := o[k].(float64)
+		o[k] = current + n
+		return nil
+	}
+	objects, ok := op["objects"].([]interface{})
+	if !ok {
+		return errorf(http.StatusBadRequest, parse.CodeInvalidJSON, "%s objects must be an array", name)
+	}
+	current, _ := o[k].([]interface{})
+	switch name {
+	case "Add":
+		o[k] = append(append([]interface{}{}, current...), objects...)
+	case "AddUnique":
+		next := append([]interface{}{}, current...)
+		for _, v := range objects {
+			if !contains(next, v) {
+				next = append(next, v)
+			}
+		}
+		o[k] = next
+	case "Remove":
+		next := []interface{}{}
+		for _, v := range current {
+			if !contains(objects, v) {
+				next = append(next, v)
+			}
+		}
+		o[k] = next
+	default:
+		return errorf(http.StatusBadRequest, parse.CodeInvalidJSON, "unsupported operation %s", name)
+	}
+	return nil
+}
+
+func (s *Server) signUp(body map[string]interface{}) (*result, error) {
+	username, _ := body["username"].(string)
+	password, _ := body["password"].(string)
+	if username == "" {
+		return nil, errorf(http.StatusBadRequest, parse.CodeUsernameMissing, "bad or missing username")
+	}
+	if password == "" {
+		return nil, errorf(http.StatusBadRequest, parse.CodePasswordMissing, "password is required")
+	}
+	if s.findUser(username) != nil {
+		return nil, errorf(http.StatusBadRequest, parse.CodeUsernameTaken, "username %s already taken", username)
+	}
+	// The password is set once the user is created, and public leaves it out
+	// of responses. Like on the API, an update of the user can change it.
+	fields := copyObject(body)
+	delete(fields, "password")
+	res, err := s.create("_User", fields)
+	if err != nil {
+		return nil, err
+	}
+	created := res.body.(map[string]interface{})
+	id := created["objectId"].(string)
+	s.classes["_User"][id]["password"] = password
+	token := "r:" + newID() + newID()
+	s.sessions[token] = id
+	created["sessionToken"] = token
+	return res, nil
+}
+
+func (s *Server) findUser(username string) map[string]interface{} {
+	for _, u := range s.classes["_User"] {
+		if u["username"] == username {
+			return u
+		}
+	}
+	return nil
+}
+
+func (s *Server) login(username, password string) (*result, error) {
+	u := s.findUser(username)
+	if u == nil || u["password"] != password {
+		return nil, errorf(http.StatusNotFound, parse.CodeObjectNotFound, "Invalid username/password.")
+	}
+	token := "r:" + newID() + newID()
+	s.sessions[token] = u["objectId"].(string)
+	res := public(u)
+	res["sessionToken"] = token
+	return &result{body: res}, nil
+}
+
+func (s *Server) me(h http.Header) (*result, error) {
+	token := h.Get("X-Parse-Session-Token")
+	id, ok := s.sessions[token]
+	u := s.classes["_User"][id]
+	if !ok || u == nil {
+		return nil, errorf(http.StatusBadRequest, parse.CodeInvalidSessionToken, "Invalid session token")
+	}
+	res := public(u)
+	res["sessionToken"] = token
+	return &result{body: res}, nil
+}
+
+func (s *Server) batch(h http.Header, body map[string]interface{}) (*result, error) {
+	reqs, ok := body["requests"].([]interface{})
+	if !ok {
+		return nil, errorf(http.StatusBadRequest, parse.CodeInvalidJSON, "requests must be an array")
+	}
+	results := make([]interface{}, len(reqs))
+	for i, raw := range reqs {
+		req, _ := raw.(map[string]interface{})
+		method, _ := req["method"].(string)
+		path, _ := req["path"].(string)
+		reqBody, _ := req["body"].(map[string]interface{})
+		res, err := s.route(strings.ToUpper(method), strings.TrimPrefix(path, MountPath), nil, h, reqBody)
+		if err != nil {
+			e, ok := err.(*apiError)
+			if !ok {
+				e = errorf(http.StatusInternalServerError, parse.CodeInternalServerError, "%s", err)
+			}
+			results[i] = map[string]interface{}{"error": map[string]interface{}{"code": e.code, "error": e.msg}}
+			continue
+		}
+		results[i] = map[string]interface{}{"success": res.body}
+	}
+	return &result{body: results}, nil
+}
@@ -0,0 +1,313 @@
+package parsetest_test
+
+import (
+	"fmt"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"os"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+	"github.com/facebookgo/parse/parsetest"
+)
+
+type gameScore struct {
+	ID      string   `json:"objectId,omitempty"`
+	Player  string   `json:"player,omitempty"`
+	Score   int      `json:"score,omitempty"`
+	Tags    []string `json:"tags,omitempty"`
+	Cheater bool     `json:"cheater,omitempty"`
+}
+
+func TestObjectCRUD(t *testing.T) {
+	t.Parallel()
+	s := parsetest.NewServer()
+	defer s.Close()
+	o := parse.ObjectClient{Client: s.Client(), Path: "classes/GameScore"}
+
+	created, err := o.Post(gameScore{Player: "sean", Score: 10, Tags: []string{"a"}})
+	ensure.Nil(t, err)
+	ensure.True(t, created.ID != "")
+	ensure.False(t, created.CreatedAt.IsZero())
+
+	_, err = o.Put(created.ID, map[string]interface{}{
+		"score": parse.Increment(5),
+		"tags":  parse.AddUnique{"a", "b"},
+	})
+	ensure.Nil(t, err)
+
+	var g gameScore
+	_, err = o.Get(created.ID, &g)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, g, gameScore{ID: created.ID, Player: "sean", Score: 15, Tags: []string{"a", "b"}})
+	ensure.DeepEqual(t, len(s.Objects("GameScore")), 1)
+
+	var partial gameScore
+	_, err = o.GetWithOptions(created.ID, &parse.GetOptions{Keys: []string{"score"}}, &partial)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, partial, gameScore{ID: created.ID, Score: 15})
+
+	_, err = o.Delete(created.ID)
+	ensure.Nil(t, err)
+	_, err = o.Get(created.ID, &g)
+	ensure.True(t, parse.IsObjectNotFound(err), err)
+}
+
+func TestQuery(t *testing.T) {
+	t.Parallel()
+	s := parsetest.NewServer()
+	defer s.Close()
+	o := parse.ObjectClient{Client: s.Client(), Path: "classes/GameScore"}
+	for i, name := range []string{"a", "b", "c", "d"} {
+		_, err := o.Post(gameScore{Player: name, Score: i * 10, Cheater: name == "c"})
+		ensure.Nil(t, err)
+	}
+
+	var res struct {
+		Results []gameScore `json:"results"`
+		Count   int         `json:"count"`
+	}
+	_, err := o.Query(&parse.Params{
+		Where: map[string]interface{}{
+			"score":   map[string]interface{}{"$gte": 10},
+			"cheater": map[string]interface{}{"$ne": true},
+		},
+		Order: []string{"-score"},
+		Keys:  []string{"player"},
+		Limit: 1,
+		Count: true,
+	}, &res)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res.Count, 2)
+	ensure.DeepEqual(t, len(res.Results), 1)
+	ensure.DeepEqual(t, res.Results[0].Player, "d")
+	ensure.DeepEqual(t, res.Results[0].Score, 0)
+
+	n, err := o.Count(map[string]interface{}{"player": map[string]interface{}{"$in": []string{"a", "b", "z"}}})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, n, 2)
+}
+
+func TestUsers(t *testing.T) {
+	t.Parallel()
+	s := parsetest.NewServer()
+	defer s.Close()
+	c := s.Client()
+
+	user := map[string]string{"username": "sean", "password": "secret"}
+	var signedUp struct {
+		ID           string `json:"objectId"`
+		SessionToken string `json:"sessionToken"`
+	}
+	_, err := c.Post(&url.URL{Path: "users"}, user, &signedUp)
+	ensure.Nil(t, err)
+	ensure.True(t, signedUp.SessionToken != "")
+
+	_, err = c.Post(&url.URL{Path: "users"}, user, nil)
+	ensure.True(t, parse.IsUsernameTaken(err), err)
+
+	var loggedIn struct {
+		SessionToken string `json:"sessionToken"`
+		Password     string `json:"password"`
+	}
+	_, err = c.Get(&url.URL{Path: "login", RawQuery: "username=sean&password=secret"}, &loggedIn)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, loggedIn.Password, "")
+
+	_, err = c.Get(&url.URL{Path: "login", RawQuery: "username=sean&password=wrong"}, nil)
+	ensure.True(t, parse.IsObjectNotFound(err), err)
+
+	sc := &parse.Client{
+		BaseURL: s.BaseURL(),
+		Credentials: parse.SessionToken{
+			ApplicationID: parsetest.ApplicationID,
+			RestAPIKey:    parsetest.RestAPIKey,
+			SessionToken:  loggedIn.SessionToken,
+		},
+	}
+	var me struct {
+		ID string `json:"objectId"`
+	}
+	_, err = sc.Get(&url.URL{Path: "users/me"}, &me)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, me.ID, signedUp.ID)
+
+	_, err = sc.Post(&url.URL{Path: "logout"}, nil, nil)
+	ensure.Nil(t, err)
+	_, err = sc.Get(&url.URL{Path: "users/me"}, &me)
+	ensure.True(t, parse.IsInvalidSessionToken(err), err)
+}
+
+func TestBatch(t *testing.T) {
+	t.Parallel()
+	s := parsetest.NewServer()
+	defer s.Close()
+	c := s.Client()
+
+	res, err := c.Batch([]parse.BatchRequest{
+		{Method: "POST", Path: "classes/GameScore", Body: gameScore{Player: "a"}},
+		{Method: "DELETE", Path: "classes/GameScore/missing"},
+		{Method: "POST", Path: "classes/GameScore", Body: gameScore{Player: "b"}},
+	})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(res), 3)
+	ensure.True(t, res[0].Success != nil)
+	ensure.DeepEqual(t, res[1].Error.Code, parse.CodeObjectNotFound)
+	ensure.True(t, res[2].Success != nil)
+	ensure.DeepEqual(t, len(s.Objects("GameScore")), 2)
+}
+
+func TestUnauthorized(t *testing.T) {
+	t.Parallel()
+	s := parsetest.NewServer()
+	defer s.Close()
+	c := &parse.Client{
+		BaseURL:     s.BaseURL(),
+		Credentials: parse.RestAPIKey{ApplicationID: parsetest.ApplicationID, RestAPIKey: "wrong"},
+	}
+	_, err := c.Get(&url.URL{Path: "classes/GameScore"}, nil)
+	ensure.NotNil(t, err)
+}
+
+func TestRecorder(t *testing.T) {
+	t.Parallel()
+	s := parsetest.NewServer()
+	defer s.Close()
+	f, err := ioutil.TempFile("", "parsetest-")
+	ensure.Nil(t, err)
+	f.Close()
+	defer os.Remove(f.Name())
+
+	run := func(r *parsetest.Recorder) (string, error) {
+		c := s.Client()
+		c.Transport = r
+		var res struct {
+			SessionToken string `json:"sessionToken"`
+		}
+		_, err := c.Post(&url.URL{Path: "users"}, map[string]string{"username": "sean", "password": "secret"}, &res)
+		return res.SessionToken, err
+	}
+
+	rec := &parsetest.Recorder{Path: f.Name(), Mode: parsetest.ModeRecord}
+	token, err := run(rec)
+	ensure.Nil(t, err)
+	ensure.Nil(t, rec.Close())
+
+	b, err := ioutil.ReadFile(f.Name())
+	ensure.Nil(t, err)
+	ensure.False(t, strings.Contains(string(b), "secret"))
+	ensure.False(t, strings.Contains(string(b), token))
+	ensure.False(t, strings.Contains(string(b), parsetest.RestAPIKey))
+
+	s.Close()
+	replay := &parsetest.Recorder{Path: f.Name()}
+	token, err = run(replay)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, token, "SCRUBBED")
+	ensure.DeepEqual(t, len(replay.Unused()), 0)
+
+	_, err = run(replay)
+	ensure.Err(t, err, regexp.MustCompile("no recorded interaction for POST /1/users"))
+}
+
+func TestMock(t *testing.T) {
+	t.Parallel()
+	m := &parsetest.Mock{
+		Handler: func(c parsetest.Call) (interface{}, error) {
+			if c.URL.Path == "classes/GameScore/missing" {
+				return nil, &parse.Error{Code: parse.CodeObjectNotFound}
+			}
+			return gameScore{ID: "a", Score: 3}, nil
+		},
+	}
+	var api parse.API = m
+	var g gameScore
+	_, err := api.Post(&url.URL{Path: "classes/GameScore"}, gameScore{Score: 3}, &g)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, g, gameScore{ID: "a", Score: 3})
+	_, err = api.Get(&url.URL{Path: "classes/GameScore/missing"}, &g)
+	ensure.True(t, parse.IsObjectNotFound(err))
+
+	calls := m.Calls()
+	ensure.DeepEqual(t, len(calls), 2)
+	ensure.DeepEqual(t, calls[0].Method, "POST")
+	ensure.DeepEqual(t, string(calls[0].Body), `{"score":3}`)
+	ensure.True(t, calls[1].Body == nil)
+}
+
+func TestMockObjects(t *testing.T) {
+	t.Parallel()
+	var o parse.Objects = &parsetest.MockObjects{
+		CountFunc: func(where interface{}) (int, error) { return 7, nil },
+	}
+	n, err := o.Count(nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, n, 7)
+	_, err = o.Get("a", nil)
+	ensure.Err(t, err, regexp.MustCompile("not stubbed"))
+}
+
+func TestCapture(t *testing.T) {
+	t.Parallel()
+	cp := &parsetest.Capture{
+		Response: func(r *parsetest.Request) (int, interface{}) {
+			return http.StatusCreated, map[string]string{"objectId": "a"}
+		},
+	}
+	o := parse.ObjectClient{Client: &parse.Client{Transport: cp}, Path: "classes/Yak"}
+	res, err := o.Post(map[string]interface{}{"name": "Al", "age": 3, "tags": parse.Add{"x"}})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res.ID, "a")
+
+	r := cp.ExpectPost(t, "classes/Yak", parsetest.HasFields(map[string]interface{}{
+		"name": "Al",
+		"tags": parse.Add{"x"},
+	}))
+	ensure.DeepEqual(t, r.Path, "/1/classes/Yak/")
+	cp.ExpectPost(t, "/1/classes/Yak", parsetest.Equals(map[string]interface{}{
+		"name": "Al",
+		"age":  3,
+		"tags": map[string]interface{}{"__op": "Add", "objects": []string{"x"}},
+	}))
+
+	var ft fakeT
+	cp.ExpectPost(&ft, "classes/Yak", parsetest.HasFields(map[string]interface{}{"name": "Bo"}))
+	cp.ExpectDelete(&ft, "classes/Yak/a")
+	ensure.DeepEqual(t, ft.errors, []string{
+		`parsetest: no matching POST classes/Yak request: field name is "Al", not "Bo"`,
+		"parsetest: no DELETE classes/Yak/a request, got POST /1/classes/Yak/",
+	})
+
+	cp.Reset()
+	cp.ExpectNone(t)
+}
+
+func TestCaptureTransport(t *testing.T) {
+	t.Parallel()
+	s := parsetest.NewServer()
+	defer s.Close()
+	c := s.Client()
+	cp := &parsetest.Capture{Transport: http.DefaultTransport}
+	c.Transport = cp
+	_, err := c.Get(&url.URL{Path: "classes/Yak", RawQuery: "limit=1"}, nil)
+	ensure.Nil(t, err)
+	r := cp.ExpectGet(t, "classes/Yak")
+	ensure.DeepEqual(t, r.Query.Get("limit"), "1")
+	ensure.DeepEqual(t, r.Header.Get("X-Parse-REST-API-Key"), parsetest.RestAPIKey)
+}
+
+// fakeT records the errors of the Expect methods.
+type fakeT struct {
+	testing.TB
+	errors []string
+}
+
+func (f *fakeT) Helper() {}
+
+func (f *fakeT) Errorf(format string, args ...interface{}) {
+	f.errors = append(f.errors, fmt.Sprintf(format, args...))
+}
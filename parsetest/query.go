@@ -0,0 +1,266 @@
+package parsetest
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"reflect"
+	"sort"
+	"strconv"
+	"strings"
+
+	"github.com/facebookgo/parse"
+)
+
+func (s *Server) query(className string, q url.Values) (*result, error) {
+	var where map[string]interface{}
+	if w := q.Get("where"); w != "" {
+		if err := json.Unmarshal([]byte(w), &where); err != nil {
+			return nil, errorf(http.StatusBadRequest, parse.CodeInvalidJSON, "invalid where")
+		}
+	}
+	var matched []map[string]interface{}
+	for _, o := range s.classes[className] {
+		ok, err := matches(o, where)
+		if err != nil {
+			return nil, err
+		}
+		if ok {
+			matched = append(matched, o)
+		}
+	}
+
+	order := []string{"objectId"}
+	if o := q.Get("order"); o != "" {
+		order = strings.Split(o, ",")
+	}
+	sort.SliceStable(matched, func(i, j int) bool {
+		for _, key := range order {
+			desc := strings.HasPrefix(key, "-")
+			key = strings.TrimPrefix(key, "-")
+			c, _ := compare(matched[i][key], matched[j][key])
+			if c != 0 {
+				return (c < 0) != desc
+			}
+		}
+		return false
+	})
+
+	body := make(map[string]interface{})
+	if q.Get("count") == "1" {
+		body["count"] = len(matched)
+	}
+	limit := 100
+	if l := q.Get("limit"); l != "" {
+		n, err := strconv.Atoi(l)
+		if err != nil {
+			return nil, errorf(http.StatusBadRequest, parse.CodeInvalidQuery, "invalid limit %s", l)
+		}
+		limit = n
+	}
+	if sk := q.Get("skip"); sk != "" {
+		n, err := strconv.Atoi(sk)
+		if err != nil || n < 0 {
+			return nil, errorf(http.StatusBadRequest, parse.CodeInvalidQuery, "invalid skip %s", sk)
+		}
+		if n > len(matched) {
+			n = len(matched)
+		}
+		matched = matched[n:]
+	}
+	if limit >= 0 && limit < len(matched) {
+		matched = matched[:limit]
+	}
+	var keys []string
+	if k := q.Get("keys"); k != "" {
+		keys = strings.Split(k, ",")
+	}
+	results := make([]interface{}, len(matched))
+	for i, o := range matched {
+		results[i] = project(public(o), keys)
+	}
+	body["results"] = results
+	return &result{body: body}, nil
+}
+
+// project returns only the keys of the object, along with the fields that are
+// always returned.
+func project(o map[string]interface{}, keys []string) map[string]interface{} {
+	if len(keys) == 0 {
+		return o
+	}
+	p := map[string]interface{}{
+		"objectId":  o["objectId"],
+		"createdAt": o["createdAt"],
+		"updatedAt": o["updatedAt"],
+	}
+	for _, k := range keys {
+		if v, ok := o[k]; ok {
+			p[k] = v
+		}
+	}
+	return p
+}
+
+func matches(o, where map[string]interface{}) (bool, error) {
+	for key, cond := range where {
+		if key == "$and" || key == "$or" {
+			ok, err := matchesAny(o, key == "$and", cond)
+			if err != nil || !ok {
+				return false, err
+			}
+			continue
+		}
+		v, set := o[key]
+		c, ok := cond.(map[string]interface{})
+		if !ok || !isConstraint(c) {
+			if !equal(v, cond) {
+				return false, nil
+			}
+			continue
+		}
+		for op, arg := range c {
+			ok, err := constraint(op, v, set, arg)
+			if err != nil {
+				return false, err
+			}
+			if !ok {
+				return false, nil
+			}
+		}
+	}
+	return true, nil
+}
+
+// matchesAny reports whether the object matches all or any of the clauses.
+func matchesAny(o map[string]interface{}, all bool, clauses interface{}) (bool, error) {
+	a, ok := clauses.([]interface{})
+	if !ok {
+		return false, errorf(http.StatusBadRequest, parse.CodeInvalidQuery, "invalid clauses %v", clauses)
+	}
+	for _, c := range a {
+		w, ok := c.(map[string]interface{})
+		if !ok {
+			return false, errorf(http.StatusBadRequest, parse.CodeInvalidQuery, "invalid clause %v", c)
+		}
+		m, err := matches(o, w)
+		if err != nil {
+			return false, err
+		}
+		if m != all {
+			return m, nil
+		}
+	}
+	return all, nil
+}
+
+func isConstraint(c map[string]interface{}) bool {
+	for k := range c {
+		if strings.HasPrefix(k, "$") {
+			return true
+		}
+	}
+	return false
+}
+
+func constraint(op string, v interface{}, set bool, arg interface{}) (bool, error) {
+	switch op {
+	case "$ne":
+		return !equal(v, arg), nil
+	case "$exists":
+		want, _ := arg.(bool)
+		return set == want, nil
+	case "$in", "$nin":
+		list, ok := arg.([]interface{})
+		if !ok {
+			return false, errorf(http.StatusBadRequest, parse.CodeInvalidQuery, "%s must be an array", op)
+		}
+		in := false
+		for _, a := range list {
+			if equal(v, a) {
+				in = true
+				break
+			}
+		}
+		return in == (op == "$in"), nil
+	case "$lt", "$lte", "$gt", "$gte":
+		c, ok := compare(v, arg)
+		if !ok || !set {
+			return false, nil
+		}
+		switch op {
+		case "$lt":
+			return c < 0, nil
+		case "$lte":
+			return c <= 0, nil
+		case "$gt":
+			return c > 0, nil
+		}
+		return c >= 0, nil
+	}
+	return false, errorf(http.StatusBadRequest, parse.CodeInvalidQuery, "unsupported constraint %s", op)
+}
+
+// equal reports whether the field matches the value. An array field matches
+// any of its elements.
+func equal(v, want interface{}) bool {
+	if reflect.DeepEqual(v, want) {
+		return true
+	}
+	if list, ok := v.([]interface{}); ok {
+		return contains(list, want)
+	}
+	return false
+}
+
+func contains(list []interface{}, v interface{}) bool {
+	for _, e := range list {
+		if reflect.DeepEqual(e, v) {
+			return true
+		}
+	}
+	return false
+}
+
+// compare orders numbers, strings and Dates. It returns false when the values
+// cannot be compared. Unset values sort first.
+func compare(a, b interface{}) (int, bool) {
+	a, b = sortKey(a), sortKey(b)
+	switch a := a.(type) {
+	case nil:
+		if b == nil {
+			return 0, true
+		}
+		return -1, false
+	case float64:
+		b, ok := b.(float64)
+		if !ok {
+			return 0, false
+		}
+		switch {
+		case a < b:
+			return -1, true
+		case a > b:
+			return 1, true
+		}
+		return 0, true
+	case string:
+		b, ok := b.(string)
+		if !ok {
+			return 0, false
+		}
+		return strings.Compare(a, b), true
+	}
+	if b == nil {
+		return 1, false
+	}
+	return 0, false
+}
+
+// sortKey returns the ISO string of a Date, or the value itself.
+func sortKey(v interface{}) interface{} {
+	if m, ok := v.(map[string]interface{}); ok && m["__type"] == "Date" {
+		return m["iso"]
+	}
+	return v
+}
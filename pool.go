@@ -0,0 +1,26 @@
+package parse
+
+import (
+	"bytes"
+	"sync"
+)
+
+// maxPooledBuffer is the capacity above which buffers are not kept, so that a
+// single huge response doesn't pin its memory in the pool.
+const maxPooledBuffer = 1 << 20
+
+var bufferPool = sync.Pool{
+	New: func() interface{} { return new(bytes.Buffer) },
+}
+
+func getBuffer() *bytes.Buffer {
+	return bufferPool.Get().(*bytes.Buffer)
+}
+
+func putBuffer(b *bytes.Buffer) {
+	if b.Cap() > maxPooledBuffer {
+		return
+	}
+	b.Reset()
+	bufferPool.Put(b)
+}
@@ -0,0 +1,88 @@
+package parse
+
+import (
+	"sync"
+	"time"
+)
+
+// ProgressReport describes how far a long running operation, such as an
+// export, import or migration, is.
+type ProgressReport struct {
+	// Name identifies what is being processed, usually the class name.
+	Name string
+
+	// Processed is the number of objects handled so far, including those
+	// that failed.
+	Processed int
+	Errors    int
+
+	// Total is the number of objects to process, or zero when unknown.
+	Total int
+
+	Elapsed time.Duration
+
+	// Rate is the number of objects processed per second.
+	Rate float64
+
+	// ETA is the estimated time left, or zero when the Total is unknown.
+	ETA time.Duration
+
+	// Done is set in the last report of a completed operation.
+	Done bool
+}
+
+// ProgressReporter receives progress reports. Reports are made after every
+// page or batch of objects, so a reporter that logs should limit how often it
+// does. Reports can come from several goroutines, but not at once.
+type ProgressReporter interface {
+	ReportProgress(ProgressReport)
+}
+
+// ProgressFunc is an adapter to allow using a function as a ProgressReporter.
+type ProgressFunc func(ProgressReport)
+
+// ReportProgress calls f(r).
+func (f ProgressFunc) ReportProgress(r ProgressReport) {
+	f(r)
+}
+
+// progress makes reports for a ProgressReporter. A nil progress does nothing.
+type progress struct {
+	r     ProgressReporter
+	clock Clock
+	name  string
+	total int
+	start time.Time
+	mu    sync.Mutex
+}
+
+func newProgress(r ProgressReporter, c *Client, name string, total int) *progress {
+	if r == nil {
+		return nil
+	}
+	clock := c.clock()
+	return &progress{r: r, clock: clock, name: name, total: total, start: clock.Now()}
+}
+
+func (p *progress) report(processed, errors int, done bool) {
+	if p == nil {
+		return
+	}
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	r := ProgressReport{
+		Name:      p.name,
+		Processed: processed,
+		Errors:    errors,
+		Total:     p.total,
+		Elapsed:   p.clock.Now().Sub(p.start),
+		Done:      done,
+	}
+	if s := r.Elapsed.Seconds(); s > 0 {
+		r.Rate = float64(processed) / s
+	}
+	if r.Rate > 0 && r.Total > processed && !done {
+		r.ETA = time.Duration(float64(r.Total-processed) / r.Rate * float64(time.Second))
+	}
+	p.r.ReportProgress(r)
+}
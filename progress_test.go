@@ -0,0 +1,100 @@
+package parse_test
+
+import (
+	"bytes"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+	"github.com/facebookgo/parse/parsetest"
+)
+
+// stepClock moves a second forward every time it is read.
+type stepClock struct {
+	*parsetest.Clock
+}
+
+func (c stepClock) Now() time.Time {
+	c.Advance(time.Second)
+	return c.Clock.Now()
+}
+
+type progressReports struct {
+	mu      sync.Mutex
+	reports []parse.ProgressReport
+}
+
+func (p *progressReports) ReportProgress(r parse.ProgressReport) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.reports = append(p.reports, r)
+}
+
+func progressServer(t *testing.T, n int) (*parsetest.Server, *parse.Client) {
+	s := parsetest.NewServer()
+	c := s.Client()
+	c.Clock = stepClock{parsetest.NewClock(time.Date(2015, 6, 16, 1, 2, 3, 0, time.UTC))}
+	yaks := make([]map[string]int, n)
+	for i := range yaks {
+		yaks[i] = map[string]int{"n": i}
+	}
+	_, err := (&parse.ObjectClient{Client: c, Path: "classes/Yak"}).PostAll(yaks)
+	ensure.Nil(t, err)
+	return s, c
+}
+
+func TestProgressExport(t *testing.T) {
+	t.Parallel()
+	s, c := progressServer(t, 3)
+	defer s.Close()
+	var p progressReports
+	e := parse.Exporter{Client: c, ClassName: "Yak", BatchSize: 2, Reporter: &p}
+	_, err := e.Export(&bytes.Buffer{})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, p.reports, []parse.ProgressReport{
+		{Name: "Yak", Processed: 2, Total: 3, Elapsed: time.Second, Rate: 2, ETA: time.Second / 2},
+		{Name: "Yak", Processed: 3, Total: 3, Elapsed: 2 * time.Second, Rate: 1.5},
+		{Name: "Yak", Processed: 3, Total: 3, Elapsed: 3 * time.Second, Rate: 1, Done: true},
+	})
+}
+
+func TestProgressImport(t *testing.T) {
+	t.Parallel()
+	s := parsetest.NewServer()
+	defer s.Close()
+	var p progressReports
+	i := parse.Importer{Client: s.Client(), ClassName: "Yak", BatchSize: 2, Reporter: &p}
+	_, err := i.Import(strings.NewReader("{\"n\":1}\n{\"n\":2}\nnot json\n{\"n\":3}\n"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(p.reports), 3)
+	last := p.reports[2]
+	ensure.DeepEqual(t, last.Processed, 4)
+	ensure.DeepEqual(t, last.Errors, 1)
+	ensure.DeepEqual(t, last.Total, 0)
+	ensure.True(t, last.Done)
+}
+
+func TestProgressMigrate(t *testing.T) {
+	t.Parallel()
+	source, c := progressServer(t, 3)
+	defer source.Close()
+	destination := parsetest.NewServer()
+	defer destination.Close()
+	var p progressReports
+	m := parse.Migrator{
+		Source:      c,
+		Destination: destination.Client(),
+		Classes:     []string{"Yak"},
+		Reporter:    &p,
+	}
+	_, err := m.Migrate()
+	ensure.Nil(t, err)
+	last := p.reports[len(p.reports)-1]
+	ensure.DeepEqual(t, last.Name, "Yak")
+	ensure.DeepEqual(t, last.Processed, 3)
+	ensure.DeepEqual(t, last.Total, 3)
+	ensure.True(t, last.Done)
+}
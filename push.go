@@ -0,0 +1,122 @@
+package parse
+
+import (
+	"encoding/json"
+	"errors"
+	"log"
+	"net/url"
+	"time"
+)
+
+const pushStatusIDHeader = "X-Parse-Push-Status-Id"
+
+var (
+	errPushNoTarget   = errors.New("parse: push requires Channels or Where")
+	errPushBothTarget = errors.New("parse: push cannot use both Channels and Where")
+	errPushNoData     = errors.New("parse: push requires Data")
+
+	pushURL = &url.URL{Path: "push"}
+)
+
+// Push is a push notification. It targets either the installations
+// subscribed to Channels or the installations matching the Where query,
+// which is typically built using Where:
+//
+//	parse.Push{
+//		Where: parse.Where{}.
+//			EqualTo("deviceType", "ios").
+//			WithinKilometers("location", here, 10),
+//		Data: map[string]string{"alert": "Yaks nearby!"},
+//	}
+type Push struct {
+	Channels           []string    `json:"channels,omitempty"`
+	Where              interface{} `json:"where,omitempty"`
+	Data               interface{} `json:"data"`
+	PushTime           *time.Time  `json:"push_time,omitempty"`
+	ExpirationTime     *time.Time  `json:"expiration_time,omitempty"`
+	ExpirationInterval int         `json:"expiration_interval,omitempty"`
+
+	// AudienceID associates the push with a saved Audience.
+	AudienceID string `json:"audience_id,omitempty"`
+}
+
+func (p *Push) validate() error {
+	if len(p.Channels) == 0 && p.Where == nil {
+		return errPushNoTarget
+	}
+	if len(p.Channels) != 0 && p.Where != nil {
+		return errPushBothTarget
+	}
+	if p.Data == nil {
+		return errPushNoData
+	}
+	return nil
+}
+
+// PushResult is the result of sending a push notification.
+type PushResult struct {
+	Result bool `json:"result"`
+
+	// StatusID is the ID of the _PushStatus object tracking the push, when
+	// provided by the server.
+	StatusID string `json:"-"`
+
+	// Installations is the number of targeted installations. It is only set
+	// in DryRun mode.
+	Installations int `json:"-"`
+}
+
+// PushClient sends push notifications. It requires the Master Key.
+type PushClient struct {
+	Client *Client
+
+	// DryRun counts the targeted installations and logs the push instead of
+	// sending it.
+	DryRun bool
+
+	// Logger for DryRun mode. When nil the standard logger will be used.
+	Logger *log.Logger
+}
+
+func (c *PushClient) logf(format string, v ...interface{}) {
+	if c.Logger == nil {
+		log.Printf(format, v...)
+	} else {
+		c.Logger.Printf(format, v...)
+	}
+}
+
+// Send sends the push notification.
+func (c *PushClient) Send(p *Push) (*PushResult, error) {
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	if c.DryRun {
+		return c.dryRun(p)
+	}
+	var result PushResult
+	res, err := c.Client.Post(pushURL, p, &result)
+	if err != nil {
+		return nil, err
+	}
+	result.StatusID = res.Header.Get(pushStatusIDHeader)
+	return &result, nil
+}
+
+func (c *PushClient) dryRun(p *Push) (*PushResult, error) {
+	where := p.Where
+	if len(p.Channels) != 0 {
+		where = Where{}.ContainedIn("channels", stringsToObjects(p.Channels)...)
+	}
+	installations := ObjectClient{Client: c.Client, Path: installationsPath}
+	count, err := installations.Count(where)
+	if err != nil {
+		return nil, err
+	}
+	payload, err := json.Marshal(p)
+	if err != nil {
+		return nil, err
+	}
+	c.logf("parse: dry run push to %d installations: %s", count, payload)
+	return &PushResult{Result: true, Installations: count}, nil
+}
@@ -0,0 +1,151 @@
+package parse
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// The maximum size of the encoded push data, as enforced by APNS.
+const maxPushDataSize = 4096
+
+var errNegativeBadge = errors.New("parse: push badge cannot be negative")
+
+// PushBuilder composes a Push. The zero value is ready to use, and the methods
+// can be chained:
+//
+//	p, err := new(parse.PushBuilder).
+//	  Channels("yaks").
+//	  Alert("Hello").
+//	  IncrementBadge().
+//	  Build()
+type PushBuilder struct {
+	// Clock optionally replaces the real clock used by In, in tests.
+	Clock Clock
+
+	push Push
+	data map[string]interface{}
+	err  error
+}
+
+func (b *PushBuilder) set(key string, value interface{}) *PushBuilder {
+	if b.data == nil {
+		b.data = make(map[string]interface{})
+	}
+	b.data[key] = value
+	return b
+}
+
+// Channels targets the installations subscribed to the given channels.
+func (b *PushBuilder) Channels(channels ...string) *PushBuilder {
+	b.push.Channels = append(b.push.Channels, channels...)
+	return b
+}
+
+// Where targets the installations matching the given query.
+func (b *PushBuilder) Where(where interface{}) *PushBuilder {
+	b.push.Where = where
+	return b
+}
+
+// Audience targets the installations matching the saved audience.
+func (b *PushBuilder) Audience(a *Audience) *PushBuilder {
+	if a.Query != nil {
+		b.push.Where = a.Query
+	}
+	b.push.AudienceID = a.ID
+	return b
+}
+
+// Alert sets the notification message.
+func (b *PushBuilder) Alert(alert string) *PushBuilder {
+	return b.set("alert", alert)
+}
+
+// Title sets the notification title.
+func (b *PushBuilder) Title(title string) *PushBuilder {
+	return b.set("title", title)
+}
+
+// Badge sets the iOS badge to the given value.
+func (b *PushBuilder) Badge(badge int) *PushBuilder {
+	if badge < 0 {
+		b.err = errNegativeBadge
+	}
+	return b.set("badge", badge)
+}
+
+// IncrementBadge increments the current iOS badge by one.
+func (b *PushBuilder) IncrementBadge() *PushBuilder {
+	return b.set("badge", "Increment")
+}
+
+// Sound sets the name of the sound file to play.
+func (b *PushBuilder) Sound(sound string) *PushBuilder {
+	return b.set("sound", sound)
+}
+
+// ContentAvailable marks the notification as a silent background update on
+// iOS.
+func (b *PushBuilder) ContentAvailable() *PushBuilder {
+	return b.set("content-available", 1)
+}
+
+// Category sets the iOS notification category identifier.
+func (b *PushBuilder) Category(category string) *PushBuilder {
+	return b.set("category", category)
+}
+
+// Data sets a custom data key.
+func (b *PushBuilder) Data(key string, value interface{}) *PushBuilder {
+	return b.set(key, value)
+}
+
+// At schedules the push for the given time.
+func (b *PushBuilder) At(t time.Time) *PushBuilder {
+	b.push.PushTime = &t
+	return b
+}
+
+// In schedules the push for the given duration from now.
+func (b *PushBuilder) In(d time.Duration) *PushBuilder {
+	return b.At(clockOrReal(b.Clock).Now().Add(d))
+}
+
+// ExpiresAt sets the time after which the push will no longer be delivered.
+func (b *PushBuilder) ExpiresAt(t time.Time) *PushBuilder {
+	b.push.ExpirationTime = &t
+	return b
+}
+
+// ExpiresIn sets the duration after which the push will no longer be
+// delivered.
+func (b *PushBuilder) ExpiresIn(d time.Duration) *PushBuilder {
+	b.push.ExpirationInterval = int(d / time.Second)
+	return b
+}
+
+// Build validates and returns the Push.
+func (b *PushBuilder) Build() (*Push, error) {
+	if b.err != nil {
+		return nil, b.err
+	}
+	p := b.push
+	if b.data != nil {
+		data, err := json.Marshal(b.data)
+		if err != nil {
+			return nil, err
+		}
+		if len(data) > maxPushDataSize {
+			return nil, fmt.Errorf(
+				"parse: push data is %d bytes which exceeds the limit of %d bytes",
+				len(data), maxPushDataSize)
+		}
+		p.Data = b.data
+	}
+	if err := p.validate(); err != nil {
+		return nil, err
+	}
+	return &p, nil
+}
@@ -0,0 +1,81 @@
+package parse_test
+
+import (
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestPushBuilder(t *testing.T) {
+	t.Parallel()
+	at := time.Date(2015, 6, 16, 1, 2, 3, 0, time.UTC)
+	p, err := new(parse.PushBuilder).
+		Channels("yaks", "herds").
+		Alert("hello").
+		Title("greeting").
+		IncrementBadge().
+		Sound("moo.caf").
+		ContentAvailable().
+		Category("social").
+		Data("yakId", "xyz").
+		At(at).
+		ExpiresIn(time.Hour).
+		Build()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, p, &parse.Push{
+		Channels: []string{"yaks", "herds"},
+		Data: map[string]interface{}{
+			"alert":             "hello",
+			"title":             "greeting",
+			"badge":             "Increment",
+			"sound":             "moo.caf",
+			"content-available": 1,
+			"category":          "social",
+			"yakId":             "xyz",
+		},
+		PushTime:           &at,
+		ExpirationInterval: 3600,
+	})
+}
+
+func TestPushBuilderWhere(t *testing.T) {
+	t.Parallel()
+	where := map[string]string{"deviceType": "ios"}
+	p, err := new(parse.PushBuilder).Where(where).Badge(2).Build()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, p.Where, where)
+	ensure.DeepEqual(t, p.Data, map[string]interface{}{"badge": 2})
+}
+
+func TestPushBuilderErrors(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Builder *parse.PushBuilder
+		Error   string
+	}{
+		{
+			new(parse.PushBuilder).Channels("a").Badge(-1),
+			"badge cannot be negative",
+		},
+		{
+			new(parse.PushBuilder).Channels("a").Alert(strings.Repeat("a", 5000)),
+			"exceeds the limit of 4096 bytes",
+		},
+		{
+			new(parse.PushBuilder).Alert("hello"),
+			"requires Channels or Where",
+		},
+		{
+			new(parse.PushBuilder).Channels("a"),
+			"requires Data",
+		},
+	}
+	for _, c := range cases {
+		_, err := c.Builder.Build()
+		ensure.Err(t, err, regexp.MustCompile(c.Error))
+	}
+}
@@ -0,0 +1,190 @@
+package parse
+
+import (
+	"encoding/json"
+	"errors"
+	"time"
+)
+
+// The maximum time to live FCM allows for a message.
+const maxFCMTimeToLive = 28 * 24 * time.Hour
+
+var (
+	errAPNSLocArgs          = errors.New("parse: APNS alert LocArgs require LocKey")
+	errAPNSTitleLocArgs     = errors.New("parse: APNS alert TitleLocArgs require TitleLocKey")
+	errAPNSSoundName        = errors.New("parse: APNS sound requires Name")
+	errAPNSSoundVolume      = errors.New("parse: APNS sound Volume must be between 0 and 1")
+	errFCMPriority          = errors.New(`parse: FCM Priority must be "high" or "normal"`)
+	errFCMTimeToLive        = errors.New("parse: FCM TimeToLive must be between 0 and 28 days")
+	errFCMNotificationEmpty = errors.New("parse: FCM Notification requires Title or Body")
+)
+
+// APNSAlert is the alert dictionary of an iOS notification.
+type APNSAlert struct {
+	Title        string   `json:"title,omitempty"`
+	Subtitle     string   `json:"subtitle,omitempty"`
+	Body         string   `json:"body,omitempty"`
+	TitleLocKey  string   `json:"title-loc-key,omitempty"`
+	TitleLocArgs []string `json:"title-loc-args,omitempty"`
+	LocKey       string   `json:"loc-key,omitempty"`
+	LocArgs      []string `json:"loc-args,omitempty"`
+	ActionLocKey string   `json:"action-loc-key,omitempty"`
+	LaunchImage  string   `json:"launch-image,omitempty"`
+}
+
+// APNSSound is the sound to play for an iOS notification.
+type APNSSound struct {
+	Name string
+
+	// Critical alerts play even when the device is muted. They require a
+	// special entitlement.
+	Critical bool
+
+	// Volume of a critical alert, between 0 and 1.
+	Volume float64
+}
+
+// MarshalJSON encodes the sound as a name where possible, and as a sound
+// dictionary for critical alerts.
+func (s APNSSound) MarshalJSON() ([]byte, error) {
+	if !s.Critical {
+		return json.Marshal(s.Name)
+	}
+	return json.Marshal(struct {
+		Critical int     `json:"critical"`
+		Name     string  `json:"name"`
+		Volume   float64 `json:"volume"`
+	}{Critical: 1, Name: s.Name, Volume: s.Volume})
+}
+
+// APNS holds the iOS specific parts of a push.
+type APNS struct {
+	Alert *APNSAlert
+	Sound *APNSSound
+
+	// MutableContent allows a notification service extension to modify the
+	// notification before it is displayed.
+	MutableContent bool
+
+	// ContentAvailable makes the notification a silent background update.
+	ContentAvailable bool
+
+	Category string
+	ThreadID string
+}
+
+func (a *APNS) payload() (map[string]interface{}, error) {
+	p := make(map[string]interface{})
+	if a.Alert != nil {
+		if len(a.Alert.LocArgs) != 0 && a.Alert.LocKey == "" {
+			return nil, errAPNSLocArgs
+		}
+		if len(a.Alert.TitleLocArgs) != 0 && a.Alert.TitleLocKey == "" {
+			return nil, errAPNSTitleLocArgs
+		}
+		p["alert"] = a.Alert
+	}
+	if a.Sound != nil {
+		if a.Sound.Name == "" {
+			return nil, errAPNSSoundName
+		}
+		if a.Sound.Volume < 0 || a.Sound.Volume > 1 {
+			return nil, errAPNSSoundVolume
+		}
+		p["sound"] = a.Sound
+	}
+	if a.MutableContent {
+		p["mutable-content"] = 1
+	}
+	if a.ContentAvailable {
+		p["content-available"] = 1
+	}
+	if a.Category != "" {
+		p["category"] = a.Category
+	}
+	if a.ThreadID != "" {
+		p["threadId"] = a.ThreadID
+	}
+	return p, nil
+}
+
+// FCMNotification is the displayed part of an Android notification message.
+type FCMNotification struct {
+	Title       string `json:"title,omitempty"`
+	Body        string `json:"body,omitempty"`
+	Icon        string `json:"icon,omitempty"`
+	Color       string `json:"color,omitempty"`
+	Sound       string `json:"sound,omitempty"`
+	Tag         string `json:"tag,omitempty"`
+	ClickAction string `json:"click_action,omitempty"`
+}
+
+// FCM holds the Android specific parts of a push. When Notification is set
+// the push is delivered as a notification message which is displayed by the
+// system, otherwise it is a data message handled by the application.
+type FCM struct {
+	// Priority is either "high" or "normal".
+	Priority string
+
+	// CollapseKey identifies a group of messages of which only the last one
+	// is delivered when the device comes back online.
+	CollapseKey string
+
+	// TimeToLive is how long the message is kept while the device is offline.
+	TimeToLive time.Duration
+
+	Notification *FCMNotification
+}
+
+func (f *FCM) payload() (map[string]interface{}, error) {
+	p := make(map[string]interface{})
+	switch f.Priority {
+	case "":
+	case "high", "normal":
+		p["priority"] = f.Priority
+	default:
+		return nil, errFCMPriority
+	}
+	if f.CollapseKey != "" {
+		p["collapse_key"] = f.CollapseKey
+	}
+	if f.TimeToLive < 0 || f.TimeToLive > maxFCMTimeToLive {
+		return nil, errFCMTimeToLive
+	}
+	if f.TimeToLive != 0 {
+		p["time_to_live"] = int(f.TimeToLive / time.Second)
+	}
+	if f.Notification != nil {
+		if f.Notification.Title == "" && f.Notification.Body == "" {
+			return nil, errFCMNotificationEmpty
+		}
+		p["notification"] = f.Notification
+	}
+	return p, nil
+}
+
+// APNS adds the iOS specific parts to the push.
+func (b *PushBuilder) APNS(a *APNS) *PushBuilder {
+	p, err := a.payload()
+	if err != nil {
+		b.err = err
+		return b
+	}
+	for k, v := range p {
+		b.set(k, v)
+	}
+	return b
+}
+
+// FCM adds the Android specific parts to the push.
+func (b *PushBuilder) FCM(f *FCM) *PushBuilder {
+	p, err := f.payload()
+	if err != nil {
+		b.err = err
+		return b
+	}
+	for k, v := range p {
+		b.set(k, v)
+	}
+	return b
+}
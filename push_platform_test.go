@@ -0,0 +1,108 @@
+package parse_test
+
+import (
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestPushBuilderAPNS(t *testing.T) {
+	t.Parallel()
+	p, err := new(parse.PushBuilder).
+		Channels("yaks").
+		APNS(&parse.APNS{
+			Alert: &parse.APNSAlert{
+				Title:   "Yak",
+				LocKey:  "NEW_YAK",
+				LocArgs: []string{"bob"},
+			},
+			Sound:          &parse.APNSSound{Name: "moo.caf", Critical: true, Volume: 0.5},
+			MutableContent: true,
+			Category:       "social",
+			ThreadID:       "herd",
+		}).
+		Build()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, string(jsonB(t, p.Data)), `{`+
+		`"alert":{"title":"Yak","loc-key":"NEW_YAK","loc-args":["bob"]},`+
+		`"category":"social",`+
+		`"mutable-content":1,`+
+		`"sound":{"critical":1,"name":"moo.caf","volume":0.5},`+
+		`"threadId":"herd"`+
+		`}`)
+}
+
+func TestAPNSSoundName(t *testing.T) {
+	t.Parallel()
+	ensure.DeepEqual(t, string(jsonB(t, parse.APNSSound{Name: "moo.caf"})), `"moo.caf"`)
+}
+
+func TestPushBuilderFCM(t *testing.T) {
+	t.Parallel()
+	p, err := new(parse.PushBuilder).
+		Channels("yaks").
+		FCM(&parse.FCM{
+			Priority:     "high",
+			CollapseKey:  "yaks",
+			TimeToLive:   time.Hour,
+			Notification: &parse.FCMNotification{Title: "Yak", Body: "moo"},
+		}).
+		Build()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, string(jsonB(t, p.Data)), `{`+
+		`"collapse_key":"yaks",`+
+		`"notification":{"title":"Yak","body":"moo"},`+
+		`"priority":"high",`+
+		`"time_to_live":3600`+
+		`}`)
+}
+
+func TestPushPlatformErrors(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Builder *parse.PushBuilder
+		Error   string
+	}{
+		{
+			new(parse.PushBuilder).APNS(&parse.APNS{
+				Alert: &parse.APNSAlert{LocArgs: []string{"a"}},
+			}),
+			"LocArgs require LocKey",
+		},
+		{
+			new(parse.PushBuilder).APNS(&parse.APNS{
+				Alert: &parse.APNSAlert{TitleLocArgs: []string{"a"}},
+			}),
+			"TitleLocArgs require TitleLocKey",
+		},
+		{
+			new(parse.PushBuilder).APNS(&parse.APNS{Sound: &parse.APNSSound{}}),
+			"sound requires Name",
+		},
+		{
+			new(parse.PushBuilder).APNS(&parse.APNS{
+				Sound: &parse.APNSSound{Name: "a", Critical: true, Volume: 2},
+			}),
+			"Volume must be between 0 and 1",
+		},
+		{
+			new(parse.PushBuilder).FCM(&parse.FCM{Priority: "urgent"}),
+			"Priority must be",
+		},
+		{
+			new(parse.PushBuilder).FCM(&parse.FCM{TimeToLive: 30 * 24 * time.Hour}),
+			"TimeToLive must be between 0 and 28 days",
+		},
+		{
+			new(parse.PushBuilder).FCM(&parse.FCM{Notification: &parse.FCMNotification{}}),
+			"Notification requires Title or Body",
+		},
+	}
+	for _, c := range cases {
+		_, err := c.Builder.Channels("a").Build()
+		ensure.Err(t, err, regexp.MustCompile(c.Error))
+	}
+}
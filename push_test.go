@@ -0,0 +1,134 @@
+package parse_test
+
+import (
+	"bytes"
+	"log"
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestPushSendChannels(t *testing.T) {
+	t.Parallel()
+	pushTime := time.Date(2015, 6, 16, 1, 2, 3, 0, time.UTC)
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Method, "POST")
+			ensure.DeepEqual(t, r.URL.Path, "/1/push")
+			var m map[string]interface{}
+			decodeBody(t, r, &m)
+			ensure.DeepEqual(t, m, map[string]interface{}{
+				"channels":  []interface{}{"yaks"},
+				"data":      map[string]interface{}{"alert": "hello"},
+				"push_time": "2015-06-16T01:02:03Z",
+			})
+			res := jsonResponse(t, http.StatusOK, map[string]bool{"result": true})
+			res.Header.Set("X-Parse-Push-Status-Id", "status")
+			return res, nil
+		}),
+	}
+	pc := parse.PushClient{Client: c}
+	res, err := pc.Send(&parse.Push{
+		Channels: []string{"yaks"},
+		Data:     map[string]string{"alert": "hello"},
+		PushTime: &pushTime,
+	})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res, &parse.PushResult{Result: true, StatusID: "status"})
+}
+
+func TestPushSendWhere(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			var m map[string]interface{}
+			decodeBody(t, r, &m)
+			ensure.DeepEqual(t, m["where"], map[string]interface{}{"deviceType": "ios"})
+			return jsonResponse(t, http.StatusOK, map[string]bool{"result": true}), nil
+		}),
+	}
+	pc := parse.PushClient{Client: c}
+	res, err := pc.Send(&parse.Push{
+		Where: map[string]string{"deviceType": "ios"},
+		Data:  map[string]string{"alert": "hello"},
+	})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res, &parse.PushResult{Result: true})
+}
+
+func TestPushSendInvalid(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Push  *parse.Push
+		Error string
+	}{
+		{&parse.Push{Data: "a"}, "requires Channels or Where"},
+		{
+			&parse.Push{Channels: []string{"a"}, Where: map[string]string{}, Data: "a"},
+			"cannot use both Channels and Where",
+		},
+		{&parse.Push{Channels: []string{"a"}}, "requires Data"},
+	}
+	pc := parse.PushClient{Client: &parse.Client{}}
+	for _, c := range cases {
+		_, err := pc.Send(c.Push)
+		ensure.Err(t, err, regexp.MustCompile(c.Error))
+	}
+}
+
+func TestPushSendTypedWhere(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			var m map[string]interface{}
+			decodeBody(t, r, &m)
+			ensure.DeepEqual(t, m["where"], map[string]interface{}{
+				"deviceType": "ios",
+				"user": map[string]interface{}{
+					"__type":    "Pointer",
+					"className": "_User",
+					"objectId":  "xyz",
+				},
+			})
+			return jsonResponse(t, http.StatusOK, map[string]bool{"result": true}), nil
+		}),
+	}
+	pc := parse.PushClient{Client: c}
+	_, err := pc.Send(&parse.Push{
+		Where: parse.Where{}.
+			EqualTo("deviceType", "ios").
+			EqualTo("user", parse.Pointer{ClassName: "_User", ID: "xyz"}),
+		Data: map[string]string{"alert": "hello"},
+	})
+	ensure.Nil(t, err)
+}
+
+func TestPushDryRun(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Method, "GET")
+			ensure.DeepEqual(t, r.URL.Path, "/1/installations/")
+			ensure.DeepEqual(t, r.URL.Query().Get("where"), `{"channels":{"$in":["yaks"]}}`)
+			return jsonResponse(t, http.StatusOK, map[string]int{"count": 42}), nil
+		}),
+	}
+	var buf bytes.Buffer
+	pc := parse.PushClient{
+		Client: c,
+		DryRun: true,
+		Logger: log.New(&buf, "", 0),
+	}
+	res, err := pc.Send(&parse.Push{
+		Channels: []string{"yaks"},
+		Data:     map[string]string{"alert": "hello"},
+	})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res, &parse.PushResult{Result: true, Installations: 42})
+	ensure.DeepEqual(t, buf.String(),
+		`parse: dry run push to 42 installations: {"channels":["yaks"],"data":{"alert":"hello"}}`+"\n")
+}
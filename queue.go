@@ -0,0 +1,280 @@
+package parse
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"sync"
+	"time"
+)
+
+const defaultQueueRetryInterval = 10 * time.Second
+
+// QueuedWrite is a create, update or delete waiting in a WriteQueue.
+type QueuedWrite struct {
+	Method string          `json:"method"`
+	Path   string          `json:"path"`
+	Body   json.RawMessage `json:"body,omitempty"`
+	Queued time.Time       `json:"queued"`
+
+	// RequestID is sent as the idempotency key of every attempt, so that a
+	// write that reached the server before the connection failed is not
+	// applied twice by a server with idempotency enabled.
+	RequestID string `json:"requestId"`
+}
+
+// QueueStore persists the writes of a WriteQueue, so that they survive a
+// restart.
+type QueueStore interface {
+	// Load returns the saved writes, in order.
+	Load() ([]QueuedWrite, error)
+
+	// Save replaces the saved writes.
+	Save([]QueuedWrite) error
+}
+
+// FileQueueStore is a QueueStore keeping the writes in a JSON file.
+type FileQueueStore struct {
+	Path string
+}
+
+// Load reads the file. A missing file is an empty queue.
+func (f FileQueueStore) Load() ([]QueuedWrite, error) {
+	b, err := ioutil.ReadFile(f.Path)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	var writes []QueuedWrite
+	if err := json.Unmarshal(b, &writes); err != nil {
+		return nil, err
+	}
+	return writes, nil
+}
+
+// Save replaces the file atomically. An empty queue removes the file.
+func (f FileQueueStore) Save(writes []QueuedWrite) error {
+	if len(writes) == 0 {
+		err := os.Remove(f.Path)
+		if os.IsNotExist(err) {
+			return nil
+		}
+		return err
+	}
+	b, err := json.Marshal(writes)
+	if err != nil {
+		return err
+	}
+	return writeFileAtomic(f.Path, b)
+}
+
+// WriteQueue holds writes while the server is unreachable and replays them in
+// order once it is back, for clients that must not lose writes to network
+// failures:
+//
+//	q := &parse.WriteQueue{Client: c, Store: parse.FileQueueStore{Path: "writes.json"}}
+//	go q.Run(ctx)
+//	err := q.Send("POST", "classes/Reading", reading)
+//
+// Network errors, canceled contexts, an open CircuitBreaker and answers of
+// the server with status 429 or 5xx are transient and stop the replay until
+// the next try. Writes failing otherwise, such as those the server rejects,
+// are dropped and reported to Rejected.
+type WriteQueue struct {
+	Client *Client
+
+	// Store optionally persists the queue. Without it the queue is lost
+	// when the process exits.
+	Store QueueStore
+
+	// RetryInterval is the time between replays by Run. Defaults to 10
+	// seconds.
+	RetryInterval time.Duration
+
+	// Rejected is optionally called with the writes that were dropped and
+	// their errors, after the queue is unlocked.
+	Rejected func(QueuedWrite, error)
+
+	mu     sync.Mutex
+	writes []QueuedWrite
+	loaded bool
+}
+
+// transient reports if the write should be tried again later.
+func transient(err error) bool {
+	var apiErr *Error
+	if errors.As(err, &apiErr) {
+		return apiErr.StatusCode >= 500 || apiErr.StatusCode == http.StatusTooManyRequests
+	}
+	var rawErr *RawError
+	if errors.As(err, &rawErr) {
+		return rawErr.StatusCode >= 500 || rawErr.StatusCode == http.StatusTooManyRequests
+	}
+	var netErr net.Error
+	var openErr *CircuitOpenError
+	return errors.As(err, &netErr) || errors.As(err, &openErr) ||
+		errors.Is(err, context.Canceled) || errors.Is(err, context.DeadlineExceeded) ||
+		errors.Is(err, io.EOF) || errors.Is(err, io.ErrUnexpectedEOF)
+}
+
+func (q *WriteQueue) load() error {
+	if q.loaded || q.Store == nil {
+		q.loaded = true
+		return nil
+	}
+	writes, err := q.Store.Load()
+	if err != nil {
+		return err
+	}
+	q.writes = append(writes, q.writes...)
+	q.loaded = true
+	return nil
+}
+
+func (q *WriteQueue) save() error {
+	if q.Store == nil {
+		return nil
+	}
+	return q.Store.Save(q.writes)
+}
+
+func newQueuedWrite(method, path string, body interface{}, now time.Time) (QueuedWrite, error) {
+	w := QueuedWrite{Method: method, Path: path, Queued: now}
+	if body != nil {
+		b, err := json.Marshal(body)
+		if err != nil {
+			return w, err
+		}
+		w.Body = b
+	}
+	id, err := newRequestID()
+	if err != nil {
+		return w, err
+	}
+	w.RequestID = id
+	return w, nil
+}
+
+// Enqueue adds the write to the end of the queue without sending it.
+func (q *WriteQueue) Enqueue(method, path string, body interface{}) error {
+	w, err := newQueuedWrite(method, path, body, q.Client.clock().Now())
+	if err != nil {
+		return err
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err := q.load(); err != nil {
+		return err
+	}
+	q.writes = append(q.writes, w)
+	return q.save()
+}
+
+// Send sends the write right away when the queue is empty, and queues it if
+// that fails with a transient error or if earlier writes are still queued. A
+// write the server rejects returns its error without being queued.
+func (q *WriteQueue) Send(method, path string, body interface{}) error {
+	w, err := newQueuedWrite(method, path, body, q.Client.clock().Now())
+	if err != nil {
+		return err
+	}
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err := q.load(); err != nil {
+		return err
+	}
+	if len(q.writes) == 0 {
+		err := q.send(context.Background(), w)
+		if err == nil || !transient(err) {
+			return err
+		}
+	}
+	q.writes = append(q.writes, w)
+	return q.save()
+}
+
+func (q *WriteQueue) send(ctx context.Context, w QueuedWrite) error {
+	var body interface{}
+	if len(w.Body) != 0 {
+		body = w.Body
+	}
+	ctx = WithIdempotencyKey(ctx, w.RequestID)
+	req := (&http.Request{Method: w.Method, URL: &url.URL{Path: w.Path}}).WithContext(ctx)
+	_, err := q.Client.Do(req, body, nil)
+	return err
+}
+
+// Len returns the number of queued writes.
+func (q *WriteQueue) Len() (int, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err := q.load(); err != nil {
+		return 0, err
+	}
+	return len(q.writes), nil
+}
+
+// rejectedWrite is a write dropped by Replay.
+type rejectedWrite struct {
+	write QueuedWrite
+	err   error
+}
+
+// Replay sends the queued writes in order. It stops at the first transient
+// error, which it returns, leaving that write and the following ones queued.
+func (q *WriteQueue) Replay(ctx context.Context) error {
+	rejected, err := q.replay(ctx)
+	if q.Rejected != nil {
+		for _, r := range rejected {
+			q.Rejected(r.write, r.err)
+		}
+	}
+	return err
+}
+
+func (q *WriteQueue) replay(ctx context.Context) ([]rejectedWrite, error) {
+	q.mu.Lock()
+	defer q.mu.Unlock()
+	if err := q.load(); err != nil {
+		return nil, err
+	}
+	var rejected []rejectedWrite
+	for len(q.writes) != 0 {
+		w := q.writes[0]
+		err := q.send(ctx, w)
+		if err != nil && transient(err) {
+			return rejected, err
+		}
+		q.writes = q.writes[1:]
+		if err := q.save(); err != nil {
+			return rejected, err
+		}
+		if err != nil {
+			rejected = append(rejected, rejectedWrite{write: w, err: err})
+		}
+	}
+	return rejected, nil
+}
+
+// Run replays the queue, waiting RetryInterval on the Clock of the Client
+// after every replay, until the context is done.
+func (q *WriteQueue) Run(ctx context.Context) error {
+	d := q.RetryInterval
+	if d <= 0 {
+		d = defaultQueueRetryInterval
+	}
+	for {
+		q.Replay(ctx)
+		if err := sleep(ctx, q.Client.clock(), d); err != nil {
+			return err
+		}
+	}
+}
@@ -0,0 +1,217 @@
+package parse_test
+
+import (
+	"context"
+	"errors"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+	"github.com/facebookgo/parse/parsetest"
+)
+
+// flakyServer fails requests with a connection error while down, or with err
+// when set, and records the requests it answers.
+type flakyServer struct {
+	t        *testing.T
+	down     bool
+	err      error
+	status   int
+	requests []string
+	ids      []string
+}
+
+func (s *flakyServer) RoundTrip(r *http.Request) (*http.Response, error) {
+	if s.down {
+		return nil, &net.OpError{Op: "dial", Net: "tcp", Err: errors.New("connection refused")}
+	}
+	if s.err != nil {
+		return nil, s.err
+	}
+	var body []byte
+	if r.Body != nil {
+		var err error
+		body, err = ioutil.ReadAll(r.Body)
+		ensure.Nil(s.t, err)
+	}
+	s.requests = append(s.requests, r.Method+" "+r.URL.Path+" "+string(body))
+	s.ids = append(s.ids, r.Header.Get("X-Parse-Request-Id"))
+	status := s.status
+	if status == 0 {
+		status = http.StatusOK
+	}
+	return jsonResponse(s.t, status, map[string]interface{}{"code": 111, "error": "invalid type"}), nil
+}
+
+func TestWriteQueue(t *testing.T) {
+	t.Parallel()
+	s := &flakyServer{t: t}
+	q := &parse.WriteQueue{Client: &parse.Client{Transport: s}}
+
+	ensure.Nil(t, q.Send("POST", "classes/Yak", map[string]int{"n": 1}))
+	s.down = true
+	ensure.Nil(t, q.Send("PUT", "classes/Yak/a", map[string]int{"n": 2}))
+	s.down = false
+	ensure.Nil(t, q.Send("DELETE", "classes/Yak/b", nil))
+	n, err := q.Len()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, n, 2)
+	ensure.DeepEqual(t, len(s.requests), 1)
+
+	ensure.Nil(t, q.Replay(context.Background()))
+	n, err = q.Len()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, n, 0)
+	ensure.DeepEqual(t, s.requests, []string{
+		`POST /1/classes/Yak {"n":1}`,
+		`PUT /1/classes/Yak/a {"n":2}`,
+		`DELETE /1/classes/Yak/b `,
+	})
+}
+
+func TestWriteQueueReplayStopsWhileDown(t *testing.T) {
+	t.Parallel()
+	s := &flakyServer{t: t, down: true}
+	q := &parse.WriteQueue{Client: &parse.Client{Transport: s}}
+	ensure.Nil(t, q.Enqueue("POST", "classes/Yak", map[string]int{"n": 1}))
+	ensure.Nil(t, q.Enqueue("POST", "classes/Yak", map[string]int{"n": 2}))
+	ensure.NotNil(t, q.Replay(context.Background()))
+	ensure.NotNil(t, q.Replay(context.Background()))
+	n, err := q.Len()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, n, 2)
+
+	s.down = false
+	ensure.Nil(t, q.Replay(context.Background()))
+	ensure.DeepEqual(t, len(s.requests), 2)
+	ensure.NotDeepEqual(t, s.ids[0], "")
+	ensure.NotDeepEqual(t, s.ids[0], s.ids[1])
+}
+
+func TestWriteQueueRejected(t *testing.T) {
+	t.Parallel()
+	s := &flakyServer{t: t, down: true}
+	var rejected []parse.QueuedWrite
+	q := &parse.WriteQueue{
+		Client:   &parse.Client{Transport: s},
+		Rejected: func(w parse.QueuedWrite, err error) { rejected = append(rejected, w) },
+	}
+	ensure.Nil(t, q.Send("POST", "classes/Yak", map[string]string{"n": "x"}))
+	s.down = false
+	s.status = http.StatusBadRequest
+	ensure.Nil(t, q.Replay(context.Background()))
+	ensure.DeepEqual(t, len(rejected), 1)
+	ensure.DeepEqual(t, rejected[0].Path, "classes/Yak")
+
+	err := q.Send("POST", "classes/Yak", map[string]string{"n": "y"})
+	ensure.DeepEqual(t, err.(*parse.Error).Code, 111)
+	n, err := q.Len()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, n, 0)
+}
+
+func TestWriteQueueLocalError(t *testing.T) {
+	t.Parallel()
+	s := &flakyServer{t: t, err: errors.New("unsupported protocol scheme")}
+	var q *parse.WriteQueue
+	var lens []int
+	q = &parse.WriteQueue{
+		Client: &parse.Client{Transport: s},
+		Rejected: func(w parse.QueuedWrite, err error) {
+			n, lerr := q.Len()
+			ensure.Nil(t, lerr)
+			lens = append(lens, n)
+		},
+	}
+	ensure.Nil(t, q.Enqueue("POST", "classes/Yak", map[string]int{"n": 1}))
+	ensure.Nil(t, q.Enqueue("POST", "classes/Yak", map[string]int{"n": 2}))
+	ensure.Nil(t, q.Replay(context.Background()))
+	ensure.DeepEqual(t, lens, []int{0, 0})
+}
+
+func TestWriteQueueServerError(t *testing.T) {
+	t.Parallel()
+	s := &flakyServer{t: t, status: http.StatusServiceUnavailable}
+	q := &parse.WriteQueue{Client: &parse.Client{Transport: s}}
+	ensure.Nil(t, q.Send("DELETE", "classes/Yak/a", nil))
+	n, err := q.Len()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, n, 1)
+}
+
+func TestWriteQueueFileStore(t *testing.T) {
+	t.Parallel()
+	dir, err := ioutil.TempDir("", "parse-queue")
+	ensure.Nil(t, err)
+	defer os.RemoveAll(dir)
+	store := parse.FileQueueStore{Path: filepath.Join(dir, "writes.json")}
+
+	s := &flakyServer{t: t, down: true}
+	q := &parse.WriteQueue{Client: &parse.Client{Transport: s}, Store: store}
+	ensure.Nil(t, q.Send("POST", "classes/Yak", map[string]int{"n": 1}))
+	ensure.Nil(t, q.Send("DELETE", "classes/Yak/a", nil))
+
+	writes, err := store.Load()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(writes), 2)
+	ensure.DeepEqual(t, string(writes[0].Body), `{"n":1}`)
+
+	// A new queue, as after a restart, picks up the saved writes.
+	s.down = false
+	q = &parse.WriteQueue{Client: &parse.Client{Transport: s}, Store: store}
+	ensure.Nil(t, q.Replay(context.Background()))
+	ensure.DeepEqual(t, s.requests, []string{`POST /1/classes/Yak {"n":1}`, `DELETE /1/classes/Yak/a `})
+	ensure.DeepEqual(t, s.ids[0], writes[0].RequestID)
+	_, err = os.Stat(store.Path)
+	ensure.True(t, os.IsNotExist(err))
+}
+
+func TestWriteQueueRun(t *testing.T) {
+	t.Parallel()
+	s := &flakyServer{t: t}
+	q := &parse.WriteQueue{Client: &parse.Client{Transport: s}}
+	ensure.Nil(t, q.Enqueue("DELETE", "classes/Yak/a", nil))
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	ensure.DeepEqual(t, q.Run(ctx), context.Canceled)
+	n, err := q.Len()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, n, 0)
+}
+
+func TestWriteQueueRunClock(t *testing.T) {
+	t.Parallel()
+	clock := parsetest.NewClock(time.Date(2015, 6, 16, 1, 2, 3, 0, time.UTC))
+	s := &flakyServer{t: t, down: true}
+	q := &parse.WriteQueue{
+		Client:        &parse.Client{Transport: s, Clock: clock},
+		RetryInterval: time.Minute,
+	}
+	ensure.Nil(t, q.Enqueue("DELETE", "classes/Yak/a", nil))
+	ctx, cancel := context.WithCancel(context.Background())
+	done := make(chan error)
+	go func() { done <- q.Run(ctx) }()
+	for clock.Waiters() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	n, err := q.Len()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, n, 1)
+
+	s.down = false
+	clock.Advance(time.Minute)
+	for clock.Waiters() == 0 {
+		time.Sleep(time.Millisecond)
+	}
+	n, err = q.Len()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, n, 0)
+	cancel()
+	ensure.DeepEqual(t, <-done, context.Canceled)
+}
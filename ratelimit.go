@@ -0,0 +1,50 @@
+package parse
+
+import (
+	"fmt"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// RateLimitError is returned when the API rejects a request because the
+// request limit of the application was exceeded.
+type RateLimitError struct {
+	// RetryAfter is the delay requested by the server before trying again,
+	// from the Retry-After header. It is zero when the server didn't say.
+	RetryAfter time.Duration
+
+	// Err is the underlying *Error or *RawError.
+	Err error
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter == 0 {
+		return fmt.Sprintf("parse: rate limited: %s", e.Err)
+	}
+	return fmt.Sprintf("parse: rate limited, retry after %s: %s", e.RetryAfter, e.Err)
+}
+
+// Unwrap returns the underlying *Error or *RawError.
+func (e *RateLimitError) Unwrap() error {
+	return e.Err
+}
+
+// retryAfter returns the delay in the Retry-After header, which is either a
+// number of seconds or a date. It returns zero when there is no valid header.
+func retryAfter(h http.Header, now time.Time) time.Duration {
+	v := h.Get("Retry-After")
+	if v == "" {
+		return 0
+	}
+	if s, err := strconv.Atoi(v); err == nil {
+		if s < 0 {
+			return 0
+		}
+		return time.Duration(s) * time.Second
+	}
+	if t, err := http.ParseTime(v); err == nil && t.After(now) {
+		return t.Sub(now)
+	}
+	return 0
+}
@@ -0,0 +1,126 @@
+package parse_test
+
+import (
+	"net/http"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func rateLimited(t testing.TB, retryAfter string) *http.Response {
+	res := jsonResponse(t, http.StatusTooManyRequests, map[string]interface{}{
+		"code":  155,
+		"error": "request limit exceeded",
+	})
+	if retryAfter != "" {
+		res.Header.Set("Retry-After", retryAfter)
+	}
+	return res
+}
+
+func TestRateLimitError(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Header     string
+		RetryAfter time.Duration
+	}{
+		{"", 0},
+		{"5", 5 * time.Second},
+		{"-5", 0},
+		{"soon", 0},
+		{"Wed, 21 Oct 2015 07:28:00 GMT", 0},
+	}
+	for _, c := range cases {
+		client := &parse.Client{
+			Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+				return rateLimited(t, c.Header), nil
+			}),
+		}
+		_, err := client.Get(&url.URL{Path: "a"}, nil)
+		ensure.DeepEqual(t, err, &parse.RateLimitError{
+			RetryAfter: c.RetryAfter,
+			Err: &parse.Error{
+				Code:       155,
+				Message:    "request limit exceeded",
+				StatusCode: http.StatusTooManyRequests,
+				URL:        "https://api.parse.com/1/a",
+			},
+		}, c.Header)
+	}
+}
+
+func TestRateLimitErrorDate(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			at := time.Now().Add(time.Hour).UTC().Format(http.TimeFormat)
+			return rateLimited(t, at), nil
+		}),
+	}
+	_, err := c.Get(&url.URL{Path: "a"}, nil)
+	rerr, ok := err.(*parse.RateLimitError)
+	ensure.True(t, ok)
+	ensure.True(t, rerr.RetryAfter > 59*time.Minute && rerr.RetryAfter <= time.Hour, rerr.RetryAfter)
+	ensure.Err(t, err, regexp.MustCompile(`rate limited, retry after 59m\d.*code=155`))
+}
+
+func TestRateLimitRetry(t *testing.T) {
+	t.Parallel()
+	calls := 0
+	c := &parse.Client{
+		Retry: &parse.RetryPolicy{
+			MaxAttempts: 2,
+			BaseDelay:   time.Millisecond,
+			RateLimited: true,
+		},
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			if calls == 1 {
+				return rateLimited(t, "0"), nil
+			}
+			return jsonResponse(t, http.StatusCreated, map[string]string{"objectId": "a"}), nil
+		}),
+	}
+	_, err := c.Post(&url.URL{Path: "classes/Yak"}, map[string]string{}, nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, calls, 2)
+}
+
+func TestRateLimitRetryTooLong(t *testing.T) {
+	t.Parallel()
+	calls := 0
+	c := &parse.Client{
+		Retry: &parse.RetryPolicy{
+			MaxAttempts: 2,
+			MaxDelay:    time.Second,
+			RateLimited: true,
+		},
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			return rateLimited(t, "60"), nil
+		}),
+	}
+	_, err := c.Get(&url.URL{Path: "a"}, nil)
+	ensure.Err(t, err, regexp.MustCompile("retry after 1m0s"))
+	ensure.DeepEqual(t, calls, 1)
+}
+
+func TestRateLimitNotRetriedByDefault(t *testing.T) {
+	t.Parallel()
+	calls := 0
+	c := &parse.Client{
+		Retry: &parse.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond},
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			return rateLimited(t, ""), nil
+		}),
+	}
+	_, err := c.Get(&url.URL{Path: "a"}, nil)
+	_, ok := err.(*parse.RateLimitError)
+	ensure.True(t, ok)
+	ensure.DeepEqual(t, calls, 1)
+}
@@ -0,0 +1,106 @@
+package parse
+
+import (
+	"bytes"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+)
+
+const redacted = "REDACTED"
+
+// The query parameters whose values are redacted. Logging in with GET /login
+// for example sends the password in the query.
+var sensitiveParams = map[string]bool{
+	"password":      true,
+	"sessiontoken":  true,
+	"masterkey":     true,
+	"restapikey":    true,
+	"javascriptkey": true,
+	"clientkey":     true,
+}
+
+// The headers whose values are redacted.
+var sensitiveHeaders = []string{
+	masterKeyHeader,
+	restAPIKeyHeader,
+	sessionTokenHeader,
+	javaScriptKeyHeader,
+	"X-Parse-Client-Key",
+	"Authorization",
+	"Cookie",
+	"Set-Cookie",
+}
+
+// sensitiveJSON matches the values of the redacted JSON fields, such as the
+// password of a signup or the session token of a login response.
+var sensitiveJSON = regexp.MustCompile(`(?i)("(?:password|sessionToken|masterKey|restAPIKey|javascriptKey|clientKey|access_token|refresh_token)"\s*:\s*)"(?:[^"\\]|\\.)*"`)
+
+// redactURL returns the URL with the password of the user info and the values
+// of sensitive query parameters replaced.
+func (c *Client) redactURL(u *url.URL) string {
+	if u == nil {
+		return ""
+	}
+	r := *u
+	if _, ok := r.User.Password(); ok {
+		r.User = url.UserPassword(r.User.Username(), redacted)
+	}
+	if r.RawQuery != "" {
+		q := r.Query()
+		changed := false
+		for k, v := range q {
+			if sensitiveParams[strings.ToLower(k)] {
+				for i := range v {
+					v[i] = redacted
+				}
+				changed = true
+			}
+		}
+		if changed {
+			r.RawQuery = q.Encode()
+		}
+	}
+	return string(c.redactPatterns([]byte(r.String())))
+}
+
+func redactHeader(h http.Header) http.Header {
+	r := make(http.Header, len(h))
+	for k, v := range h {
+		r[k] = v
+	}
+	for _, k := range sensitiveHeaders {
+		if r.Get(k) != "" {
+			r.Set(k, redacted)
+		}
+	}
+	return r
+}
+
+// redactDump replaces the values of sensitive JSON fields, and the matches of
+// the Redact patterns.
+func (c *Client) redactDump(dump []byte) []byte {
+	return c.redactPatterns(sensitiveJSON.ReplaceAll(dump, []byte(`$1"`+redacted+`"`)))
+}
+
+func (c *Client) redactPatterns(b []byte) []byte {
+	for _, re := range c.Redact {
+		b = re.ReplaceAllLiteral(b, []byte(redacted))
+	}
+	return b
+}
+
+// redactSecrets replaces the values of the sensitive headers of a request
+// wherever they appear in b, such as a session token echoed by an error page,
+// as well as what redactDump replaces.
+func (c *Client) redactSecrets(b []byte, h http.Header) []byte {
+	for _, k := range sensitiveHeaders {
+		for _, v := range h[http.CanonicalHeaderKey(k)] {
+			if len(v) >= 4 && bytes.Contains(b, []byte(v)) {
+				b = bytes.Replace(b, []byte(v), []byte(redacted), -1)
+			}
+		}
+	}
+	return c.redactDump(b)
+}
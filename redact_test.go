@@ -0,0 +1,83 @@
+package parse_test
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestErrorsRedactSecrets(t *testing.T) {
+	t.Parallel()
+	cr := parse.SessionToken{
+		ApplicationID: "app",
+		RestAPIKey:    "rest-key-secret",
+		SessionToken:  "r:session-secret",
+	}
+	bodies := []string{
+		`{"code":209,"error":"invalid session token r:session-secret"}`,
+		`<html>bad key rest-key-secret</html>`,
+		`["sessionToken", {"sessionToken":"r:other-secret","password":"hunter2"}]`,
+	}
+	for _, body := range bodies {
+		c := &parse.Client{
+			Credentials: cr,
+			Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+				return &http.Response{
+					StatusCode: http.StatusBadRequest,
+					Body:       ioutil.NopCloser(strings.NewReader(body)),
+				}, nil
+			}),
+		}
+		u := url.URL{Path: "login", RawQuery: "username=al&password=hunter2"}
+		_, err := c.Get(&u, nil)
+		ensure.NotNil(t, err)
+		for _, secret := range []string{"r:session-secret", "rest-key-secret", "r:other-secret", "hunter2"} {
+			ensure.False(t, strings.Contains(err.Error(), secret), err.Error())
+			switch e := err.(type) {
+			case *parse.Error:
+				ensure.False(t, strings.Contains(e.URL, secret), e.URL)
+			case *parse.RawError:
+				ensure.False(t, strings.Contains(string(e.Body), secret), string(e.Body))
+				ensure.False(t, strings.Contains(e.URL, secret), e.URL)
+				ensure.StringContains(t, string(e.Body), "REDACTED")
+			}
+		}
+	}
+}
+
+func TestRedactPatterns(t *testing.T) {
+	t.Parallel()
+	var debug bytes.Buffer
+	var logs []parse.RequestLog
+	c := &parse.Client{
+		Debug:  &debug,
+		Logger: parse.LoggerFunc(func(l parse.RequestLog) { logs = append(logs, l) }),
+		Redact: []*regexp.Regexp{regexp.MustCompile(`yak-token-[0-9]+`)},
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Header.Get("X-Yak-Token"), "yak-token-42")
+			return &http.Response{
+				StatusCode: http.StatusForbidden,
+				Body:       ioutil.NopCloser(strings.NewReader("denied yak-token-42")),
+			}, nil
+		}),
+	}
+	req := http.Request{
+		Method: "GET",
+		URL:    &url.URL{Path: "classes/Yak", RawQuery: "token=yak-token-42"},
+		Header: http.Header{"X-Yak-Token": {"yak-token-42"}},
+	}
+	_, err := c.Do(&req, nil, nil)
+	ensure.NotNil(t, err)
+	ensure.DeepEqual(t, err.(*parse.RawError).Body, []byte("denied REDACTED"))
+	ensure.DeepEqual(t, err.(*parse.RawError).URL, "https://api.parse.com/1/classes/Yak?token=REDACTED")
+	ensure.DeepEqual(t, logs[0].URL, "https://api.parse.com/1/classes/Yak?token=REDACTED")
+	ensure.False(t, strings.Contains(debug.String(), "yak-token-42"), debug.String())
+	ensure.StringContains(t, debug.String(), "X-Yak-Token: REDACTED")
+}
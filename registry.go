@@ -0,0 +1,73 @@
+package parse
+
+import (
+	"fmt"
+	"sort"
+	"sync"
+)
+
+// Registry holds the Clients of several applications by name, such as "prod",
+// "staging" or a partner application. The zero value is ready to use and safe
+// for concurrent use.
+type Registry struct {
+	mu      sync.RWMutex
+	clients map[string]*Client
+}
+
+// Register adds the Client under the name, replacing any Client registered
+// under it before.
+func (r *Registry) Register(name string, c *Client) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.clients == nil {
+		r.clients = make(map[string]*Client)
+	}
+	r.clients[name] = c
+}
+
+// RegisterConfig registers the Client of the config file read by
+// LoadClientConfig.
+func (r *Registry) RegisterConfig(name, file string) error {
+	c, err := LoadClientConfig(file)
+	if err != nil {
+		return err
+	}
+	r.Register(name, c)
+	return nil
+}
+
+// Remove removes the Client registered under the name.
+func (r *Registry) Remove(name string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.clients, name)
+}
+
+// Lookup returns the Client registered under the name.
+func (r *Registry) Lookup(name string) (*Client, bool) {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	c, ok := r.clients[name]
+	return c, ok
+}
+
+// Client returns the Client registered under the name, or an error naming
+// the registered applications.
+func (r *Registry) Client(name string) (*Client, error) {
+	if c, ok := r.Lookup(name); ok {
+		return c, nil
+	}
+	return nil, fmt.Errorf("parse: no client registered for %q, have %q", name, r.Names())
+}
+
+// Names returns the registered names in order.
+func (r *Registry) Names() []string {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	names := make([]string, 0, len(r.clients))
+	for name := range r.clients {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
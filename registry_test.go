@@ -0,0 +1,53 @@
+package parse_test
+
+import (
+	"os"
+	"regexp"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestRegistry(t *testing.T) {
+	t.Parallel()
+	var r parse.Registry
+	prod := &parse.Client{Credentials: defaultRestAPIKey}
+	staging := &parse.Client{}
+	r.Register("prod", prod)
+	r.Register("staging", staging)
+
+	c, err := r.Client("prod")
+	ensure.Nil(t, err)
+	ensure.True(t, c == prod)
+	c, ok := r.Lookup("staging")
+	ensure.True(t, ok)
+	ensure.True(t, c == staging)
+	ensure.DeepEqual(t, r.Names(), []string{"prod", "staging"})
+
+	r.Remove("staging")
+	_, ok = r.Lookup("staging")
+	ensure.False(t, ok)
+	_, err = r.Client("staging")
+	ensure.Err(t, err, regexp.MustCompile(`no client registered for "staging", have \["prod"\]`))
+}
+
+func TestRegistryEmpty(t *testing.T) {
+	t.Parallel()
+	var r parse.Registry
+	_, ok := r.Lookup("prod")
+	ensure.False(t, ok)
+	ensure.DeepEqual(t, r.Names(), []string{})
+}
+
+func TestRegistryConfig(t *testing.T) {
+	t.Parallel()
+	name := writeConfig(t, `{"applicationId": "a", "restApiKey": "r", "serverURL": "https://partner.example.com/parse"}`)
+	defer os.Remove(name)
+	var r parse.Registry
+	ensure.Nil(t, r.RegisterConfig("partner", name))
+	c, err := r.Client("partner")
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, c.BaseURL.Host, "partner.example.com")
+	ensure.NotNil(t, r.RegisterConfig("missing", "/nonexistent/parse.json"))
+}
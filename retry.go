@@ -0,0 +1,214 @@
+package parse
+
+import (
+	"bytes"
+	"io"
+	"io/ioutil"
+	"math"
+	"math/rand"
+	"net/http"
+	"time"
+)
+
+const (
+	defaultRetryBaseDelay  = 100 * time.Millisecond
+	defaultRetryMaxDelay   = 10 * time.Second
+	defaultRetryMultiplier = 2
+)
+
+// The status codes retried by default, which indicate a transient failure of
+// the server or a proxy in front of it.
+var defaultRetryStatusCodes = []int{
+	http.StatusInternalServerError,
+	http.StatusBadGateway,
+	http.StatusServiceUnavailable,
+	http.StatusGatewayTimeout,
+}
+
+// RetryPolicy configures how a Client retries failed requests. The delay
+// before each retry grows exponentially from BaseDelay by Multiplier up to
+// MaxDelay, and is randomly reduced by up to the Jitter fraction so that
+// clients failing together don't retry together.
+//
+// Only GET, HEAD, PUT and DELETE requests are retried unless RetryPOST or
+// IdempotentPOST is set, since retrying a POST that did reach the server can
+// create an object twice. Rate limited requests are not processed by the
+// server, so with RateLimited set they are retried whatever the method.
+type RetryPolicy struct {
+	// MaxAttempts is the total number of attempts, including the first one.
+	// Values below 2 disable retries.
+	MaxAttempts int
+
+	// BaseDelay is the delay before the first retry. Defaults to 100ms.
+	BaseDelay time.Duration
+
+	// MaxDelay caps the delay between attempts. Defaults to 10s.
+	MaxDelay time.Duration
+
+	// Multiplier is the growth factor of the delay. Defaults to 2.
+	Multiplier float64
+
+	// Jitter is the fraction, between 0 and 1, of the delay that is
+	// randomized.
+	Jitter float64
+
+	// StatusCodes are the retried response status codes. Defaults to 500,
+	// 502, 503 and 504.
+	StatusCodes []int
+
+	// RetryPOST allows retrying POST requests.
+	RetryPOST bool
+
+	// IdempotentPOST allows retrying POST requests that have an
+	// X-Parse-Request-Id header, which Parse Server with its idempotency
+	// option enabled doesn't process twice. See Client.IdempotencyKeys.
+	IdempotentPOST bool
+
+	// RateLimited retries requests rejected with status 429 after the delay
+	// requested in the Retry-After header, or the usual delay if there is
+	// none. When the requested delay is longer than MaxDelay the
+	// RateLimitError is returned instead of waiting.
+	RateLimited bool
+
+	// Retryable optionally replaces the default decision of whether an
+	// attempt is retried. It is given either the response or the error
+	// returned by the Transport. By default errors, which are connection
+	// failures such as resets and timeouts, and responses with one of the
+	// StatusCodes are retried. A *CircuitOpenError is not retried.
+	Retryable func(res *http.Response, err error) bool
+}
+
+func (p *RetryPolicy) method(req *http.Request) bool {
+	switch req.Method {
+	case "", "GET", "HEAD", "PUT", "DELETE":
+		return true
+	case "POST":
+		return p.RetryPOST || (p.IdempotentPOST && req.Header.Get(requestIDHeader) != "")
+	}
+	return false
+}
+
+func (p *RetryPolicy) retryable(res *http.Response, err error) bool {
+	if p.Retryable != nil {
+		return p.Retryable(res, err)
+	}
+	if err != nil {
+		_, open := err.(*CircuitOpenError)
+		return !open
+	}
+	codes := p.StatusCodes
+	if codes == nil {
+		codes = defaultRetryStatusCodes
+	}
+	for _, code := range codes {
+		if res.StatusCode == code {
+			return true
+		}
+	}
+	return false
+}
+
+func (p *RetryPolicy) maxDelay() time.Duration {
+	if p.MaxDelay <= 0 {
+		return defaultRetryMaxDelay
+	}
+	return p.MaxDelay
+}
+
+// retry decides whether the given failed attempt is retried, and returns the
+// time to wait before doing so.
+func (p *RetryPolicy) retry(req *http.Request, res *http.Response, err error, attempt int, now time.Time) (time.Duration, bool) {
+	if p.RateLimited && err == nil && res.StatusCode == http.StatusTooManyRequests {
+		d := retryAfter(res.Header, now)
+		if d == 0 {
+			return p.delay(attempt), true
+		}
+		return d, d <= p.maxDelay()
+	}
+	if !p.method(req) || !p.retryable(res, err) {
+		return 0, false
+	}
+	return p.delay(attempt), true
+}
+
+// delay returns the backoff delay after the given failed attempt.
+func (p *RetryPolicy) delay(attempt int) time.Duration {
+	base, max, mult := p.BaseDelay, p.maxDelay(), p.Multiplier
+	if base <= 0 {
+		base = defaultRetryBaseDelay
+	}
+	if mult < 1 {
+		mult = defaultRetryMultiplier
+	}
+	d := float64(base) * math.Pow(mult, float64(attempt-1))
+	if d > float64(max) {
+		d = float64(max)
+	}
+	if p.Jitter > 0 {
+		d -= d * math.Min(p.Jitter, 1) * rand.Float64()
+	}
+	return time.Duration(d)
+}
+
+// send performs the request using the Transport, retrying it according to the
+// Retry policy.
+func (c *Client) send(req *http.Request) (*http.Response, error) {
+	if c.DryRun && isWrite(requestMethod(req)) {
+		return c.roundTrip(RoundTripperFunc(c.dryRun), req, 1)
+	}
+	t := c.transport()
+	if c.Gzip || c.GzipRequests > 0 {
+		t = c.gzipTransport(t)
+	}
+	if c.MaxURLLength > 0 {
+		t = c.overrideMethod(t)
+	}
+	p := c.Retry
+	if p == nil || p.MaxAttempts < 2 || !(p.RateLimited || p.method(req)) {
+		return c.roundTrip(t, req, 1)
+	}
+
+	// Without GetBody the body is buffered so that it can be sent again.
+	if req.Body != nil && req.GetBody == nil {
+		body, err := ioutil.ReadAll(req.Body)
+		req.Body.Close()
+		if err != nil {
+			return nil, err
+		}
+		req.Body = ioutil.NopCloser(bytes.NewReader(body))
+		req.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(body)), nil
+		}
+	}
+
+	for attempt := 1; ; attempt++ {
+		if attempt > 1 && req.GetBody != nil {
+			body, err := req.GetBody()
+			if err != nil {
+				return nil, err
+			}
+			req.Body = body
+		}
+		res, err := c.roundTrip(t, req, attempt)
+		if attempt == p.MaxAttempts || req.Context().Err() != nil {
+			return res, err
+		}
+		d, ok := p.retry(req, res, err, attempt, c.clock().Now())
+		if !ok {
+			return res, err
+		}
+		if err == nil {
+			io.Copy(ioutil.Discard, res.Body)
+			res.Body.Close()
+		}
+		if c.Metrics != nil {
+			c.Metrics.IncRetry(c.endpoint(req.URL), requestMethod(req))
+		}
+
+		select {
+		case <-c.clock().After(d):
+		case <-req.Context().Done():
+			return nil, req.Context().Err()
+		}
+	}
+}
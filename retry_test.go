@@ -0,0 +1,243 @@
+package parse_test
+
+import (
+	"context"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestRetryStatusCode(t *testing.T) {
+	t.Parallel()
+	var bodies []string
+	c := &parse.Client{
+		Retry: &parse.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond},
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			b, err := ioutil.ReadAll(r.Body)
+			ensure.Nil(t, err)
+			bodies = append(bodies, string(b))
+			if len(bodies) < 3 {
+				return jsonResponse(t, http.StatusServiceUnavailable, map[string]string{}), nil
+			}
+			return jsonResponse(t, http.StatusOK, map[string]int{"answer": 42}), nil
+		}),
+	}
+	var res map[string]int
+	_, err := c.Put(&url.URL{Path: "classes/Yak/a"}, map[string]string{"name": "Al"}, &res)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res, map[string]int{"answer": 42})
+	ensure.DeepEqual(t, bodies, []string{`{"name":"Al"}`, `{"name":"Al"}`, `{"name":"Al"}`})
+}
+
+func TestRetryNetworkError(t *testing.T) {
+	t.Parallel()
+	calls := 0
+	c := &parse.Client{
+		Retry: &parse.RetryPolicy{MaxAttempts: 2, BaseDelay: time.Millisecond},
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			if calls == 1 {
+				return nil, errors.New("connection reset by peer")
+			}
+			return jsonResponse(t, http.StatusOK, map[string]int{}), nil
+		}),
+	}
+	_, err := c.Get(&url.URL{Path: "classes/Yak/a"}, nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, calls, 2)
+}
+
+func TestRetryGivesUp(t *testing.T) {
+	t.Parallel()
+	calls := 0
+	c := &parse.Client{
+		Retry: &parse.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond},
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			return jsonResponse(t, http.StatusBadGateway, map[string]interface{}{
+				"code":  1,
+				"error": "bad gateway",
+			}), nil
+		}),
+	}
+	_, err := c.Get(&url.URL{Path: "classes/Yak/a"}, nil)
+	ensure.DeepEqual(t, err, &parse.Error{
+		Code:       1,
+		Message:    "bad gateway",
+		StatusCode: http.StatusBadGateway,
+		URL:        "https://api.parse.com/1/classes/Yak/a",
+	})
+	ensure.DeepEqual(t, calls, 3)
+}
+
+func TestRetrySkips(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Name   string
+		Policy parse.RetryPolicy
+		Method string
+		Code   int
+		Calls  int
+	}{
+		{"not retryable code", parse.RetryPolicy{MaxAttempts: 3}, "GET", http.StatusNotFound, 1},
+		{"post", parse.RetryPolicy{MaxAttempts: 3}, "POST", http.StatusServiceUnavailable, 1},
+		{"retry post", parse.RetryPolicy{MaxAttempts: 3, RetryPOST: true}, "POST", http.StatusServiceUnavailable, 3},
+		{"custom codes", parse.RetryPolicy{MaxAttempts: 2, StatusCodes: []int{http.StatusNotFound}}, "GET", http.StatusNotFound, 2},
+		{"single attempt", parse.RetryPolicy{MaxAttempts: 1}, "GET", http.StatusServiceUnavailable, 1},
+		{
+			"custom retryable",
+			parse.RetryPolicy{
+				MaxAttempts: 3,
+				Retryable:   func(res *http.Response, err error) bool { return false },
+			},
+			"GET", http.StatusServiceUnavailable, 1,
+		},
+	}
+	for _, c := range cases {
+		calls := 0
+		c.Policy.BaseDelay = time.Millisecond
+		client := &parse.Client{
+			Retry: &c.Policy,
+			Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+				calls++
+				return jsonResponse(t, c.Code, map[string]string{}), nil
+			}),
+		}
+		client.Do(&http.Request{Method: c.Method, URL: &url.URL{Path: "a"}}, nil, nil)
+		ensure.DeepEqual(t, calls, c.Calls, c.Name)
+	}
+}
+
+func TestRetryCancel(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	calls := 0
+	c := &parse.Client{
+		Retry: &parse.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Hour},
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			cancel()
+			return &http.Response{
+				StatusCode: http.StatusServiceUnavailable,
+				Body:       ioutil.NopCloser(strings.NewReader("")),
+			}, nil
+		}),
+	}
+	_, err := c.GetCtx(ctx, &url.URL{Path: "a"}, nil)
+	ensure.Err(t, err, regexp.MustCompile("status=503"))
+	ensure.DeepEqual(t, calls, 1)
+}
+
+func TestRetryCancelDuringDelay(t *testing.T) {
+	t.Parallel()
+	ctx, cancel := context.WithCancel(context.Background())
+	c := &parse.Client{
+		Retry: &parse.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Hour},
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			time.AfterFunc(time.Millisecond, cancel)
+			return nil, errors.New("connection refused")
+		}),
+	}
+	_, err := c.GetCtx(ctx, &url.URL{Path: "a"}, nil)
+	ensure.Err(t, err, regexp.MustCompile("context canceled"))
+}
+
+func TestRetryMaxDelay(t *testing.T) {
+	t.Parallel()
+	calls := 0
+	c := &parse.Client{
+		Retry: &parse.RetryPolicy{
+			MaxAttempts: 3,
+			BaseDelay:   time.Hour,
+			MaxDelay:    time.Millisecond,
+			Jitter:      0.5,
+		},
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			return nil, errors.New("connection refused")
+		}),
+	}
+	_, err := c.Get(&url.URL{Path: "a"}, nil)
+	ensure.Err(t, err, regexp.MustCompile("connection refused"))
+	ensure.DeepEqual(t, calls, 3)
+}
+
+func TestRetryIdempotentPOST(t *testing.T) {
+	t.Parallel()
+	for _, keys := range []bool{false, true} {
+		calls := 0
+		c := &parse.Client{
+			IdempotencyKeys: keys,
+			Retry:           &parse.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond, IdempotentPOST: true},
+			Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+				calls++
+				return jsonResponse(t, http.StatusServiceUnavailable, map[string]string{}), nil
+			}),
+		}
+		c.Post(&url.URL{Path: "classes/Yak"}, map[string]string{}, nil)
+		if keys {
+			ensure.DeepEqual(t, calls, 3)
+		} else {
+			ensure.DeepEqual(t, calls, 1)
+		}
+	}
+}
+
+func TestRetryGetBody(t *testing.T) {
+	t.Parallel()
+	var bodies []string
+	c := &parse.Client{
+		Retry: &parse.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond},
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			b, err := ioutil.ReadAll(r.Body)
+			ensure.Nil(t, err)
+			bodies = append(bodies, string(b))
+			if len(bodies) < 3 {
+				return jsonResponse(t, http.StatusServiceUnavailable, map[string]string{}), nil
+			}
+			return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+		}),
+	}
+	gets := 0
+	req := http.Request{
+		Method: "PUT",
+		URL:    &url.URL{Path: "classes/Yak/a"},
+		Body:   ioutil.NopCloser(strings.NewReader("first")),
+		GetBody: func() (io.ReadCloser, error) {
+			gets++
+			return ioutil.NopCloser(strings.NewReader("again")), nil
+		},
+	}
+	_, err := c.Do(&req, nil, nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, bodies, []string{"first", "again", "again"})
+	ensure.DeepEqual(t, gets, 2)
+}
+
+func TestDoSetsGetBody(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.NotNil(t, r.GetBody)
+			for i := 0; i < 2; i++ {
+				body, err := r.GetBody()
+				ensure.Nil(t, err)
+				b, err := ioutil.ReadAll(body)
+				ensure.Nil(t, err)
+				ensure.DeepEqual(t, string(b), `{"name":"Al"}`)
+			}
+			return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+		}),
+	}
+	_, err := c.Put(&url.URL{Path: "classes/Yak/a"}, map[string]string{"name": "Al"}, nil)
+	ensure.Nil(t, err)
+}
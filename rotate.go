@@ -0,0 +1,59 @@
+package parse
+
+import (
+	"errors"
+	"net/http"
+	"sync"
+)
+
+var errNoRotatingCredentials = errors.New("parse: RotatingCredentials have not been set")
+
+// RotatingCredentials are Credentials that can be replaced while requests are
+// in flight, to rotate keys without restarting. Every request uses the
+// Credentials set when it starts. Use a pointer:
+//
+//	cr := &parse.RotatingCredentials{}
+//	cr.Set(parse.MasterKey{ApplicationID: id, MasterKey: oldKey})
+//	c := &parse.Client{Credentials: cr}
+//	...
+//	cr.Set(parse.MasterKey{ApplicationID: id, MasterKey: newKey})
+type RotatingCredentials struct {
+	mu sync.RWMutex
+	cr Credentials
+}
+
+// Set replaces the Credentials.
+func (r *RotatingCredentials) Set(cr Credentials) {
+	r.mu.Lock()
+	r.cr = cr
+	r.mu.Unlock()
+}
+
+// Get returns the current Credentials.
+func (r *RotatingCredentials) Get() Credentials {
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+	return r.cr
+}
+
+// Modify uses the current Credentials.
+func (r *RotatingCredentials) Modify(req *http.Request) error {
+	cr := r.Get()
+	if cr == nil {
+		return errNoRotatingCredentials
+	}
+	return cr.Modify(req)
+}
+
+// CredentialsFunc provides the Credentials of every request, for example from
+// a secret store that caches them.
+type CredentialsFunc func() (Credentials, error)
+
+// Modify uses the Credentials returned by f.
+func (f CredentialsFunc) Modify(req *http.Request) error {
+	cr, err := f()
+	if err != nil {
+		return err
+	}
+	return cr.Modify(req)
+}
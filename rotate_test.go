@@ -0,0 +1,67 @@
+package parse_test
+
+import (
+	"errors"
+	"net/http"
+	"regexp"
+	"sync"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestRotatingCredentials(t *testing.T) {
+	t.Parallel()
+	var mu sync.Mutex
+	keys := make(map[string]int)
+	cr := &parse.RotatingCredentials{}
+	c := &parse.Client{
+		Credentials: cr,
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			mu.Lock()
+			keys[r.Header.Get("X-Parse-Master-Key")]++
+			mu.Unlock()
+			return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+		}),
+	}
+	_, err := c.Get(nil, nil)
+	ensure.Err(t, err, regexp.MustCompile("have not been set"))
+
+	cr.Set(parse.MasterKey{ApplicationID: defaultApplicationID, MasterKey: "old"})
+	var wg sync.WaitGroup
+	for i := 0; i < 10; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := c.Get(nil, nil)
+			ensure.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+	cr.Set(parse.MasterKey{ApplicationID: defaultApplicationID, MasterKey: "new"})
+	_, err = c.Get(nil, nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, keys, map[string]int{"old": 10, "new": 1})
+	ensure.DeepEqual(t, cr.Get(), parse.MasterKey{ApplicationID: defaultApplicationID, MasterKey: "new"})
+}
+
+func TestCredentialsFunc(t *testing.T) {
+	t.Parallel()
+	fail := errors.New("secret store unavailable")
+	var err error
+	c := &parse.Client{
+		Credentials: parse.CredentialsFunc(func() (parse.Credentials, error) {
+			return defaultRestAPIKey, err
+		}),
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Header.Get("X-Parse-REST-API-Key"), defaultRestAPIKey.RestAPIKey)
+			return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+		}),
+	}
+	_, e := c.Get(nil, nil)
+	ensure.Nil(t, e)
+	err = fail
+	_, e = c.Get(nil, nil)
+	ensure.True(t, e == fail)
+}
@@ -0,0 +1,152 @@
+package parse
+
+import (
+	"encoding/json"
+	"errors"
+	"net/url"
+)
+
+const (
+	schemasPath = "schemas/"
+	purgePath   = "purge/"
+)
+
+// Field types.
+const (
+	FieldString   = "String"
+	FieldNumber   = "Number"
+	FieldBoolean  = "Boolean"
+	FieldDate     = "Date"
+	FieldObject   = "Object"
+	FieldArray    = "Array"
+	FieldGeoPoint = "GeoPoint"
+	FieldFile     = "File"
+	FieldPointer  = "Pointer"
+	FieldRelation = "Relation"
+	FieldBytes    = "Bytes"
+	FieldPolygon  = "Polygon"
+	FieldACL      = "ACL"
+)
+
+var (
+	errEmptyClassName    = errors.New("parse: cannot use empty class name")
+	errPurgeNotConfirmed = errors.New("parse: purge confirmation must match the class name")
+)
+
+// Field is the definition of a field in a class.
+type Field struct {
+	Type string `json:"type"`
+
+	// TargetClass is required for Pointer and Relation fields.
+	TargetClass string `json:"targetClass,omitempty"`
+
+	Required     bool        `json:"required,omitempty"`
+	DefaultValue interface{} `json:"defaultValue,omitempty"`
+}
+
+// Schema is the definition of a class.
+type Schema struct {
+	ClassName             string           `json:"className"`
+	Fields                map[string]Field `json:"fields,omitempty"`
+	ClassLevelPermissions *CLP             `json:"classLevelPermissions,omitempty"`
+	Indexes               map[string]Index `json:"indexes,omitempty"`
+}
+
+// SchemaClient provides access to the schemas endpoint. It requires the Master
+// Key.
+type SchemaClient struct {
+	Client *Client
+}
+
+func schemaURL(className string) *url.URL {
+	return &url.URL{Path: schemasPath + className}
+}
+
+// List fetches the schemas of all classes.
+func (c *SchemaClient) List() ([]*Schema, error) {
+	var res struct {
+		Results []*Schema `json:"results"`
+	}
+	if _, err := c.Client.Get(schemaURL(""), &res); err != nil {
+		return nil, err
+	}
+	return res.Results, nil
+}
+
+// Get fetches the schema of the given class.
+func (c *SchemaClient) Get(className string) (*Schema, error) {
+	if className == "" {
+		return nil, errEmptyClassName
+	}
+	var s Schema
+	if _, err := c.Client.Get(schemaURL(className), &s); err != nil {
+		return nil, err
+	}
+	return &s, nil
+}
+
+// Create creates a new class, and returns the resulting schema.
+func (c *SchemaClient) Create(s *Schema) (*Schema, error) {
+	if s.ClassName == "" {
+		return nil, errEmptyClassName
+	}
+	var res Schema
+	if _, err := c.Client.Post(schemaURL(s.ClassName), s, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Update adds the fields and sets the permissions in s on an existing class,
+// and returns the resulting schema. Existing fields cannot be modified, they
+// must be deleted and added again.
+func (c *SchemaClient) Update(s *Schema) (*Schema, error) {
+	if s.ClassName == "" {
+		return nil, errEmptyClassName
+	}
+	var res Schema
+	if _, err := c.Client.Put(schemaURL(s.ClassName), s, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// DeleteField removes the fields, and all their data, from a class.
+func (c *SchemaClient) DeleteField(className string, fields ...string) (*Schema, error) {
+	if className == "" {
+		return nil, errEmptyClassName
+	}
+	del := make(map[string]json.RawMessage, len(fields))
+	for _, f := range fields {
+		del[f] = json.RawMessage(`{"__op":"Delete"}`)
+	}
+	body := map[string]interface{}{"className": className, "fields": del}
+	var res Schema
+	if _, err := c.Client.Put(schemaURL(className), body, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// Delete removes a class. The class must not contain any objects.
+func (c *SchemaClient) Delete(className string) error {
+	if className == "" {
+		return errEmptyClassName
+	}
+	_, err := c.Client.Delete(schemaURL(className), nil)
+	return err
+}
+
+// Purge deletes all objects in a class, but keeps the class and its schema. As
+// a safeguard against wiping the wrong class, confirm must repeat the class
+// name.
+func (c *SchemaClient) Purge(className, confirm string) error {
+	if className == "" {
+		return errEmptyClassName
+	}
+	if confirm != className {
+		return errPurgeNotConfirmed
+	}
+	_, err := c.Client.Delete(&url.URL{Path: purgePath + className}, nil)
+	return err
+}
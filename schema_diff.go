@@ -0,0 +1,299 @@
+package parse
+
+import (
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"time"
+)
+
+// Kinds of SchemaDifference.
+const (
+	MissingClass      = "missing-class"
+	MissingField      = "missing-field"
+	FieldTypeMismatch = "type-mismatch"
+	ExtraField        = "extra-field"
+)
+
+var (
+	// Fields managed by the API which exist on every class.
+	defaultFields = map[string]bool{
+		"objectId":  true,
+		"createdAt": true,
+		"updatedAt": true,
+		"ACL":       true,
+	}
+
+	timeType       = reflect.TypeOf(time.Time{})
+	nullStringType = reflect.TypeOf(NullString{})
+	nullBoolType   = reflect.TypeOf(NullBool{})
+	nullIntType    = reflect.TypeOf(NullInt{})
+	nullFloatType  = reflect.TypeOf(NullFloat{})
+	dateType       = reflect.TypeOf(Date{})
+	geoPointType   = reflect.TypeOf(GeoPoint{})
+	pointerType    = reflect.TypeOf(Pointer{})
+	fileType       = reflect.TypeOf(File{})
+	relationType   = reflect.TypeOf(Relation{})
+	rawMessageType = reflect.TypeOf(json.RawMessage{})
+)
+
+// SchemaDifference is a difference between the expected schema of a class,
+// derived from a Go struct, and its actual schema.
+type SchemaDifference struct {
+	Kind      string `json:"kind"`
+	ClassName string `json:"className"`
+	Field     string `json:"field,omitempty"`
+	Expected  string `json:"expected,omitempty"`
+	Actual    string `json:"actual,omitempty"`
+}
+
+func (d SchemaDifference) String() string {
+	switch d.Kind {
+	case MissingClass:
+		return fmt.Sprintf("class %s is missing", d.ClassName)
+	case MissingField:
+		return fmt.Sprintf("field %s.%s of type %s is missing", d.ClassName, d.Field, d.Expected)
+	case FieldTypeMismatch:
+		return fmt.Sprintf("field %s.%s is of type %s instead of %s", d.ClassName, d.Field, d.Actual, d.Expected)
+	case ExtraField:
+		return fmt.Sprintf("field %s.%s of type %s is not defined", d.ClassName, d.Field, d.Actual)
+	}
+	return d.Kind
+}
+
+func (f Field) String() string {
+	if f.TargetClass != "" {
+		return fmt.Sprintf("%s<%s>", f.Type, f.TargetClass)
+	}
+	return f.Type
+}
+
+// StructSchema derives the schema of a class from the exported fields of a Go
+// struct. Field names are taken from the json tag, and the field type is
+// derived from the Go type. The parse tag can be used to provide what can't
+// be derived, as a comma separated list of options:
+//
+//	Owner parse.Pointer `json:"owner" parse:"target=_User,required"`
+//	Likes interface{}   `json:"likes" parse:"type=Relation,target=_User"`
+//
+// The index option adds an ascending index on the field, or a 2dsphere index
+// for GeoPoint fields, and the lenient option, used by Client.Lenient, is
+// ignored. A time.Time field is a String, since it encodes as one, so use Date
+// for Date fields. Fields with types that can hold any value, like
+// interface{}, are skipped unless their type is set. The default fields, like
+// objectId, are skipped.
+func StructSchema(className string, v interface{}) (*Schema, error) {
+	t := reflect.TypeOf(v)
+	for t != nil && t.Kind() == reflect.Ptr {
+		t = t.Elem()
+	}
+	if t == nil || t.Kind() != reflect.Struct {
+		return nil, fmt.Errorf("parse: cannot derive schema from %T", v)
+	}
+	s := &Schema{ClassName: className, Fields: make(map[string]Field)}
+	if err := structFields(t, s); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+func structFields(t reflect.Type, s *Schema) error {
+	for i := 0; i < t.NumField(); i++ {
+		sf := t.Field(i)
+		if sf.PkgPath != "" && !sf.Anonymous {
+			continue
+		}
+		name := sf.Name
+		if tag := sf.Tag.Get("json"); tag != "" {
+			if tag == "-" {
+				continue
+			}
+			if n := strings.Split(tag, ",")[0]; n != "" {
+				name = n
+			}
+		}
+		ft := sf.Type
+		for ft.Kind() == reflect.Ptr {
+			ft = ft.Elem()
+		}
+		if sf.Anonymous && sf.Tag.Get("json") == "" && ft.Kind() == reflect.Struct {
+			if err := structFields(ft, s); err != nil {
+				return err
+			}
+			continue
+		}
+		if defaultFields[name] {
+			continue
+		}
+
+		f := Field{Type: fieldType(ft)}
+		index := false
+		for _, opt := range strings.Split(sf.Tag.Get("parse"), ",") {
+			switch {
+			case opt == "":
+			case opt == "required":
+				f.Required = true
+			case opt == "index":
+				index = true
+			case opt == "lenient":
+			case strings.HasPrefix(opt, "type="):
+				f.Type = strings.TrimPrefix(opt, "type=")
+			case strings.HasPrefix(opt, "target="):
+				f.TargetClass = strings.TrimPrefix(opt, "target=")
+			default:
+				return fmt.Errorf("parse: unknown parse tag option %q on field %s", opt, sf.Name)
+			}
+		}
+		if f.Type == "" {
+			continue
+		}
+		s.Fields[name] = f
+		if index {
+			if s.Indexes == nil {
+				s.Indexes = make(map[string]Index)
+			}
+			key := Ascending(name)
+			if f.Type == FieldGeoPoint {
+				key = Sphere2D(name)
+			}
+			s.Indexes[fmt.Sprintf("%s_%v", name, key.Value)] = Index{key}
+		}
+	}
+	return nil
+}
+
+func fieldType(t reflect.Type) string {
+	switch t {
+	case timeType:
+		// A time.Time encodes as an RFC 3339 string rather than a Date.
+		return FieldString
+	case dateType:
+		return FieldDate
+	case nullStringType:
+		return FieldString
+	case nullBoolType:
+		return FieldBoolean
+	case nullIntType, nullFloatType:
+		return FieldNumber
+	case geoPointType:
+		return FieldGeoPoint
+	case pointerType:
+		return FieldPointer
+	case fileType:
+		return FieldFile
+	case relationType:
+		return FieldRelation
+	case rawMessageType:
+		return ""
+	}
+	switch t.Kind() {
+	case reflect.String:
+		return FieldString
+	case reflect.Bool:
+		return FieldBoolean
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64,
+		reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64,
+		reflect.Float32, reflect.Float64:
+		return FieldNumber
+	case reflect.Slice, reflect.Array:
+		if t.Elem().Kind() == reflect.Uint8 {
+			return FieldString
+		}
+		return FieldArray
+	case reflect.Map, reflect.Struct:
+		return FieldObject
+	}
+	return ""
+}
+
+// DiffSchema compares the expected schema of a class with its actual schema,
+// which is nil if the class does not exist. The differences are sorted by
+// field.
+func DiffSchema(expected, actual *Schema) []SchemaDifference {
+	if actual == nil {
+		return []SchemaDifference{{Kind: MissingClass, ClassName: expected.ClassName}}
+	}
+	var diffs []SchemaDifference
+	for name, want := range expected.Fields {
+		got, ok := actual.Fields[name]
+		if !ok {
+			diffs = append(diffs, SchemaDifference{
+				Kind:      MissingField,
+				ClassName: expected.ClassName,
+				Field:     name,
+				Expected:  want.String(),
+			})
+			continue
+		}
+		if got.Type != want.Type || (want.TargetClass != "" && got.TargetClass != want.TargetClass) {
+			diffs = append(diffs, SchemaDifference{
+				Kind:      FieldTypeMismatch,
+				ClassName: expected.ClassName,
+				Field:     name,
+				Expected:  want.String(),
+				Actual:    got.String(),
+			})
+		}
+	}
+	for name, got := range actual.Fields {
+		if _, ok := expected.Fields[name]; ok || defaultFields[name] {
+			continue
+		}
+		diffs = append(diffs, SchemaDifference{
+			Kind:      ExtraField,
+			ClassName: expected.ClassName,
+			Field:     name,
+			Actual:    got.String(),
+		})
+	}
+	sort.Sort(byField(diffs))
+	return diffs
+}
+
+type byField []SchemaDifference
+
+func (s byField) Len() int           { return len(s) }
+func (s byField) Less(i, j int) bool { return s[i].Field < s[j].Field }
+func (s byField) Swap(i, j int)      { s[i], s[j] = s[j], s[i] }
+
+// SchemaRegistry holds the expected schemas of classes, derived from Go
+// structs.
+type SchemaRegistry struct {
+	schemas []*Schema
+}
+
+// Register derives the expected schema of the class from the Go struct v,
+// using StructSchema.
+func (r *SchemaRegistry) Register(className string, v interface{}) error {
+	s, err := StructSchema(className, v)
+	if err != nil {
+		return err
+	}
+	r.schemas = append(r.schemas, s)
+	return nil
+}
+
+// Schemas returns the registered schemas in the order they were registered.
+func (r *SchemaRegistry) Schemas() []*Schema {
+	return r.schemas
+}
+
+// Diff compares the registered schemas with the live schemas fetched using
+// the SchemaClient.
+func (r *SchemaRegistry) Diff(c *SchemaClient) ([]SchemaDifference, error) {
+	live, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*Schema, len(live))
+	for _, s := range live {
+		byName[s.ClassName] = s
+	}
+	var diffs []SchemaDifference
+	for _, s := range r.schemas {
+		diffs = append(diffs, DiffSchema(s, byName[s.ClassName])...)
+	}
+	return diffs, nil
+}
@@ -0,0 +1,163 @@
+package parse_test
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+type yakBase struct {
+	ID        string     `json:"objectId,omitempty"`
+	CreatedAt *time.Time `json:"createdAt,omitempty"`
+}
+
+type yak struct {
+	yakBase
+	Name     string                 `json:"name" parse:"required"`
+	Age      int                    `json:"age,omitempty"`
+	Shaved   bool                   `json:"shaved"`
+	Born     parse.Date             `json:"born"`
+	Seen     time.Time              `json:"seen"`
+	Tags     []string               `json:"tags"`
+	Extra    map[string]interface{} `json:"extra"`
+	Location *parse.GeoPoint        `json:"location"`
+	Owner    parse.Pointer          `json:"owner" parse:"target=_User"`
+	Likes    interface{}            `json:"likes" parse:"type=Relation,target=_User"`
+	Anything interface{}            `json:"anything"`
+	Ignored  string                 `json:"-"`
+	NoTag    string
+	private  string
+}
+
+func TestStructSchema(t *testing.T) {
+	t.Parallel()
+	s, err := parse.StructSchema("Yak", &yak{})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, s, &parse.Schema{
+		ClassName: "Yak",
+		Fields: map[string]parse.Field{
+			"name":     {Type: parse.FieldString, Required: true},
+			"age":      {Type: parse.FieldNumber},
+			"shaved":   {Type: parse.FieldBoolean},
+			"born":     {Type: parse.FieldDate},
+			"seen":     {Type: parse.FieldString},
+			"tags":     {Type: parse.FieldArray},
+			"extra":    {Type: parse.FieldObject},
+			"location": {Type: parse.FieldGeoPoint},
+			"owner":    {Type: parse.FieldPointer, TargetClass: "_User"},
+			"likes":    {Type: parse.FieldRelation, TargetClass: "_User"},
+			"NoTag":    {Type: parse.FieldString},
+		},
+	})
+}
+
+// nullableYak is what parsegen -nullable generates for the schema in
+// TestStructSchemaNullable.
+type nullableYak struct {
+	ID   string            `json:"objectId,omitempty"`
+	Age  *parse.NullFloat  `json:"age,omitempty"`
+	Born *parse.Date       `json:"born,omitempty"`
+	Name *parse.NullString `json:"name,omitempty"`
+	Tame *parse.NullBool   `json:"tame,omitempty"`
+	Legs parse.NullInt     `json:"legs"`
+}
+
+func TestStructSchemaNullable(t *testing.T) {
+	t.Parallel()
+	actual := &parse.Schema{
+		ClassName: "Yak",
+		Fields: map[string]parse.Field{
+			"name": {Type: parse.FieldString},
+			"age":  {Type: parse.FieldNumber},
+			"tame": {Type: parse.FieldBoolean},
+			"born": {Type: parse.FieldDate},
+			"legs": {Type: parse.FieldNumber},
+		},
+	}
+	s, err := parse.StructSchema("Yak", &nullableYak{})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, s, actual)
+	ensure.DeepEqual(t, len(parse.DiffSchema(s, actual)), 0)
+}
+
+func TestStructSchemaErrors(t *testing.T) {
+	t.Parallel()
+	_, err := parse.StructSchema("Yak", 42)
+	ensure.Err(t, err, regexp.MustCompile("cannot derive schema from int"))
+	type bad struct {
+		Name string `parse:"unique"`
+	}
+	_, err = parse.StructSchema("Yak", bad{})
+	ensure.Err(t, err, regexp.MustCompile(`unknown parse tag option "unique" on field Name`))
+}
+
+func TestDiffSchema(t *testing.T) {
+	t.Parallel()
+	expected := &parse.Schema{
+		ClassName: "Yak",
+		Fields: map[string]parse.Field{
+			"name":  {Type: parse.FieldString},
+			"age":   {Type: parse.FieldNumber},
+			"owner": {Type: parse.FieldPointer, TargetClass: "_User"},
+			"herd":  {Type: parse.FieldPointer},
+		},
+	}
+	actual := &parse.Schema{
+		ClassName: "Yak",
+		Fields: map[string]parse.Field{
+			"objectId": {Type: parse.FieldString},
+			"ACL":      {Type: parse.FieldACL},
+			"age":      {Type: parse.FieldString},
+			"owner":    {Type: parse.FieldPointer, TargetClass: "Farmer"},
+			"herd":     {Type: parse.FieldPointer, TargetClass: "Herd"},
+			"color":    {Type: parse.FieldString},
+		},
+	}
+	diffs := parse.DiffSchema(expected, actual)
+	ensure.DeepEqual(t, diffs, []parse.SchemaDifference{
+		{Kind: parse.FieldTypeMismatch, ClassName: "Yak", Field: "age", Expected: "Number", Actual: "String"},
+		{Kind: parse.ExtraField, ClassName: "Yak", Field: "color", Actual: "String"},
+		{Kind: parse.MissingField, ClassName: "Yak", Field: "name", Expected: "String"},
+		{Kind: parse.FieldTypeMismatch, ClassName: "Yak", Field: "owner", Expected: "Pointer<_User>", Actual: "Pointer<Farmer>"},
+	})
+	ensure.DeepEqual(t, diffs[0].String(), "field Yak.age is of type String instead of Number")
+	ensure.DeepEqual(t, diffs[1].String(), "field Yak.color of type String is not defined")
+	ensure.DeepEqual(t, diffs[2].String(), "field Yak.name of type String is missing")
+}
+
+func TestSchemaRegistryDiff(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.URL.Path, "/1/schemas/")
+			return jsonResponse(t, http.StatusOK, map[string]interface{}{
+				"results": []interface{}{
+					map[string]interface{}{
+						"className": "Herd",
+						"fields": map[string]interface{}{
+							"name": map[string]string{"type": "String"},
+						},
+					},
+				},
+			}), nil
+		}),
+	}
+	type herd struct {
+		Name string `json:"name"`
+	}
+	var r parse.SchemaRegistry
+	ensure.Nil(t, r.Register("Herd", herd{}))
+	ensure.Nil(t, r.Register("Yak", yak{}))
+	ensure.Err(t, r.Register("Bad", "yak"), regexp.MustCompile("cannot derive schema"))
+	ensure.DeepEqual(t, len(r.Schemas()), 2)
+	diffs, err := r.Diff(&parse.SchemaClient{Client: c})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, diffs, []parse.SchemaDifference{
+		{Kind: parse.MissingClass, ClassName: "Yak"},
+	})
+	ensure.DeepEqual(t, diffs[0].String(), "class Yak is missing")
+}
@@ -0,0 +1,151 @@
+package parse
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+)
+
+// ApplyOptions configure SchemaRegistry.Apply.
+type ApplyOptions struct {
+	// DryRun only computes the SchemaPlan, without making any changes.
+	DryRun bool
+
+	// Indexes also creates the missing indexes.
+	Indexes bool
+}
+
+// SchemaPlan describes the changes needed to make the live schemas match the
+// registered schemas.
+type SchemaPlan struct {
+	// Create are the missing classes, with their fields and indexes.
+	Create []*Schema
+
+	// Update are the missing fields and indexes of existing classes.
+	Update []*Schema
+
+	// Conflicts are differences that cannot be resolved automatically, such
+	// as type mismatches. Fields only defined on the server are not
+	// conflicts.
+	Conflicts []SchemaDifference
+}
+
+// Empty reports if there are no changes or conflicts.
+func (p *SchemaPlan) Empty() bool {
+	return len(p.Create) == 0 && len(p.Update) == 0 && len(p.Conflicts) == 0
+}
+
+func (p *SchemaPlan) String() string {
+	var buf bytes.Buffer
+	write := func(action string, s *Schema) {
+		fmt.Fprintf(&buf, "%s class %s\n", action, s.ClassName)
+		for _, name := range sortedKeys(s.Fields) {
+			fmt.Fprintf(&buf, "  add field %s %s\n", name, s.Fields[name])
+		}
+		names := make([]string, 0, len(s.Indexes))
+		for name := range s.Indexes {
+			names = append(names, name)
+		}
+		sort.Strings(names)
+		for _, name := range names {
+			fmt.Fprintf(&buf, "  add index %s\n", name)
+		}
+	}
+	for _, s := range p.Create {
+		write("create", s)
+	}
+	for _, s := range p.Update {
+		write("update", s)
+	}
+	for _, d := range p.Conflicts {
+		fmt.Fprintf(&buf, "conflict: %s\n", d)
+	}
+	return buf.String()
+}
+
+func sortedKeys(fields map[string]Field) []string {
+	keys := make([]string, 0, len(fields))
+	for k := range fields {
+		keys = append(keys, k)
+	}
+	sort.Strings(keys)
+	return keys
+}
+
+// Plan computes the changes needed to make the live schemas match the
+// registered schemas.
+func (r *SchemaRegistry) Plan(c *SchemaClient, indexes bool) (*SchemaPlan, error) {
+	live, err := c.List()
+	if err != nil {
+		return nil, err
+	}
+	byName := make(map[string]*Schema, len(live))
+	for _, s := range live {
+		byName[s.ClassName] = s
+	}
+
+	var plan SchemaPlan
+	for _, want := range r.schemas {
+		got := byName[want.ClassName]
+		if got == nil {
+			s := &Schema{ClassName: want.ClassName, Fields: want.Fields}
+			if indexes {
+				s.Indexes = want.Indexes
+			}
+			plan.Create = append(plan.Create, s)
+			continue
+		}
+
+		update := &Schema{ClassName: want.ClassName}
+		for _, d := range DiffSchema(want, got) {
+			switch d.Kind {
+			case MissingField:
+				if update.Fields == nil {
+					update.Fields = make(map[string]Field)
+				}
+				update.Fields[d.Field] = want.Fields[d.Field]
+			case FieldTypeMismatch:
+				plan.Conflicts = append(plan.Conflicts, d)
+			}
+		}
+		if indexes {
+			for name, x := range want.Indexes {
+				if _, ok := got.Indexes[name]; ok {
+					continue
+				}
+				if update.Indexes == nil {
+					update.Indexes = make(map[string]Index)
+				}
+				update.Indexes[name] = x
+			}
+		}
+		if update.Fields != nil || update.Indexes != nil {
+			plan.Update = append(plan.Update, update)
+		}
+	}
+	return &plan, nil
+}
+
+// Apply creates the missing classes and fields, and optionally indexes, of the
+// registered schemas. The returned SchemaPlan describes the changes, and any
+// conflicts which must be resolved by hand.
+func (r *SchemaRegistry) Apply(c *SchemaClient, opts ApplyOptions) (*SchemaPlan, error) {
+	plan, err := r.Plan(c, opts.Indexes)
+	if err != nil {
+		return nil, err
+	}
+	if opts.DryRun {
+		return plan, nil
+	}
+	for _, s := range plan.Create {
+		if _, err := c.Create(s); err != nil {
+			return plan, err
+		}
+	}
+	for _, s := range plan.Update {
+		if _, err := c.Update(s); err != nil {
+			return plan, err
+		}
+	}
+	return plan, nil
+}
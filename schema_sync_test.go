@@ -0,0 +1,109 @@
+package parse_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+type herd struct {
+	Name     string         `json:"name" parse:"index"`
+	Size     int            `json:"size"`
+	Location parse.GeoPoint `json:"location" parse:"index"`
+}
+
+type farmer struct {
+	Name string `json:"name"`
+}
+
+func schemaTestClient(t testing.TB, requests *[]string) *parse.Client {
+	return &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			if r.Method == "GET" {
+				return jsonResponse(t, http.StatusOK, map[string]interface{}{
+					"results": []interface{}{
+						map[string]interface{}{
+							"className": "Herd",
+							"fields": map[string]interface{}{
+								"name": map[string]string{"type": "Number"},
+							},
+							"indexes": map[string]interface{}{
+								"name_1": map[string]int{"name": 1},
+							},
+						},
+					},
+				}), nil
+			}
+			var m map[string]interface{}
+			decodeBody(t, r, &m)
+			*requests = append(*requests, r.Method+" "+r.URL.Path+" "+string(jsonB(t, m)))
+			return jsonResponse(t, http.StatusOK, m), nil
+		}),
+	}
+}
+
+func TestStructSchemaIndexes(t *testing.T) {
+	t.Parallel()
+	s, err := parse.StructSchema("Herd", herd{})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, s.Indexes, map[string]parse.Index{
+		"name_1":            {parse.Ascending("name")},
+		"location_2dsphere": {parse.Sphere2D("location")},
+	})
+}
+
+func TestSchemaApplyDryRun(t *testing.T) {
+	t.Parallel()
+	var requests []string
+	var r parse.SchemaRegistry
+	ensure.Nil(t, r.Register("Herd", herd{}))
+	ensure.Nil(t, r.Register("Farmer", farmer{}))
+	plan, err := r.Apply(
+		&parse.SchemaClient{Client: schemaTestClient(t, &requests)},
+		parse.ApplyOptions{DryRun: true, Indexes: true},
+	)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(requests), 0)
+	ensure.False(t, plan.Empty())
+	ensure.DeepEqual(t, plan.String(), ""+
+		"create class Farmer\n"+
+		"  add field name String\n"+
+		"update class Herd\n"+
+		"  add field location GeoPoint\n"+
+		"  add field size Number\n"+
+		"  add index location_2dsphere\n"+
+		"conflict: field Herd.name is of type Number instead of String\n")
+}
+
+func TestSchemaApply(t *testing.T) {
+	t.Parallel()
+	var requests []string
+	var r parse.SchemaRegistry
+	ensure.Nil(t, r.Register("Herd", herd{}))
+	ensure.Nil(t, r.Register("Farmer", farmer{}))
+	plan, err := r.Apply(
+		&parse.SchemaClient{Client: schemaTestClient(t, &requests)},
+		parse.ApplyOptions{},
+	)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(plan.Conflicts), 1)
+	ensure.DeepEqual(t, requests, []string{
+		`POST /1/schemas/Farmer {"className":"Farmer","fields":{"name":{"type":"String"}}}`,
+		`PUT /1/schemas/Herd {"className":"Herd","fields":{"location":{"type":"GeoPoint"},"size":{"type":"Number"}}}`,
+	})
+}
+
+func TestSchemaApplyNothing(t *testing.T) {
+	t.Parallel()
+	var requests []string
+	var r parse.SchemaRegistry
+	plan, err := r.Apply(
+		&parse.SchemaClient{Client: schemaTestClient(t, &requests)},
+		parse.ApplyOptions{},
+	)
+	ensure.Nil(t, err)
+	ensure.True(t, plan.Empty())
+	ensure.DeepEqual(t, plan.String(), "")
+}
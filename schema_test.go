@@ -0,0 +1,158 @@
+package parse_test
+
+import (
+	"net/http"
+	"regexp"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestSchemaList(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Method, "GET")
+			ensure.DeepEqual(t, r.URL.Path, "/1/schemas/")
+			return jsonResponse(t, http.StatusOK, map[string]interface{}{
+				"results": []interface{}{
+					map[string]interface{}{
+						"className": "Yak",
+						"fields": map[string]interface{}{
+							"owner": map[string]interface{}{
+								"type":        "Pointer",
+								"targetClass": "_User",
+								"required":    true,
+							},
+						},
+					},
+				},
+			}), nil
+		}),
+	}
+	sc := parse.SchemaClient{Client: c}
+	schemas, err := sc.List()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, schemas, []*parse.Schema{
+		{
+			ClassName: "Yak",
+			Fields: map[string]parse.Field{
+				"owner": {Type: parse.FieldPointer, TargetClass: "_User", Required: true},
+			},
+		},
+	})
+}
+
+func TestSchemaCreate(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Method, "POST")
+			ensure.DeepEqual(t, r.URL.Path, "/1/schemas/Yak")
+			var m map[string]interface{}
+			decodeBody(t, r, &m)
+			ensure.DeepEqual(t, m, map[string]interface{}{
+				"className": "Yak",
+				"fields": map[string]interface{}{
+					"name": map[string]interface{}{"type": "String", "defaultValue": "bob"},
+				},
+			})
+			return jsonResponse(t, http.StatusOK, m), nil
+		}),
+	}
+	sc := parse.SchemaClient{Client: c}
+	s, err := sc.Create(&parse.Schema{
+		ClassName: "Yak",
+		Fields: map[string]parse.Field{
+			"name": {Type: parse.FieldString, DefaultValue: "bob"},
+		},
+	})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, s.ClassName, "Yak")
+}
+
+func TestSchemaUpdateAndDeleteField(t *testing.T) {
+	t.Parallel()
+	var bodies []map[string]interface{}
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Method, "PUT")
+			ensure.DeepEqual(t, r.URL.Path, "/1/schemas/Yak")
+			var m map[string]interface{}
+			decodeBody(t, r, &m)
+			bodies = append(bodies, m)
+			return jsonResponse(t, http.StatusOK, map[string]string{"className": "Yak"}), nil
+		}),
+	}
+	sc := parse.SchemaClient{Client: c}
+	_, err := sc.Update(&parse.Schema{
+		ClassName: "Yak",
+		Fields:    map[string]parse.Field{"age": {Type: parse.FieldNumber}},
+	})
+	ensure.Nil(t, err)
+	_, err = sc.DeleteField("Yak", "name")
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, bodies, []map[string]interface{}{
+		{
+			"className": "Yak",
+			"fields":    map[string]interface{}{"age": map[string]interface{}{"type": "Number"}},
+		},
+		{
+			"className": "Yak",
+			"fields":    map[string]interface{}{"name": map[string]interface{}{"__op": "Delete"}},
+		},
+	})
+}
+
+func TestSchemaGetAndDelete(t *testing.T) {
+	t.Parallel()
+	var methods []string
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			methods = append(methods, r.Method)
+			ensure.DeepEqual(t, r.URL.Path, "/1/schemas/Yak")
+			return jsonResponse(t, http.StatusOK, map[string]string{"className": "Yak"}), nil
+		}),
+	}
+	sc := parse.SchemaClient{Client: c}
+	s, err := sc.Get("Yak")
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, s, &parse.Schema{ClassName: "Yak"})
+	ensure.Nil(t, sc.Delete("Yak"))
+	ensure.DeepEqual(t, methods, []string{"GET", "DELETE"})
+}
+
+func TestSchemaEmptyClassName(t *testing.T) {
+	t.Parallel()
+	sc := parse.SchemaClient{Client: &parse.Client{}}
+	re := regexp.MustCompile("empty class name")
+	_, err := sc.Get("")
+	ensure.Err(t, err, re)
+	_, err = sc.Create(&parse.Schema{})
+	ensure.Err(t, err, re)
+	_, err = sc.Update(&parse.Schema{})
+	ensure.Err(t, err, re)
+	_, err = sc.DeleteField("")
+	ensure.Err(t, err, re)
+	ensure.Err(t, sc.Delete(""), re)
+}
+
+func TestSchemaPurge(t *testing.T) {
+	t.Parallel()
+	calls := 0
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			ensure.DeepEqual(t, r.Method, "DELETE")
+			ensure.DeepEqual(t, r.URL.Path, "/1/purge/Yak")
+			return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+		}),
+	}
+	sc := parse.SchemaClient{Client: c}
+	ensure.Err(t, sc.Purge("Yak", "Yaks"), regexp.MustCompile("confirmation must match"))
+	ensure.Err(t, sc.Purge("", ""), regexp.MustCompile("empty class name"))
+	ensure.DeepEqual(t, calls, 0)
+	ensure.Nil(t, sc.Purge("Yak", "Yak"))
+	ensure.DeepEqual(t, calls, 1)
+}
@@ -0,0 +1,67 @@
+package parse
+
+import (
+	"context"
+	"net/url"
+)
+
+var (
+	healthURL     = &url.URL{Path: "health"}
+	serverInfoURL = &url.URL{Path: "serverInfo"}
+)
+
+// HealthStatus is the health of a Parse Server.
+type HealthStatus struct {
+	// Status is "ok" once the server is ready to serve requests.
+	Status string `json:"status"`
+}
+
+// OK reports if the server is ready.
+func (h *HealthStatus) OK() bool {
+	return h.Status == "ok"
+}
+
+// Features lists what a Parse Server supports, by group and feature, such as
+// Features["schemas"]["addField"].
+type Features map[string]map[string]bool
+
+// Has reports if the feature of the group is supported.
+func (f Features) Has(group, feature string) bool {
+	return f[group][feature]
+}
+
+// ServerInfo describes a Parse Server.
+type ServerInfo struct {
+	Version  string   `json:"parseServerVersion"`
+	Features Features `json:"features"`
+}
+
+// Health checks if the server is up. A server that is still starting responds
+// with an error or a status other than "ok".
+func (c *Client) Health() (*HealthStatus, error) {
+	return c.HealthCtx(context.Background())
+}
+
+// HealthCtx is Health with a context used for the request.
+func (c *Client) HealthCtx(ctx context.Context) (*HealthStatus, error) {
+	var h HealthStatus
+	if _, err := c.GetCtx(ctx, healthURL, &h); err != nil {
+		return nil, err
+	}
+	return &h, nil
+}
+
+// ServerInfo fetches the version and features of the server. It requires the
+// Master Key.
+func (c *Client) ServerInfo() (*ServerInfo, error) {
+	return c.ServerInfoCtx(context.Background())
+}
+
+// ServerInfoCtx is ServerInfo with a context used for the request.
+func (c *Client) ServerInfoCtx(ctx context.Context) (*ServerInfo, error) {
+	var info ServerInfo
+	if _, err := c.GetCtx(ctx, serverInfoURL, &info); err != nil {
+		return nil, err
+	}
+	return &info, nil
+}
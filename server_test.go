@@ -0,0 +1,61 @@
+package parse_test
+
+import (
+	"net/http"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestHealth(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.Method, "GET")
+			ensure.DeepEqual(t, r.URL.Path, "/1/health")
+			return jsonResponse(t, http.StatusOK, map[string]string{"status": "ok"}), nil
+		}),
+	}
+	h, err := c.Health()
+	ensure.Nil(t, err)
+	ensure.True(t, h.OK())
+}
+
+func TestHealthStarting(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			return jsonResponse(t, http.StatusServiceUnavailable, map[string]string{"status": "initialized"}), nil
+		}),
+	}
+	_, err := c.Health()
+	ensure.NotNil(t, err)
+	ensure.DeepEqual(t, err.(*parse.RawError).StatusCode, http.StatusServiceUnavailable)
+	ensure.DeepEqual(t, string(err.(*parse.RawError).Body), `{"status":"initialized"}`)
+}
+
+func TestServerInfo(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Credentials: parse.MasterKey{ApplicationID: defaultApplicationID, MasterKey: "master"},
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			ensure.DeepEqual(t, r.URL.Path, "/1/serverInfo")
+			ensure.DeepEqual(t, r.Header.Get("X-Parse-Master-Key"), "master")
+			return jsonResponse(t, http.StatusOK, map[string]interface{}{
+				"parseServerVersion": "4.2.0",
+				"features": map[string]interface{}{
+					"schemas": map[string]bool{"addField": true, "removeClass": false},
+					"push":    map[string]bool{"scheduledPush": true},
+				},
+			}), nil
+		}),
+	}
+	info, err := c.ServerInfo()
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, info.Version, "4.2.0")
+	ensure.True(t, info.Features.Has("schemas", "addField"))
+	ensure.True(t, info.Features.Has("push", "scheduledPush"))
+	ensure.False(t, info.Features.Has("schemas", "removeClass"))
+	ensure.False(t, info.Features.Has("logs", "level"))
+}
@@ -0,0 +1,79 @@
+package parse
+
+import (
+	"bytes"
+	"io/ioutil"
+	"net/http"
+	"sync"
+)
+
+// Singleflight collapses concurrent identical GET requests into one, so that
+// many goroutines asking for the same object at once cause a single request.
+// Requests are identical when they have the same URL, credentials and Client
+// Partition. The zero value is ready to use and can be shared by Clients.
+//
+// The waiting requests share the result of the first one, including its
+// failure when its context is canceled.
+type Singleflight struct {
+	mu    sync.Mutex
+	calls map[string]*flight
+}
+
+type flight struct {
+	wg   sync.WaitGroup
+	res  *http.Response
+	body []byte
+	err  error
+}
+
+// do calls send unless a call with the same key is in flight, in which case
+// it waits for that call. Every caller gets its own copy of the response.
+func (s *Singleflight) do(key string, send func() (*http.Response, error)) (*http.Response, error) {
+	s.mu.Lock()
+	if s.calls == nil {
+		s.calls = make(map[string]*flight)
+	}
+	f, ok := s.calls[key]
+	if !ok {
+		f = &flight{}
+		f.wg.Add(1)
+		s.calls[key] = f
+	}
+	s.mu.Unlock()
+
+	if !ok {
+		f.res, f.err = send()
+		if f.err == nil {
+			f.body, f.err = ioutil.ReadAll(f.res.Body)
+			f.res.Body.Close()
+		}
+		s.mu.Lock()
+		delete(s.calls, key)
+		s.mu.Unlock()
+		f.wg.Done()
+	} else {
+		f.wg.Wait()
+	}
+	if f.err != nil {
+		return nil, f.err
+	}
+	res := *f.res
+	res.Header = f.res.Header.Clone()
+	res.Body = ioutil.NopCloser(bytes.NewReader(f.body))
+	return &res, nil
+}
+
+// sendShared sends the request through the Singleflight of the Client when it
+// is a GET.
+func (c *Client) sendShared(req *http.Request) (*http.Response, error) {
+	if c.Singleflight == nil || requestMethod(req) != "GET" {
+		return c.send(req)
+	}
+	key := req.URL.String() + " " + credentialsHash(req.Header)
+	if c.Partition != "" {
+		key += " " + c.Partition
+	}
+	return c.Singleflight.do(key, func() (*http.Response, error) {
+		return c.send(req)
+	})
+}
@@ -0,0 +1,88 @@
+package parse_test
+
+import (
+	"net/http"
+	"net/url"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestSingleflight(t *testing.T) {
+	t.Parallel()
+	const n = 10
+	var requests int32
+	entered := make(chan struct{}, n)
+	release := make(chan struct{})
+	c := &parse.Client{
+		Singleflight: &parse.Singleflight{},
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&requests, 1)
+			entered <- struct{}{}
+			<-release
+			return jsonResponse(t, http.StatusOK, map[string]string{"name": "yak"}), nil
+		}),
+	}
+	var started, done sync.WaitGroup
+	for i := 0; i < n; i++ {
+		started.Add(1)
+		done.Add(1)
+		go func() {
+			defer done.Done()
+			started.Done()
+			var res struct{ Name string }
+			_, err := c.Get(&url.URL{Path: "classes/Yak/abc"}, &res)
+			ensure.Nil(t, err)
+			ensure.DeepEqual(t, res.Name, "yak")
+		}()
+	}
+	started.Wait()
+	<-entered
+	// Give the other requests time to join the one in flight.
+	time.Sleep(10 * time.Millisecond)
+	close(release)
+	done.Wait()
+	ensure.True(t, atomic.LoadInt32(&requests) < n)
+
+	_, err := c.Get(&url.URL{Path: "classes/Yak/abc"}, nil)
+	ensure.Nil(t, err)
+}
+
+func TestSingleflightError(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Singleflight: &parse.Singleflight{},
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			return jsonResponse(t, http.StatusNotFound, map[string]interface{}{"code": 101, "error": "not found"}), nil
+		}),
+	}
+	_, err := c.Get(&url.URL{Path: "classes/Yak/abc"}, nil)
+	ensure.True(t, parse.IsObjectNotFound(err))
+}
+
+func TestSingleflightWrites(t *testing.T) {
+	t.Parallel()
+	var requests int32
+	c := &parse.Client{
+		Singleflight: &parse.Singleflight{},
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			atomic.AddInt32(&requests, 1)
+			return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+		}),
+	}
+	var wg sync.WaitGroup
+	for i := 0; i < 5; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, err := c.Put(&url.URL{Path: "classes/Yak/abc"}, map[string]string{}, nil)
+			ensure.Nil(t, err)
+		}()
+	}
+	wg.Wait()
+	ensure.DeepEqual(t, atomic.LoadInt32(&requests), int32(5))
+}
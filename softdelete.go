@@ -0,0 +1,88 @@
+package parse
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+)
+
+const defaultDeletedField = "deletedAt"
+
+// deletedField returns the field that marks soft deleted objects.
+func (o *ObjectClient) deletedField() string {
+	if o.DeletedField != "" {
+		return o.DeletedField
+	}
+	return defaultDeletedField
+}
+
+// notDeleted returns the where clause restricted to objects that aren't soft
+// deleted. A Where or map that already constrains the field is left as it
+// is, so that the deleted objects can still be queried, and other where
+// clauses are combined using $and.
+func (o *ObjectClient) notDeleted(where interface{}) interface{} {
+	if !o.SoftDelete {
+		return where
+	}
+	field := o.deletedField()
+	var m map[string]interface{}
+	switch w := where.(type) {
+	case nil:
+	case Where:
+		m = w
+	case map[string]interface{}:
+		m = w
+	default:
+		return map[string]interface{}{"$and": []interface{}{
+			where,
+			Where{}.DoesNotExist(field),
+		}}
+	}
+	if _, ok := m[field]; ok {
+		return where
+	}
+	w := make(Where, len(m)+1)
+	for k, v := range m {
+		w[k] = v
+	}
+	return w.DoesNotExist(field)
+}
+
+// softDelete marks the object with the given ID deleted.
+func (o *ObjectClient) softDelete(ctx context.Context, id string) (*http.Response, error) {
+	defer o.invalidate()
+	return o.Client.PutCtx(ctx, o.objectURL(id), o.deletedBody(), nil)
+}
+
+// deletedBody is the update that marks an object deleted now.
+func (o *ObjectClient) deletedBody() map[string]Date {
+	return map[string]Date{o.deletedField(): Date(o.Client.clock().Now())}
+}
+
+// Restore clears the deleted field of the object with the given ID, undoing
+// a soft delete.
+func (o *ObjectClient) Restore(id string) (*UpdateResponse, error) {
+	return o.RestoreCtx(context.Background(), id)
+}
+
+// RestoreCtx is Restore with a context used for the request.
+func (o *ObjectClient) RestoreCtx(ctx context.Context, id string) (*UpdateResponse, error) {
+	var res UpdateResponse
+	defer o.invalidate()
+	body := map[string]json.RawMessage{o.deletedField(): json.RawMessage(`{"__op":"Delete"}`)}
+	if _, err := o.Client.PutCtx(ctx, o.objectURL(id), body, &res); err != nil {
+		return nil, err
+	}
+	return &res, nil
+}
+
+// HardDelete deletes the object with the given ID, even with SoftDelete.
+func (o *ObjectClient) HardDelete(id string) (*http.Response, error) {
+	return o.HardDeleteCtx(context.Background(), id)
+}
+
+// HardDeleteCtx is HardDelete with a context used for the request.
+func (o *ObjectClient) HardDeleteCtx(ctx context.Context, id string) (*http.Response, error) {
+	defer o.invalidate()
+	return o.Client.DeleteCtx(ctx, o.objectURL(id), nil)
+}
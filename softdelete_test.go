@@ -0,0 +1,65 @@
+package parse_test
+
+import (
+	"encoding/json"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+	"github.com/facebookgo/parse/parsetest"
+)
+
+func TestSoftDelete(t *testing.T) {
+	t.Parallel()
+	s := parsetest.NewServer()
+	defer s.Close()
+	c := s.Client()
+	c.Clock = parsetest.NewClock(time.Date(2015, 6, 16, 1, 2, 3, 0, time.UTC))
+	o := parse.ObjectClient{Client: c, Path: "classes/Yak", SoftDelete: true}
+	res, err := o.PostAll([]map[string]int{{"n": 1}, {"n": 2}, {"n": 3}})
+	ensure.Nil(t, err)
+	ids := []string{res[0].ID, res[1].ID, res[2].ID}
+
+	_, err = o.Delete(ids[0])
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, s.Objects("Yak")[ids[0]]["deletedAt"], map[string]interface{}{
+		"__type": "Date",
+		"iso":    "2015-06-16T01:02:03.000Z",
+	})
+
+	count, err := o.Count(nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, count, 2)
+	var page struct {
+		Results []struct {
+			N int `json:"n"`
+		} `json:"results"`
+	}
+	_, err = o.Query(&parse.Params{Where: json.RawMessage(`{"n":{"$lt":3}}`)}, &page)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(page.Results), 1)
+	ensure.DeepEqual(t, page.Results[0].N, 2)
+
+	// Constraining the field finds the deleted objects.
+	count, err = o.Count(parse.Where{}.Exists("deletedAt"))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, count, 1)
+
+	_, err = o.DeleteAll(ids[1:])
+	ensure.Nil(t, err)
+	count, err = o.Count(nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, count, 0)
+	ensure.DeepEqual(t, len(s.Objects("Yak")), 3)
+
+	_, err = o.Restore(ids[1])
+	ensure.Nil(t, err)
+	count, err = o.Count(nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, count, 1)
+
+	_, err = o.HardDelete(ids[0])
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(s.Objects("Yak")), 2)
+}
@@ -0,0 +1,64 @@
+package parse
+
+import (
+	"context"
+	"net/http"
+	"strings"
+	"time"
+)
+
+// RequestStats describes how a request went, for callers adapting their pace
+// to the API or needing the response headers of calls that don't return the
+// http.Response, such as those of ObjectClient.
+type RequestStats struct {
+	// StatusCode, Header and ContentLength of the last response.
+	StatusCode    int
+	Header        http.Header
+	ContentLength int64
+
+	// Duration is the time from sending the request until its response body
+	// was closed, including retries.
+	Duration time.Duration
+
+	// Attempts is the number of times the request was sent, which is more
+	// than 1 when it was retried.
+	Attempts int
+
+	// ResponseSize is the number of bytes read from the response body.
+	ResponseSize int64
+
+	// RateLimit has the rate limit headers of the last response, such as
+	// X-RateLimit-Remaining and Retry-After.
+	RateLimit http.Header
+}
+
+type statsKey struct{}
+
+// WithStats returns a context that fills s with the stats of the requests made
+// with it. Each request made with the context replaces the stats of the
+// previous one, so the context must be used for one call at a time:
+//
+//	var stats parse.RequestStats
+//	_, err := o.GetCtx(parse.WithStats(ctx, &stats), id, &yak)
+func WithStats(ctx context.Context, s *RequestStats) context.Context {
+	return context.WithValue(ctx, statsKey{}, s)
+}
+
+func statsFrom(ctx context.Context) *RequestStats {
+	s, _ := ctx.Value(statsKey{}).(*RequestStats)
+	return s
+}
+
+func rateLimitHeader(h http.Header) http.Header {
+	var r http.Header
+	for k, v := range h {
+		l := strings.ToLower(k)
+		if strings.HasPrefix(l, "x-ratelimit-") || strings.HasPrefix(l, "ratelimit-") || l == "retry-after" {
+			if r == nil {
+				r = make(http.Header)
+			}
+			r[k] = v
+		}
+	}
+	return r
+}
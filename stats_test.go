@@ -0,0 +1,77 @@
+package parse_test
+
+import (
+	"context"
+	"net/http"
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestStats(t *testing.T) {
+	t.Parallel()
+	calls := 0
+	c := &parse.Client{
+		Retry: &parse.RetryPolicy{MaxAttempts: 3, BaseDelay: time.Millisecond},
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			calls++
+			if calls == 1 {
+				return jsonResponse(t, http.StatusServiceUnavailable, map[string]string{}), nil
+			}
+			res := jsonResponse(t, http.StatusOK, map[string]int{"answer": 42})
+			res.Header.Set("X-RateLimit-Remaining", "9")
+			res.Header.Set("X-Other", "no")
+			return res, nil
+		}),
+	}
+	var stats parse.RequestStats
+	ctx := parse.WithStats(context.Background(), &stats)
+	var res map[string]int
+	_, err := c.GetCtx(ctx, &url.URL{Path: "classes/Yak/a"}, &res)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res, map[string]int{"answer": 42})
+	ensure.DeepEqual(t, stats.Attempts, 2)
+	ensure.DeepEqual(t, stats.ResponseSize, int64(len(`{"answer":42}`)))
+	ensure.DeepEqual(t, stats.RateLimit, http.Header{"X-Ratelimit-Remaining": {"9"}})
+	ensure.True(t, stats.Duration >= time.Millisecond)
+}
+
+func TestStatsError(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			res := jsonResponse(t, http.StatusTooManyRequests, map[string]interface{}{"code": 155, "error": "slow down"})
+			res.Header.Set("Retry-After", "3")
+			return res, nil
+		}),
+	}
+	stats := parse.RequestStats{Attempts: 7}
+	_, err := c.GetCtx(parse.WithStats(context.Background(), &stats), &url.URL{Path: "classes/Yak/a"}, nil)
+	ensure.NotNil(t, err)
+	ensure.DeepEqual(t, stats.Attempts, 1)
+	ensure.DeepEqual(t, stats.RateLimit, http.Header{"Retry-After": {"3"}})
+	ensure.True(t, stats.ResponseSize > 0)
+}
+
+func TestStatsResponse(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			res := jsonResponse(t, http.StatusCreated, map[string]string{"objectId": "y1"})
+			res.Header.Set("X-Parse-Job-Status-Id", "job1")
+			res.ContentLength = 17
+			return res, nil
+		}),
+	}
+	var stats parse.RequestStats
+	o := parse.ObjectClient{Client: c, Path: "classes/Yak"}
+	created, err := o.PostCtx(parse.WithStats(context.Background(), &stats), map[string]string{})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, created.ID, "y1")
+	ensure.DeepEqual(t, stats.StatusCode, http.StatusCreated)
+	ensure.DeepEqual(t, stats.Header.Get("X-Parse-Job-Status-Id"), "job1")
+	ensure.DeepEqual(t, stats.ContentLength, int64(17))
+}
@@ -0,0 +1,270 @@
+package parse
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"net/url"
+	"strings"
+)
+
+const defaultTenantField = "owner"
+
+var (
+	errTenantOwner     = errors.New("parse: tenant needs an Owner")
+	errTenantAggregate = errors.New("parse: aggregate queries cannot be restricted to a tenant")
+)
+
+// Tenant isolates the data of one owner, such as a user or an organization,
+// in classes shared by all owners. Its Middleware stamps every object created
+// in a class with a Pointer to the Owner and an ACL granting read and write
+// access to the Owner, when it is a _User, and to the Roles only. It also adds
+// the Owner to the where clause of every class query, so that the objects of
+// other owners aren't returned even to a Client with the Master Key. Add it
+// to the Client of the owner with WithTenant, which also sets the Partition
+// so that the Clients of different owners can share a Singleflight or a
+// Cache:
+//
+//	c, err := parse.NewClient(cr, parse.WithTenant(&parse.Tenant{Owner: user}))
+//
+// Objects fetched, updated or deleted by objectId are protected by the ACL
+// only, which the Master Key bypasses. Aggregate queries of isolated classes
+// are rejected, since they need the Master Key and their pipelines cannot be
+// restricted reliably.
+type Tenant struct {
+	// Owner of the objects, usually a Pointer to a _User.
+	Owner Pointer
+
+	// Field is the Pointer field referring to the owner. Defaults to
+	// "owner".
+	Field string
+
+	// Roles are the names of the roles that get read and write access to the
+	// objects, in addition to the Owner.
+	Roles []string
+
+	// Classes optionally restricts the isolation to some classes. When empty
+	// it applies to all classes.
+	Classes []string
+}
+
+func (t *Tenant) field() string {
+	if t.Field != "" {
+		return t.Field
+	}
+	return defaultTenantField
+}
+
+// WithTenant adds the Middleware of the Tenant and sets the Partition of the
+// Client to the Owner.
+func WithTenant(t *Tenant) Option {
+	return func(c *Client) error {
+		if t.Owner.ID == "" {
+			return errTenantOwner
+		}
+		c.Middleware = append(c.Middleware, t.Middleware)
+		c.Partition = "tenant:" + t.field() + "=" + t.Owner.ClassName + "/" + t.Owner.ID
+		return nil
+	}
+}
+
+// ACL returns the ACL stamped on created objects.
+func (t *Tenant) ACL() map[string]map[string]bool {
+	acl := make(map[string]map[string]bool, len(t.Roles)+1)
+	if t.Owner.ClassName == "_User" {
+		acl[t.Owner.ID] = map[string]bool{"read": true, "write": true}
+	}
+	for _, r := range t.Roles {
+		acl["role:"+r] = map[string]bool{"read": true, "write": true}
+	}
+	return acl
+}
+
+// isolated reports whether the path is that of an isolated class, such as
+// "/1/classes/Yak", rather than of one of its objects.
+func (t *Tenant) isolated(path string) bool {
+	return t.isolatedPath("classes", path)
+}
+
+// isolatedPath is isolated for the endpoint, such as "classes" or
+// "aggregate".
+func (t *Tenant) isolatedPath(endpoint, path string) bool {
+	parts := strings.Split(strings.Trim(path, "/"), "/")
+	for i := len(parts) - 2; i >= 0 && i >= len(parts)-3; i-- {
+		if parts[i] != endpoint {
+			continue
+		}
+		if len(t.Classes) != 0 && !containsString(t.Classes, parts[i+1]) {
+			return false
+		}
+		return i == len(parts)-2
+	}
+	return false
+}
+
+// Middleware rewrites the queries and creates of the requests. Batched
+// creates are stamped too.
+func (t *Tenant) Middleware(next http.RoundTripper) http.RoundTripper {
+	return RoundTripperFunc(func(r *http.Request) (*http.Response, error) {
+		if t.Owner.ID == "" {
+			return nil, errTenantOwner
+		}
+		if t.isolatedPath("aggregate", r.URL.Path) {
+			return nil, errTenantAggregate
+		}
+		method := requestMethod(r)
+		class := t.isolated(r.URL.Path)
+		if class && method == "GET" {
+			v, err := url.ParseQuery(r.URL.RawQuery)
+			if err != nil {
+				return nil, err
+			}
+			where, err := t.where(v.Get("where"))
+			if err != nil {
+				return nil, err
+			}
+			v.Set("where", where)
+			r = r.Clone(r.Context())
+			u := *r.URL
+			u.RawQuery = v.Encode()
+			r.URL = &u
+			return next.RoundTrip(r)
+		}
+		batch := strings.HasSuffix(strings.TrimSuffix(r.URL.Path, "/"), "/batch")
+		if method != "POST" || (!class && !batch) || r.Body == nil {
+			return next.RoundTrip(r)
+		}
+
+		r = r.Clone(r.Context())
+		body, err := readRequestBody(r)
+		if err != nil {
+			return nil, err
+		}
+		if batch {
+			body, err = t.stampBatch(body)
+		} else {
+			body, err = t.stamp(body)
+		}
+		if err != nil {
+			return nil, err
+		}
+		r.Body = ioutil.NopCloser(bytes.NewReader(body))
+		r.GetBody = func() (io.ReadCloser, error) {
+			return ioutil.NopCloser(bytes.NewReader(body)), nil
+		}
+		r.ContentLength = int64(len(body))
+		return next.RoundTrip(r)
+	})
+}
+
+// where returns the where clause restricted to the Owner. A clause that
+// already constrains the field is combined using $and.
+func (t *Tenant) where(raw string) (string, error) {
+	owner := map[string]interface{}{t.field(): t.Owner}
+	where := owner
+	if raw != "" {
+		var w map[string]json.RawMessage
+		if err := json.Unmarshal([]byte(raw), &w); err != nil {
+			return "", err
+		}
+		if _, ok := w[t.field()]; ok {
+			where = map[string]interface{}{"$and": []interface{}{json.RawMessage(raw), owner}}
+		} else {
+			where = make(map[string]interface{}, len(w)+1)
+			for k, v := range w {
+				where[k] = v
+			}
+			where[t.field()] = t.Owner
+		}
+	}
+	b, err := json.Marshal(where)
+	return string(b), err
+}
+
+// stamp sets the owner and the ACL of a create, or restricts the where clause
+// of a query sent as a POST with a _method of GET.
+func (t *Tenant) stamp(body []byte) ([]byte, error) {
+	var o map[string]json.RawMessage
+	if err := json.Unmarshal(body, &o); err != nil {
+		return nil, err
+	}
+	if m, ok := o["_method"]; ok && string(m) == `"GET"` {
+		var raw string
+		if w, ok := o["where"]; ok {
+			if err := json.Unmarshal(w, &raw); err != nil {
+				return nil, err
+			}
+		}
+		where, err := t.where(raw)
+		if err != nil {
+			return nil, err
+		}
+		if o["where"], err = json.Marshal(where); err != nil {
+			return nil, err
+		}
+		return json.Marshal(o)
+	}
+	var err error
+	if o[t.field()], err = json.Marshal(t.Owner); err != nil {
+		return nil, err
+	}
+	if o["ACL"], err = json.Marshal(t.ACL()); err != nil {
+		return nil, err
+	}
+	return json.Marshal(o)
+}
+
+// stampBatch stamps the creates in the classes of a batch.
+func (t *Tenant) stampBatch(body []byte) ([]byte, error) {
+	var b struct {
+		Requests []struct {
+			Method string          `json:"method"`
+			Path   string          `json:"path"`
+			Body   json.RawMessage `json:"body,omitempty"`
+		} `json:"requests"`
+	}
+	if err := json.Unmarshal(body, &b); err != nil {
+		return nil, err
+	}
+	for i, r := range b.Requests {
+		if !t.isolated(r.Path) || r.Method != "POST" || len(r.Body) == 0 {
+			continue
+		}
+		stamped, err := t.stamp(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		b.Requests[i].Body = stamped
+	}
+	return json.Marshal(b)
+}
+
+// readRequestBody reads the body of the request, which must be owned by the
+// caller, decompressing it if the Client compressed it, in which case the
+// header is removed.
+func readRequestBody(r *http.Request) ([]byte, error) {
+	defer r.Body.Close()
+	var body io.Reader = r.Body
+	if strings.EqualFold(r.Header.Get("Content-Encoding"), "gzip") {
+		zr, err := gzip.NewReader(r.Body)
+		if err != nil {
+			return nil, err
+		}
+		body = zr
+		r.Header.Del("Content-Encoding")
+	}
+	return ioutil.ReadAll(body)
+}
+
+func containsString(a []string, s string) bool {
+	for _, v := range a {
+		if v == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,162 @@
+package parse_test
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/url"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+	"github.com/facebookgo/parse/parsetest"
+)
+
+func tenantClient(s *parsetest.Server, id string) *parse.Client {
+	c := s.Client()
+	t := &parse.Tenant{
+		Owner:   parse.Pointer{ClassName: "_User", ID: id},
+		Roles:   []string{"admins"},
+		Classes: []string{"Yak"},
+	}
+	if err := parse.WithTenant(t)(c); err != nil {
+		panic(err)
+	}
+	return c
+}
+
+func TestTenant(t *testing.T) {
+	t.Parallel()
+	s := parsetest.NewServer()
+	defer s.Close()
+	al := tenantClient(s, "al")
+	bo := tenantClient(s, "bo")
+	bo.GzipRequests = 1
+
+	res, err := al.Class("Yak").Post(map[string]int{"n": 1})
+	ensure.Nil(t, err)
+	_, err = bo.Class("Yak").PostAll([]map[string]int{{"n": 2}, {"n": 3}})
+	ensure.Nil(t, err)
+	_, err = al.Class("Herd").Post(map[string]int{"n": 4})
+	ensure.Nil(t, err)
+
+	yak := s.Objects("Yak")[res.ID]
+	ensure.DeepEqual(t, yak["owner"], map[string]interface{}{
+		"__type":    "Pointer",
+		"className": "_User",
+		"objectId":  "al",
+	})
+	ensure.DeepEqual(t, yak["ACL"], map[string]interface{}{
+		"al":          map[string]interface{}{"read": true, "write": true},
+		"role:admins": map[string]interface{}{"read": true, "write": true},
+	})
+	for _, herd := range s.Objects("Herd") {
+		_, ok := herd["owner"]
+		ensure.False(t, ok)
+	}
+
+	count, err := al.Class("Yak").Count(nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, count, 1)
+	count, err = bo.Class("Yak").Count(parse.Where{}.GreaterThan("n", 2))
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, count, 1)
+
+	// A where clause naming another owner still only matches the own objects.
+	other := parse.Where{}.EqualTo("owner", parse.Pointer{ClassName: "_User", ID: "bo"})
+	count, err = al.Class("Yak").Count(other)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, count, 0)
+
+	// Queries sent as a POST are restricted too.
+	bo.MaxURLLength = 10
+	var page struct {
+		Results []json.RawMessage `json:"results"`
+	}
+	_, err = bo.Class("Yak").Query(nil, &page)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, len(page.Results), 2)
+
+	_, err = al.Get(&url.URL{Path: "aggregate/Yak"}, &page)
+	ensure.Err(t, err, regexp.MustCompile("aggregate queries cannot be restricted"))
+	// Other classes reach the server, which doesn't implement aggregate.
+	_, err = al.Get(&url.URL{Path: "aggregate/Herd"}, &page)
+	ensure.Err(t, err, regexp.MustCompile("unsupported request GET aggregate/Herd"))
+}
+
+func TestTenantNoOwner(t *testing.T) {
+	t.Parallel()
+	s := parsetest.NewServer()
+	defer s.Close()
+	c := s.Client()
+	c.Middleware = append(c.Middleware, (&parse.Tenant{}).Middleware)
+	_, err := c.Class("Yak").Count(nil)
+	ensure.Err(t, err, regexp.MustCompile("tenant needs an Owner"))
+}
+
+func TestTenantSharedCache(t *testing.T) {
+	t.Parallel()
+	s := parsetest.NewServer()
+	defer s.Close()
+	al := tenantClient(s, "al")
+	bo := tenantClient(s, "bo")
+	_, err := al.Class("Yak").Post(map[string]int{"n": 1})
+	ensure.Nil(t, err)
+	_, err = bo.Class("Yak").PostAll([]map[string]int{{"n": 2}, {"n": 3}})
+	ensure.Nil(t, err)
+
+	cache := &parse.MemoryCache{}
+	count, err := (&parse.ObjectClient{Client: al, Path: "classes/Yak", Cache: cache}).Count(nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, count, 1)
+	count, err = (&parse.ObjectClient{Client: bo, Path: "classes/Yak", Cache: cache}).Count(nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, count, 2)
+}
+
+func TestTenantSharedSingleflight(t *testing.T) {
+	t.Parallel()
+	entered := make(chan string, 2)
+	release := make(chan struct{})
+	sf := &parse.Singleflight{}
+	client := func(id string) *parse.Client {
+		c := &parse.Client{
+			Credentials:  parse.MasterKey{ApplicationID: "app", MasterKey: "m"},
+			Singleflight: sf,
+			Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+				entered <- r.URL.Query().Get("where")
+				<-release
+				return jsonResponse(t, http.StatusOK, map[string]interface{}{"results": []string{}}), nil
+			}),
+		}
+		ensure.Nil(t, parse.WithTenant(&parse.Tenant{Owner: parse.Pointer{ClassName: "_User", ID: id}})(c))
+		return c
+	}
+	done := make(chan error, 2)
+	for _, c := range []*parse.Client{client("al"), client("bo")} {
+		go func(c *parse.Client) {
+			_, err := c.Class("Yak").Query(nil, nil)
+			done <- err
+		}(c)
+	}
+	wheres := make(map[string]bool)
+	for i := 0; i < 2; i++ {
+		select {
+		case w := <-entered:
+			wheres[w] = true
+		case <-time.After(time.Second):
+			t.Fatal("the queries of two tenants were collapsed into one")
+		}
+	}
+	close(release)
+	ensure.Nil(t, <-done)
+	ensure.Nil(t, <-done)
+	ensure.DeepEqual(t, len(wheres), 2)
+}
+
+func TestWithTenantNoOwner(t *testing.T) {
+	t.Parallel()
+	c := &parse.Client{}
+	ensure.Err(t, parse.WithTenant(&parse.Tenant{})(c), regexp.MustCompile("tenant needs an Owner"))
+}
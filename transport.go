@@ -0,0 +1,146 @@
+package parse
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"io/ioutil"
+	"net"
+	"net/http"
+	"net/url"
+	"time"
+)
+
+const (
+	defaultDialTimeout         = 10 * time.Second
+	defaultTLSHandshakeTimeout = 10 * time.Second
+	defaultIdleConnTimeout     = 90 * time.Second
+	defaultMaxIdleConnsPerHost = 32
+)
+
+// TransportOptions configure the connections made by NewTransport. The zero
+// value has defaults suited to the API, where most requests go to one host.
+type TransportOptions struct {
+	// Timeout is the time limit of requests made by the http.Client from
+	// NewHTTPClient. Use Client.Timeout with NewTransport. Zero means no
+	// limit.
+	Timeout time.Duration
+
+	// DialTimeout limits establishing a connection. Defaults to 10 seconds.
+	DialTimeout time.Duration
+
+	// TLSHandshakeTimeout limits the TLS handshake. Defaults to 10 seconds.
+	TLSHandshakeTimeout time.Duration
+
+	// ResponseHeaderTimeout optionally limits the wait for the response
+	// headers once the request is sent.
+	ResponseHeaderTimeout time.Duration
+
+	// MaxIdleConnsPerHost is the number of connections kept open for reuse.
+	// The http.DefaultTransport keeps 2, which causes connection churn with
+	// concurrent requests. Defaults to 32.
+	MaxIdleConnsPerHost int
+
+	// IdleConnTimeout closes connections that stay unused for longer.
+	// Defaults to 90 seconds.
+	IdleConnTimeout time.Duration
+
+	// DisableKeepAlives opens a new connection for every request.
+	DisableKeepAlives bool
+
+	// Proxy optionally sends requests through the proxy, such as
+	// http://gateway.internal:3128. By default the HTTPS_PROXY, HTTP_PROXY and
+	// NO_PROXY environment variables are used.
+	Proxy *url.URL
+
+	// RootCAs optionally replaces the system certificate authorities, for
+	// servers with a certificate from a private CA. See LoadCAFile.
+	RootCAs *x509.CertPool
+
+	// Certificates are client certificates presented to the server, as
+	// loaded by tls.LoadX509KeyPair.
+	Certificates []tls.Certificate
+
+	// TLSClientConfig optionally configures TLS. RootCAs and Certificates
+	// are added to a copy of it.
+	TLSClientConfig *tls.Config
+}
+
+// LoadCAFile returns the certificate authorities in the PEM encoded file, to
+// be used as the RootCAs.
+func LoadCAFile(name string) (*x509.CertPool, error) {
+	b, err := ioutil.ReadFile(name)
+	if err != nil {
+		return nil, err
+	}
+	pool := x509.NewCertPool()
+	if !pool.AppendCertsFromPEM(b) {
+		return nil, fmt.Errorf("parse: no certificates found in %s", name)
+	}
+	return pool, nil
+}
+
+// NewTransport returns an http.Transport configured by the options, to be used
+// as the Client Transport.
+func NewTransport(o *TransportOptions) *http.Transport {
+	if o == nil {
+		o = &TransportOptions{}
+	}
+	dialTimeout := o.DialTimeout
+	if dialTimeout == 0 {
+		dialTimeout = defaultDialTimeout
+	}
+	t := &http.Transport{
+		Proxy: http.ProxyFromEnvironment,
+		DialContext: (&net.Dialer{
+			Timeout:   dialTimeout,
+			KeepAlive: 30 * time.Second,
+		}).DialContext,
+		ForceAttemptHTTP2:     true,
+		TLSHandshakeTimeout:   o.TLSHandshakeTimeout,
+		ResponseHeaderTimeout: o.ResponseHeaderTimeout,
+		ExpectContinueTimeout: time.Second,
+		MaxIdleConns:          100,
+		MaxIdleConnsPerHost:   o.MaxIdleConnsPerHost,
+		IdleConnTimeout:       o.IdleConnTimeout,
+		DisableKeepAlives:     o.DisableKeepAlives,
+		TLSClientConfig:       o.TLSClientConfig,
+	}
+	if o.Proxy != nil {
+		t.Proxy = http.ProxyURL(o.Proxy)
+	}
+	if o.RootCAs != nil || len(o.Certificates) != 0 {
+		if t.TLSClientConfig == nil {
+			t.TLSClientConfig = &tls.Config{}
+		} else {
+			t.TLSClientConfig = t.TLSClientConfig.Clone()
+		}
+		if o.RootCAs != nil {
+			t.TLSClientConfig.RootCAs = o.RootCAs
+		}
+		t.TLSClientConfig.Certificates = append(t.TLSClientConfig.Certificates, o.Certificates...)
+	}
+	if t.TLSHandshakeTimeout == 0 {
+		t.TLSHandshakeTimeout = defaultTLSHandshakeTimeout
+	}
+	if t.MaxIdleConnsPerHost == 0 {
+		t.MaxIdleConnsPerHost = defaultMaxIdleConnsPerHost
+	}
+	if t.MaxIdleConns < t.MaxIdleConnsPerHost {
+		t.MaxIdleConns = t.MaxIdleConnsPerHost
+	}
+	if t.IdleConnTimeout == 0 {
+		t.IdleConnTimeout = defaultIdleConnTimeout
+	}
+	return t
+}
+
+// NewHTTPClient returns an http.Client using NewTransport, for code that
+// needs an http.Client rather than a Transport, such as when downloading
+// files.
+func NewHTTPClient(o *TransportOptions) *http.Client {
+	if o == nil {
+		o = &TransportOptions{}
+	}
+	return &http.Client{Transport: NewTransport(o), Timeout: o.Timeout}
+}
@@ -0,0 +1,116 @@
+package parse_test
+
+import (
+	"crypto/tls"
+	"encoding/pem"
+	"io/ioutil"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"os"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestNewTransportDefaults(t *testing.T) {
+	t.Parallel()
+	tr := parse.NewTransport(nil)
+	ensure.DeepEqual(t, tr.MaxIdleConnsPerHost, 32)
+	ensure.DeepEqual(t, tr.IdleConnTimeout, 90*time.Second)
+	ensure.DeepEqual(t, tr.TLSHandshakeTimeout, 10*time.Second)
+	ensure.False(t, tr.DisableKeepAlives)
+	ensure.NotNil(t, tr.Proxy)
+}
+
+func TestNewTransportOptions(t *testing.T) {
+	t.Parallel()
+	config := &tls.Config{ServerName: "parse.example.com"}
+	tr := parse.NewTransport(&parse.TransportOptions{
+		MaxIdleConnsPerHost:   200,
+		IdleConnTimeout:       time.Minute,
+		ResponseHeaderTimeout: time.Second,
+		DisableKeepAlives:     true,
+		TLSClientConfig:       config,
+	})
+	ensure.DeepEqual(t, tr.MaxIdleConnsPerHost, 200)
+	ensure.DeepEqual(t, tr.MaxIdleConns, 200)
+	ensure.DeepEqual(t, tr.IdleConnTimeout, time.Minute)
+	ensure.DeepEqual(t, tr.ResponseHeaderTimeout, time.Second)
+	ensure.True(t, tr.DisableKeepAlives)
+	ensure.True(t, tr.TLSClientConfig == config)
+}
+
+func TestNewHTTPClient(t *testing.T) {
+	t.Parallel()
+	hc := parse.NewHTTPClient(&parse.TransportOptions{Timeout: time.Minute})
+	ensure.DeepEqual(t, hc.Timeout, time.Minute)
+	_, ok := hc.Transport.(*http.Transport)
+	ensure.True(t, ok)
+}
+
+func TestNewTransportProxy(t *testing.T) {
+	t.Parallel()
+	proxy := &url.URL{Scheme: "http", Host: "gateway.internal:3128"}
+	tr := parse.NewTransport(&parse.TransportOptions{Proxy: proxy})
+	u, err := tr.Proxy(&http.Request{URL: &url.URL{Scheme: "https", Host: "parse.example.com"}})
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, u, proxy)
+}
+
+func TestNewTransportRootCAs(t *testing.T) {
+	t.Parallel()
+	s := httptest.NewTLSServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"name":"yak"}`))
+	}))
+	defer s.Close()
+
+	f, err := ioutil.TempFile("", "parse-ca")
+	ensure.Nil(t, err)
+	defer os.Remove(f.Name())
+	ensure.Nil(t, pem.Encode(f, &pem.Block{Type: "CERTIFICATE", Bytes: s.Certificate().Raw}))
+	ensure.Nil(t, f.Close())
+	pool, err := parse.LoadCAFile(f.Name())
+	ensure.Nil(t, err)
+
+	base, err := url.Parse(s.URL + "/parse/")
+	ensure.Nil(t, err)
+	c := &parse.Client{
+		BaseURL:   base,
+		Transport: parse.NewTransport(&parse.TransportOptions{RootCAs: pool}),
+	}
+	var res struct{ Name string }
+	_, err = c.Get(nil, &res)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, res.Name, "yak")
+
+	c.Transport = parse.NewTransport(nil)
+	_, err = c.Get(nil, &res)
+	ensure.Err(t, err, regexp.MustCompile("certificate"))
+}
+
+func TestNewTransportCertificates(t *testing.T) {
+	t.Parallel()
+	config := &tls.Config{ServerName: "parse.example.com"}
+	cert := tls.Certificate{Certificate: [][]byte{{1}}}
+	tr := parse.NewTransport(&parse.TransportOptions{
+		Certificates:    []tls.Certificate{cert},
+		TLSClientConfig: config,
+	})
+	ensure.DeepEqual(t, tr.TLSClientConfig.ServerName, "parse.example.com")
+	ensure.DeepEqual(t, len(tr.TLSClientConfig.Certificates), 1)
+	ensure.DeepEqual(t, len(config.Certificates), 0)
+}
+
+func TestLoadCAFileInvalid(t *testing.T) {
+	t.Parallel()
+	f, err := ioutil.TempFile("", "parse-ca")
+	ensure.Nil(t, err)
+	defer os.Remove(f.Name())
+	ensure.Nil(t, f.Close())
+	_, err = parse.LoadCAFile(f.Name())
+	ensure.Err(t, err, regexp.MustCompile("no certificates found"))
+}
@@ -0,0 +1,172 @@
+package parse
+
+import (
+	"encoding/json"
+	"fmt"
+	"time"
+)
+
+// Pointer is a reference to another object.
+type Pointer struct {
+	ClassName string
+	ID        string
+}
+
+type pointerJSON struct {
+	Type      string `json:"__type"`
+	ClassName string `json:"className"`
+	ID        string `json:"objectId"`
+}
+
+// MarshalJSON encodes the Pointer.
+func (p Pointer) MarshalJSON() ([]byte, error) {
+	return json.Marshal(pointerJSON{
+		Type:      "Pointer",
+		ClassName: p.ClassName,
+		ID:        p.ID,
+	})
+}
+
+// UnmarshalJSON decodes the Pointer.
+func (p *Pointer) UnmarshalJSON(b []byte) error {
+	var j pointerJSON
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	if j.Type != "Pointer" {
+		return fmt.Errorf("parse: cannot decode %s as a Pointer", j.Type)
+	}
+	p.ClassName = j.ClassName
+	p.ID = j.ID
+	return nil
+}
+
+// GeoPoint is a latitude and longitude pair.
+type GeoPoint struct {
+	Latitude  float64
+	Longitude float64
+}
+
+type geoPointJSON struct {
+	Type      string  `json:"__type"`
+	Latitude  float64 `json:"latitude"`
+	Longitude float64 `json:"longitude"`
+}
+
+// MarshalJSON encodes the GeoPoint.
+func (g GeoPoint) MarshalJSON() ([]byte, error) {
+	return json.Marshal(geoPointJSON{
+		Type:      "GeoPoint",
+		Latitude:  g.Latitude,
+		Longitude: g.Longitude,
+	})
+}
+
+// UnmarshalJSON decodes the GeoPoint.
+func (g *GeoPoint) UnmarshalJSON(b []byte) error {
+	var j geoPointJSON
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	if j.Type != "GeoPoint" {
+		return fmt.Errorf("parse: cannot decode %s as a GeoPoint", j.Type)
+	}
+	g.Latitude = j.Latitude
+	g.Longitude = j.Longitude
+	return nil
+}
+
+// The layout used by the API for dates.
+const dateLayout = "2006-01-02T15:04:05.000Z"
+
+// Date is a time encoded as a Parse Date.
+type Date time.Time
+
+type dateJSON struct {
+	Type string `json:"__type"`
+	ISO  string `json:"iso"`
+}
+
+// MarshalJSON encodes the Date.
+func (d Date) MarshalJSON() ([]byte, error) {
+	return json.Marshal(dateJSON{
+		Type: "Date",
+		ISO:  time.Time(d).UTC().Format(dateLayout),
+	})
+}
+
+// UnmarshalJSON decodes the Date.
+func (d *Date) UnmarshalJSON(b []byte) error {
+	var j dateJSON
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	if j.Type != "Date" {
+		return fmt.Errorf("parse: cannot decode %s as a Date", j.Type)
+	}
+	t, err := time.Parse(time.RFC3339Nano, j.ISO)
+	if err != nil {
+		return err
+	}
+	*d = Date(t)
+	return nil
+}
+
+// File is a reference to a file stored by the API.
+type File struct {
+	Name string
+	URL  string
+}
+
+type fileJSON struct {
+	Type string `json:"__type"`
+	Name string `json:"name"`
+	URL  string `json:"url,omitempty"`
+}
+
+// MarshalJSON encodes the File.
+func (f File) MarshalJSON() ([]byte, error) {
+	return json.Marshal(fileJSON{Type: "File", Name: f.Name, URL: f.URL})
+}
+
+// UnmarshalJSON decodes the File.
+func (f *File) UnmarshalJSON(b []byte) error {
+	var j fileJSON
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	if j.Type != "File" {
+		return fmt.Errorf("parse: cannot decode %s as a File", j.Type)
+	}
+	f.Name = j.Name
+	f.URL = j.URL
+	return nil
+}
+
+// Relation is a many-to-many relation to objects of a class.
+type Relation struct {
+	ClassName string
+}
+
+type relationJSON struct {
+	Type      string `json:"__type"`
+	ClassName string `json:"className"`
+}
+
+// MarshalJSON encodes the Relation.
+func (r Relation) MarshalJSON() ([]byte, error) {
+	return json.Marshal(relationJSON{Type: "Relation", ClassName: r.ClassName})
+}
+
+// UnmarshalJSON decodes the Relation.
+func (r *Relation) UnmarshalJSON(b []byte) error {
+	var j relationJSON
+	if err := json.Unmarshal(b, &j); err != nil {
+		return err
+	}
+	if j.Type != "Relation" {
+		return fmt.Errorf("parse: cannot decode %s as a Relation", j.Type)
+	}
+	r.ClassName = j.ClassName
+	return nil
+}
@@ -0,0 +1,86 @@
+package parse_test
+
+import (
+	"encoding/json"
+	"regexp"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestPointerJSON(t *testing.T) {
+	t.Parallel()
+	p := parse.Pointer{ClassName: "_User", ID: "xyz"}
+	b := jsonB(t, p)
+	ensure.DeepEqual(t, string(b), `{"__type":"Pointer","className":"_User","objectId":"xyz"}`)
+	var p2 parse.Pointer
+	ensure.Nil(t, json.Unmarshal(b, &p2))
+	ensure.DeepEqual(t, p2, p)
+}
+
+func TestPointerWrongType(t *testing.T) {
+	t.Parallel()
+	var p parse.Pointer
+	err := json.Unmarshal([]byte(`{"__type":"GeoPoint"}`), &p)
+	ensure.Err(t, err, regexp.MustCompile("cannot decode GeoPoint as a Pointer"))
+}
+
+func TestGeoPointJSON(t *testing.T) {
+	t.Parallel()
+	g := parse.GeoPoint{Latitude: 37.5, Longitude: -122.25}
+	b := jsonB(t, g)
+	ensure.DeepEqual(t, string(b), `{"__type":"GeoPoint","latitude":37.5,"longitude":-122.25}`)
+	var g2 parse.GeoPoint
+	ensure.Nil(t, json.Unmarshal(b, &g2))
+	ensure.DeepEqual(t, g2, g)
+}
+
+func TestGeoPointWrongType(t *testing.T) {
+	t.Parallel()
+	var g parse.GeoPoint
+	err := json.Unmarshal([]byte(`{"__type":"Pointer"}`), &g)
+	ensure.Err(t, err, regexp.MustCompile("cannot decode Pointer as a GeoPoint"))
+}
+
+func TestDateJSON(t *testing.T) {
+	t.Parallel()
+	d := parse.Date(time.Date(2015, 6, 16, 1, 2, 3, 4e6, time.UTC))
+	b := jsonB(t, d)
+	ensure.DeepEqual(t, string(b), `{"__type":"Date","iso":"2015-06-16T01:02:03.004Z"}`)
+	var d2 parse.Date
+	ensure.Nil(t, json.Unmarshal(b, &d2))
+	ensure.True(t, time.Time(d2).Equal(time.Time(d)))
+}
+
+func TestDateWrongType(t *testing.T) {
+	t.Parallel()
+	var d parse.Date
+	err := json.Unmarshal([]byte(`{"__type":"Pointer"}`), &d)
+	ensure.Err(t, err, regexp.MustCompile("cannot decode Pointer as a Date"))
+}
+
+func TestFileJSON(t *testing.T) {
+	t.Parallel()
+	f := parse.File{Name: "a.png", URL: "https://files/a.png"}
+	b := jsonB(t, f)
+	ensure.DeepEqual(t, string(b), `{"__type":"File","name":"a.png","url":"https://files/a.png"}`)
+	var f2 parse.File
+	ensure.Nil(t, json.Unmarshal(b, &f2))
+	ensure.DeepEqual(t, f2, f)
+	ensure.Err(t, json.Unmarshal([]byte(`{"__type":"Date"}`), &f2),
+		regexp.MustCompile("cannot decode Date as a File"))
+}
+
+func TestRelationJSON(t *testing.T) {
+	t.Parallel()
+	r := parse.Relation{ClassName: "_User"}
+	b := jsonB(t, r)
+	ensure.DeepEqual(t, string(b), `{"__type":"Relation","className":"_User"}`)
+	var r2 parse.Relation
+	ensure.Nil(t, json.Unmarshal(b, &r2))
+	ensure.DeepEqual(t, r2, r)
+	ensure.Err(t, json.Unmarshal([]byte(`{"__type":"File"}`), &r2),
+		regexp.MustCompile("cannot decode File as a Relation"))
+}
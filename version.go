@@ -0,0 +1,29 @@
+package parse
+
+import (
+	"net/http"
+	"runtime"
+)
+
+// Version of this package, sent in the User-Agent and X-Parse-Client-Version
+// headers.
+const Version = "1.0.0"
+
+const (
+	clientVersionHeader = "X-Parse-Client-Version"
+	osVersionHeader     = "X-Parse-OS-Version"
+	appVersionHeader    = "X-Parse-App-Build-Version"
+)
+
+// setVersionHeaders identifies the package, the platform and the AppVersion,
+// like the other Parse SDKs do. Headers already set are kept.
+func (c *Client) setVersionHeaders(req *http.Request) {
+	set := func(k, v string) {
+		if v != "" && req.Header.Get(k) == "" {
+			req.Header.Set(k, v)
+		}
+	}
+	set(clientVersionHeader, "go"+Version)
+	set(osVersionHeader, runtime.GOOS+"/"+runtime.GOARCH+" "+runtime.Version())
+	set(appVersionHeader, c.AppVersion)
+}
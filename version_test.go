@@ -0,0 +1,46 @@
+package parse_test
+
+import (
+	"net/http"
+	"runtime"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestVersionHeaders(t *testing.T) {
+	t.Parallel()
+	var h http.Header
+	c := &parse.Client{
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			h = r.Header
+			return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+		}),
+	}
+	_, err := c.Get(nil, nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, h.Get("User-Agent"), "go-parse/"+parse.Version)
+	ensure.DeepEqual(t, h.Get("X-Parse-Client-Version"), "go"+parse.Version)
+	ensure.StringContains(t, h.Get("X-Parse-OS-Version"), runtime.GOOS+"/"+runtime.GOARCH)
+	ensure.DeepEqual(t, h.Get("X-Parse-App-Build-Version"), "")
+}
+
+func TestAppVersion(t *testing.T) {
+	t.Parallel()
+	var h http.Header
+	c := &parse.Client{
+		UserAgent:  "yak-worker",
+		AppVersion: "2024.10.1",
+		Header:     http.Header{"X-Parse-Client-Version": {"go-custom"}},
+		Transport: transportFunc(func(r *http.Request) (*http.Response, error) {
+			h = r.Header
+			return jsonResponse(t, http.StatusOK, map[string]string{}), nil
+		}),
+	}
+	_, err := c.Get(nil, nil)
+	ensure.Nil(t, err)
+	ensure.DeepEqual(t, h.Get("User-Agent"), "yak-worker")
+	ensure.DeepEqual(t, h.Get("X-Parse-App-Build-Version"), "2024.10.1")
+	ensure.DeepEqual(t, h.Get("X-Parse-Client-Version"), "go-custom")
+}
@@ -0,0 +1,180 @@
+package parse
+
+import (
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"net/http"
+	"path"
+)
+
+const webhookKeyHeader = "X-Parse-Webhook-Key"
+
+// Trigger names sent by Parse Server.
+const (
+	BeforeSave   = "beforeSave"
+	AfterSave    = "afterSave"
+	BeforeDelete = "beforeDelete"
+	AfterDelete  = "afterDelete"
+)
+
+// WebhookRequest is the payload Parse Server sends to cloud function and
+// trigger webhooks. The objects are left encoded, use json.Unmarshal to decode
+// them into the appropriate types.
+type WebhookRequest struct {
+	Master         bool              `json:"master"`
+	User           json.RawMessage   `json:"user"`
+	InstallationID string            `json:"installationId"`
+	Headers        map[string]string `json:"headers"`
+	IP             string            `json:"ip"`
+
+	// FunctionName and Params are set for cloud functions.
+	FunctionName string          `json:"functionName"`
+	Params       json.RawMessage `json:"params"`
+
+	// TriggerName, Object and Original are set for triggers. Original is only
+	// set for updates.
+	TriggerName string          `json:"triggerName"`
+	Object      json.RawMessage `json:"object"`
+	Original    json.RawMessage `json:"original"`
+
+	// ClassName of the Object.
+	ClassName string `json:"-"`
+}
+
+// A WebhookFunc handles a webhook request. The returned value is sent back as
+// the successful result of the cloud function or trigger, and a returned
+// error is sent back as the error. Use an Error to control the error code. A
+// returned *WebhookResponse is sent back as-is.
+type WebhookFunc func(r *WebhookRequest) (interface{}, error)
+
+type webhookErrorBody struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+// WebhookResponse is the response to a webhook request. Use WebhookSuccess,
+// WebhookSuccessWithChanges or WebhookError to build one.
+type WebhookResponse struct {
+	Success interface{}       `json:"success,omitempty"`
+	Error   *webhookErrorBody `json:"error,omitempty"`
+}
+
+// WebhookSuccess responds with the result of a cloud function, or with the
+// object to save from a beforeSave trigger. For other triggers use true.
+func WebhookSuccess(v interface{}) *WebhookResponse {
+	return &WebhookResponse{Success: v}
+}
+
+// WebhookSuccessWithChanges responds to a beforeSave trigger with only the
+// fields that were modified. The server applies them to the object being
+// saved.
+func WebhookSuccessWithChanges(modified map[string]interface{}) *WebhookResponse {
+	if modified == nil {
+		modified = map[string]interface{}{}
+	}
+	return &WebhookResponse{Success: modified}
+}
+
+// WebhookError responds with the given error code and message, which is
+// returned to the client that called the cloud function or saved the object.
+func WebhookError(code int, message string) *WebhookResponse {
+	return &WebhookResponse{Error: &webhookErrorBody{Code: code, Message: message}}
+}
+
+// Webhooks is an http.Handler that verifies, decodes and dispatches webhook
+// requests to the registered functions. All functions must be registered
+// before serving requests.
+type Webhooks struct {
+	// Key is the webhook key configured on the server. Requests without the
+	// matching key are rejected.
+	Key string
+
+	functions map[string]WebhookFunc
+	triggers  map[string]WebhookFunc
+}
+
+// HandleFunction registers the function to handle the cloud function with the
+// given name.
+func (h *Webhooks) HandleFunction(name string, f WebhookFunc) {
+	if h.functions == nil {
+		h.functions = make(map[string]WebhookFunc)
+	}
+	h.functions[name] = f
+}
+
+// HandleTrigger registers the function to handle the trigger for the given
+// class, such as BeforeSave for "_User".
+func (h *Webhooks) HandleTrigger(trigger, className string, f WebhookFunc) {
+	if h.triggers == nil {
+		h.triggers = make(map[string]WebhookFunc)
+	}
+	h.triggers[trigger+" "+className] = f
+}
+
+func (h *Webhooks) lookup(r *http.Request, wr *WebhookRequest) WebhookFunc {
+	if wr.TriggerName != "" {
+		return h.triggers[wr.TriggerName+" "+wr.ClassName]
+	}
+	// Older servers do not send the function name, in which case the
+	// function is identified by the last path element of the webhook URL.
+	if wr.FunctionName == "" {
+		wr.FunctionName = path.Base(r.URL.Path)
+	}
+	return h.functions[wr.FunctionName]
+}
+
+func writeWebhookResponse(w http.ResponseWriter, code int, v interface{}) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(code)
+	json.NewEncoder(w).Encode(v)
+}
+
+func errorResponse(err error) interface{} {
+	var e *Error
+	if errors.As(err, &e) {
+		return WebhookError(e.Code, e.Message)
+	}
+	return map[string]string{"error": err.Error()}
+}
+
+// ServeHTTP handles a webhook request.
+func (h *Webhooks) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	key := r.Header.Get(webhookKeyHeader)
+	if h.Key == "" || subtle.ConstantTimeCompare([]byte(key), []byte(h.Key)) != 1 {
+		writeWebhookResponse(w, http.StatusUnauthorized, map[string]string{"error": "unauthorized"})
+		return
+	}
+
+	var wr WebhookRequest
+	if err := json.NewDecoder(r.Body).Decode(&wr); err != nil {
+		writeWebhookResponse(w, http.StatusBadRequest, map[string]string{"error": "invalid webhook request"})
+		return
+	}
+	if wr.Object != nil {
+		var o struct {
+			ClassName string `json:"className"`
+		}
+		if err := json.Unmarshal(wr.Object, &o); err != nil {
+			writeWebhookResponse(w, http.StatusBadRequest, map[string]string{"error": "invalid webhook object"})
+			return
+		}
+		wr.ClassName = o.ClassName
+	}
+
+	f := h.lookup(r, &wr)
+	if f == nil {
+		writeWebhookResponse(w, http.StatusNotFound, map[string]string{"error": "no handler for webhook"})
+		return
+	}
+	result, err := f(&wr)
+	if err != nil {
+		writeWebhookResponse(w, http.StatusOK, errorResponse(err))
+		return
+	}
+	if res, ok := result.(*WebhookResponse); ok {
+		writeWebhookResponse(w, http.StatusOK, res)
+		return
+	}
+	writeWebhookResponse(w, http.StatusOK, WebhookSuccess(result))
+}
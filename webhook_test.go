@@ -0,0 +1,169 @@
+package parse_test
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func serveWebhook(t testing.TB, h http.Handler, path, key, body string) (int, map[string]interface{}) {
+	r, err := http.NewRequest("POST", "https://example.com"+path, strings.NewReader(body))
+	ensure.Nil(t, err)
+	if key != "" {
+		r.Header.Set("X-Parse-Webhook-Key", key)
+	}
+	w := httptest.NewRecorder()
+	h.ServeHTTP(w, r)
+	var m map[string]interface{}
+	ensure.Nil(t, json.Unmarshal(w.Body.Bytes(), &m))
+	return w.Code, m
+}
+
+func TestWebhookFunction(t *testing.T) {
+	t.Parallel()
+	h := &parse.Webhooks{Key: "k"}
+	h.HandleFunction("hello", func(r *parse.WebhookRequest) (interface{}, error) {
+		ensure.True(t, r.Master)
+		var params struct {
+			Name string `json:"name"`
+		}
+		ensure.Nil(t, json.Unmarshal(r.Params, &params))
+		return "hello " + params.Name, nil
+	})
+	code, m := serveWebhook(t, h, "/hooks/hello", "k",
+		`{"master":true,"functionName":"hello","params":{"name":"yak"}}`)
+	ensure.DeepEqual(t, code, http.StatusOK)
+	ensure.DeepEqual(t, m, map[string]interface{}{"success": "hello yak"})
+}
+
+func TestWebhookFunctionFromPath(t *testing.T) {
+	t.Parallel()
+	h := &parse.Webhooks{Key: "k"}
+	h.HandleFunction("hello", func(r *parse.WebhookRequest) (interface{}, error) {
+		return true, nil
+	})
+	code, m := serveWebhook(t, h, "/hooks/hello", "k", `{"params":{}}`)
+	ensure.DeepEqual(t, code, http.StatusOK)
+	ensure.DeepEqual(t, m, map[string]interface{}{"success": true})
+}
+
+func TestWebhookTrigger(t *testing.T) {
+	t.Parallel()
+	h := &parse.Webhooks{Key: "k"}
+	h.HandleTrigger(parse.BeforeSave, "Yak", func(r *parse.WebhookRequest) (interface{}, error) {
+		ensure.DeepEqual(t, r.ClassName, "Yak")
+		ensure.DeepEqual(t, r.InstallationID, "i")
+		return nil, &parse.Error{Code: 142, Message: "yaks must have names"}
+	})
+	code, m := serveWebhook(t, h, "/hooks/yak", "k",
+		`{"triggerName":"beforeSave","installationId":"i","object":{"className":"Yak"}}`)
+	ensure.DeepEqual(t, code, http.StatusOK)
+	ensure.DeepEqual(t, m, map[string]interface{}{
+		"error": map[string]interface{}{
+			"code":    float64(142),
+			"message": "yaks must have names",
+		},
+	})
+}
+
+func TestWebhookPlainError(t *testing.T) {
+	t.Parallel()
+	h := &parse.Webhooks{Key: "k"}
+	h.HandleFunction("hello", func(r *parse.WebhookRequest) (interface{}, error) {
+		return nil, errors.New("no yaks")
+	})
+	_, m := serveWebhook(t, h, "/", "k", `{"functionName":"hello"}`)
+	ensure.DeepEqual(t, m, map[string]interface{}{"error": "no yaks"})
+}
+
+func TestWebhookWrappedError(t *testing.T) {
+	t.Parallel()
+	h := &parse.Webhooks{Key: "k"}
+	h.HandleFunction("hello", func(r *parse.WebhookRequest) (interface{}, error) {
+		return nil, fmt.Errorf("saving yak: %w", &parse.Error{Code: 142, Message: "no yaks"})
+	})
+	_, m := serveWebhook(t, h, "/", "k", `{"functionName":"hello"}`)
+	ensure.DeepEqual(t, m, map[string]interface{}{
+		"error": map[string]interface{}{"code": float64(142), "message": "no yaks"},
+	})
+}
+
+func TestWebhookErrors(t *testing.T) {
+	t.Parallel()
+	h := &parse.Webhooks{Key: "k"}
+	cases := []struct {
+		Key  string
+		Body string
+		Code int
+	}{
+		{"", `{}`, http.StatusUnauthorized},
+		{"wrong", `{}`, http.StatusUnauthorized},
+		{"k", `{`, http.StatusBadRequest},
+		{"k", `{"object":[]}`, http.StatusBadRequest},
+		{"k", `{"functionName":"missing"}`, http.StatusNotFound},
+		{"k", `{"triggerName":"afterSave","object":{"className":"Yak"}}`, http.StatusNotFound},
+	}
+	for _, c := range cases {
+		code, m := serveWebhook(t, h, "/", c.Key, c.Body)
+		ensure.DeepEqual(t, code, c.Code, c)
+		ensure.NotNil(t, m["error"], c)
+	}
+}
+
+func TestWebhookNoKeyConfigured(t *testing.T) {
+	t.Parallel()
+	h := &parse.Webhooks{}
+	code, _ := serveWebhook(t, h, "/", "", `{}`)
+	ensure.DeepEqual(t, code, http.StatusUnauthorized)
+}
+
+func TestWebhookResponseJSON(t *testing.T) {
+	t.Parallel()
+	cases := []struct {
+		Response *parse.WebhookResponse
+		Expected string
+	}{
+		{
+			parse.WebhookSuccess(map[string]string{"name": "yak"}),
+			`{"success":{"name":"yak"}}`,
+		},
+		{
+			parse.WebhookSuccess(true),
+			`{"success":true}`,
+		},
+		{
+			parse.WebhookSuccessWithChanges(map[string]interface{}{"count": 1}),
+			`{"success":{"count":1}}`,
+		},
+		{
+			parse.WebhookSuccessWithChanges(nil),
+			`{"success":{}}`,
+		},
+		{
+			parse.WebhookError(142, "invalid yak"),
+			`{"error":{"code":142,"message":"invalid yak"}}`,
+		},
+	}
+	for _, c := range cases {
+		ensure.DeepEqual(t, string(jsonB(t, c.Response)), c.Expected)
+	}
+}
+
+func TestWebhookReturnsResponse(t *testing.T) {
+	t.Parallel()
+	h := &parse.Webhooks{Key: "k"}
+	h.HandleTrigger(parse.BeforeSave, "Yak", func(r *parse.WebhookRequest) (interface{}, error) {
+		return parse.WebhookSuccessWithChanges(map[string]interface{}{"name": "bob"}), nil
+	})
+	_, m := serveWebhook(t, h, "/", "k", `{"triggerName":"beforeSave","object":{"className":"Yak"}}`)
+	ensure.DeepEqual(t, m, map[string]interface{}{
+		"success": map[string]interface{}{"name": "bob"},
+	})
+}
@@ -0,0 +1,113 @@
+package parse
+
+// Where is a set of query constraints. It is used as the where clause of
+// class queries and to target pushes at installations. Constraints are added
+// using the chainable methods:
+//
+//	parse.Where{}.
+//		EqualTo("deviceType", "ios").
+//		ContainsAll("channels", "yaks").
+//		EqualTo("user", parse.Pointer{ClassName: "_User", ID: id})
+//
+// The methods allocate a nil Where, so the zero value can be used as long as
+// the returned Where is kept:
+//
+//	var w parse.Where
+//	if onlyIOS {
+//		w = w.EqualTo("deviceType", "ios")
+//	}
+type Where map[string]interface{}
+
+func (w Where) constraint(key, op string, value interface{}) Where {
+	if w == nil {
+		w = make(Where)
+	}
+	c, ok := w[key].(map[string]interface{})
+	if !ok {
+		c = make(map[string]interface{})
+		w[key] = c
+	}
+	c[op] = value
+	return w
+}
+
+// EqualTo requires the key to equal the value. For array fields it requires
+// the array to contain the value.
+func (w Where) EqualTo(key string, value interface{}) Where {
+	if w == nil {
+		w = make(Where)
+	}
+	w[key] = value
+	return w
+}
+
+// NotEqualTo requires the key to not equal the value.
+func (w Where) NotEqualTo(key string, value interface{}) Where {
+	return w.constraint(key, "$ne", value)
+}
+
+// LessThan requires the key to be less than the value.
+func (w Where) LessThan(key string, value interface{}) Where {
+	return w.constraint(key, "$lt", value)
+}
+
+// LessThanOrEqualTo requires the key to be less than or equal to the value.
+func (w Where) LessThanOrEqualTo(key string, value interface{}) Where {
+	return w.constraint(key, "$lte", value)
+}
+
+// GreaterThan requires the key to be greater than the value.
+func (w Where) GreaterThan(key string, value interface{}) Where {
+	return w.constraint(key, "$gt", value)
+}
+
+// GreaterThanOrEqualTo requires the key to be greater than or equal to the
+// value.
+func (w Where) GreaterThanOrEqualTo(key string, value interface{}) Where {
+	return w.constraint(key, "$gte", value)
+}
+
+// ContainedIn requires the key to equal one of the values.
+func (w Where) ContainedIn(key string, values ...interface{}) Where {
+	return w.constraint(key, "$in", values)
+}
+
+// NotContainedIn requires the key to not equal any of the values.
+func (w Where) NotContainedIn(key string, values ...interface{}) Where {
+	return w.constraint(key, "$nin", values)
+}
+
+// ContainsAll requires the array key to contain all of the values.
+func (w Where) ContainsAll(key string, values ...interface{}) Where {
+	return w.constraint(key, "$all", values)
+}
+
+// Exists requires the key to be set.
+func (w Where) Exists(key string) Where {
+	return w.constraint(key, "$exists", true)
+}
+
+// DoesNotExist requires the key to not be set.
+func (w Where) DoesNotExist(key string) Where {
+	return w.constraint(key, "$exists", false)
+}
+
+// Near requires the GeoPoint key to be near the point, and sorts the results
+// by distance.
+func (w Where) Near(key string, point GeoPoint) Where {
+	return w.constraint(key, "$nearSphere", point)
+}
+
+// WithinKilometers requires the GeoPoint key to be within the given distance
+// of the point.
+func (w Where) WithinKilometers(key string, point GeoPoint, km float64) Where {
+	w = w.constraint(key, "$nearSphere", point)
+	return w.constraint(key, "$maxDistanceInKilometers", km)
+}
+
+// WithinMiles requires the GeoPoint key to be within the given distance of
+// the point.
+func (w Where) WithinMiles(key string, point GeoPoint, miles float64) Where {
+	w = w.constraint(key, "$nearSphere", point)
+	return w.constraint(key, "$maxDistanceInMiles", miles)
+}
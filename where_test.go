@@ -0,0 +1,52 @@
+package parse_test
+
+import (
+	"testing"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestWhere(t *testing.T) {
+	t.Parallel()
+	w := parse.Where{}.
+		EqualTo("deviceType", "ios").
+		NotEqualTo("appVersion", "1.0").
+		GreaterThan("badge", 1).
+		LessThanOrEqualTo("badge", 10).
+		ContainedIn("timeZone", "UTC", "America/Los_Angeles").
+		ContainsAll("channels", "yaks").
+		Exists("deviceToken").
+		EqualTo("user", parse.Pointer{ClassName: "_User", ID: "xyz"})
+	ensure.DeepEqual(t, string(jsonB(t, w)), `{`+
+		`"appVersion":{"$ne":"1.0"},`+
+		`"badge":{"$gt":1,"$lte":10},`+
+		`"channels":{"$all":["yaks"]},`+
+		`"deviceToken":{"$exists":true},`+
+		`"deviceType":"ios",`+
+		`"timeZone":{"$in":["UTC","America/Los_Angeles"]},`+
+		`"user":{"__type":"Pointer","className":"_User","objectId":"xyz"}`+
+		`}`)
+}
+
+func TestWhereGeo(t *testing.T) {
+	t.Parallel()
+	p := parse.GeoPoint{Latitude: 1, Longitude: 2}
+	w := parse.Where{}.WithinMiles("location", p, 5)
+	ensure.DeepEqual(t, string(jsonB(t, w)), `{"location":{`+
+		`"$maxDistanceInMiles":5,`+
+		`"$nearSphere":{"__type":"GeoPoint","latitude":1,"longitude":2}}}`)
+}
+
+func TestWhereNil(t *testing.T) {
+	t.Parallel()
+	var w parse.Where
+	w = w.EqualTo("deviceType", "ios")
+	ensure.DeepEqual(t, string(jsonB(t, w)), `{"deviceType":"ios"}`)
+
+	var g parse.Where
+	g = g.WithinKilometers("location", parse.GeoPoint{Latitude: 1, Longitude: 2}, 5)
+	ensure.DeepEqual(t, string(jsonB(t, g)), `{"location":{`+
+		`"$maxDistanceInKilometers":5,`+
+		`"$nearSphere":{"__type":"GeoPoint","latitude":1,"longitude":2}}}`)
+}
@@ -0,0 +1,65 @@
+package parse
+
+import "sync"
+
+// Pool runs functions on a bounded number of goroutines. Go blocks while all
+// of them are busy, so producers are slowed down to the pace of the work
+// instead of piling up goroutines. It is used by the bulk operations, and can
+// be used for jobs of your own. The zero value runs one function at a time.
+//
+// Once a function returns an error, Go stops running new functions and Wait
+// returns the error.
+type Pool struct {
+	// Size is the number of functions running at once. Defaults to 1.
+	Size int
+
+	once sync.Once
+	sem  chan struct{}
+	wg   sync.WaitGroup
+	mu   sync.Mutex
+	err  error
+}
+
+// Go runs fn on a goroutine once one of the Size slots is free. It does nothing
+// after a function has failed.
+func (p *Pool) Go(fn func() error) {
+	p.once.Do(func() {
+		size := p.Size
+		if size < 1 {
+			size = 1
+		}
+		p.sem = make(chan struct{}, size)
+	})
+	p.sem <- struct{}{}
+	if p.Err() != nil {
+		<-p.sem
+		return
+	}
+	p.wg.Add(1)
+	go func() {
+		defer func() {
+			<-p.sem
+			p.wg.Done()
+		}()
+		if err := fn(); err != nil {
+			p.mu.Lock()
+			if p.err == nil {
+				p.err = err
+			}
+			p.mu.Unlock()
+		}
+	}()
+}
+
+// Err returns the error of the first function that failed, if any.
+func (p *Pool) Err() error {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return p.err
+}
+
+// Wait waits for the running functions and returns the first error.
+func (p *Pool) Wait() error {
+	p.wg.Wait()
+	return p.Err()
+}
@@ -0,0 +1,51 @@
+package parse_test
+
+import (
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"github.com/facebookgo/ensure"
+	"github.com/facebookgo/parse"
+)
+
+func TestPoolBounded(t *testing.T) {
+	t.Parallel()
+	p := parse.Pool{Size: 3}
+	var running, max int32
+	for n := 0; n < 20; n++ {
+		p.Go(func() error {
+			r := atomic.AddInt32(&running, 1)
+			for {
+				m := atomic.LoadInt32(&max)
+				if r <= m || atomic.CompareAndSwapInt32(&max, m, r) {
+					break
+				}
+			}
+			time.Sleep(time.Millisecond)
+			atomic.AddInt32(&running, -1)
+			return nil
+		})
+	}
+	ensure.Nil(t, p.Wait())
+	ensure.True(t, max <= 3, max)
+}
+
+func TestPoolError(t *testing.T) {
+	t.Parallel()
+	var p parse.Pool
+	ran := 0
+	for n := 0; n < 5; n++ {
+		n := n
+		p.Go(func() error {
+			ran++
+			if n == 1 {
+				return errors.New("failed")
+			}
+			return nil
+		})
+	}
+	ensure.DeepEqual(t, p.Wait().Error(), "failed")
+	ensure.DeepEqual(t, ran, 2)
+}